@@ -0,0 +1,16 @@
+package cluster
+
+import (
+	"github.com/spf13/cobra"
+)
+
+func NewClusterCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:          "cluster",
+		Short:        "Find clusters known by OCM",
+		SilenceUsage: true,
+	}
+
+	cmd.AddCommand(newSearchClusterCmd())
+	return cmd
+}
@@ -0,0 +1,94 @@
+package cluster
+
+import (
+	"fmt"
+
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+	"github.com/spf13/cobra"
+
+	"github.com/openshift/backplane-cli/pkg/utils"
+)
+
+// clusterSearchResult is the JSON representation of a cluster matching the search
+type clusterSearchResult struct {
+	ID         string `json:"id"`
+	ExternalID string `json:"external_id"`
+	Name       string `json:"name"`
+	State      string `json:"state"`
+	Version    string `json:"version"`
+	Product    string `json:"product"`
+	Region     string `json:"region"`
+}
+
+func newSearchClusterCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "search <CLUSTERID|EXTERNAL_ID|CLUSTER_NAME|CLUSTER_NAME_SEARCH>",
+		Short: "Search clusters matching the given pattern",
+		Long: `Search the clusters whose ID, external ID or name matches the given pattern.
+The pattern supports the same syntax as the login command, use % as a wildcard in the cluster name.
+The search never prompts, so it can be used in scripts to find the cluster to pass to other commands.`,
+		Example:      " backplane cluster search %test%\n backplane cluster search %test% -o json",
+		Args:         cobra.ExactArgs(1),
+		SilenceUsage: true,
+		RunE:         runSearchCluster,
+	}
+
+	cmd.Flags().StringP(
+		"output",
+		"o",
+		"table",
+		"Format the output of the search. One of table|json",
+	)
+
+	return cmd
+}
+
+func runSearchCluster(cmd *cobra.Command, argv []string) error {
+	output, err := cmd.Flags().GetString("output")
+	if err != nil {
+		return err
+	}
+	if output != "table" && output != "json" {
+		return fmt.Errorf("unsupported output format %s, supported formats are table|json", output)
+	}
+
+	clusters, err := utils.DefaultOCMInterface.SearchClusters(argv[0])
+	if err != nil {
+		return err
+	}
+
+	results := make([]clusterSearchResult, 0, len(clusters))
+	for _, c := range clusters {
+		results = append(results, newClusterSearchResult(c))
+	}
+
+	if output == "json" {
+		return utils.RenderJSONBytes(results)
+	}
+
+	headers := []string{"ID", "EXTERNAL ID", "NAME", "STATE", "VERSION", "PRODUCT", "REGION"}
+	rows := make([][]string, 0, len(results))
+	for _, r := range results {
+		rows = append(rows, []string{r.ID, r.ExternalID, r.Name, r.State, r.Version, r.Product, r.Region})
+	}
+	utils.RenderTable(headers, rows)
+
+	return nil
+}
+
+func newClusterSearchResult(c *cmv1.Cluster) clusterSearchResult {
+	version := c.OpenshiftVersion()
+	if version == "" {
+		version = c.Version().RawID()
+	}
+
+	return clusterSearchResult{
+		ID:         c.ID(),
+		ExternalID: c.ExternalID(),
+		Name:       c.Name(),
+		State:      string(c.State()),
+		Version:    version,
+		Product:    c.Product().ID(),
+		Region:     c.Region().ID(),
+	}
+}
@@ -0,0 +1,113 @@
+package cluster
+
+import (
+	"errors"
+
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+	"github.com/spf13/cobra"
+
+	"github.com/openshift/backplane-cli/pkg/utils"
+	mocks2 "github.com/openshift/backplane-cli/pkg/utils/mocks"
+)
+
+var _ = Describe("cluster search command", func() {
+
+	var (
+		mockCtrl         *gomock.Controller
+		mockOcmInterface *mocks2.MockOCMInterface
+
+		clusters []*cmv1.Cluster
+
+		sut *cobra.Command
+	)
+
+	BeforeEach(func() {
+		mockCtrl = gomock.NewController(GinkgoT())
+		mockOcmInterface = mocks2.NewMockOCMInterface(mockCtrl)
+		utils.DefaultOCMInterface = mockOcmInterface
+
+		cluster1, _ := cmv1.NewCluster().
+			ID("id1").
+			ExternalID("external1").
+			Name("test-1").
+			State(cmv1.ClusterStateReady).
+			OpenshiftVersion("4.14.1").
+			Product(cmv1.NewProduct().ID("rosa")).
+			Region(cmv1.NewCloudRegion().ID("us-east-1")).
+			Build()
+		cluster2, _ := cmv1.NewCluster().
+			ID("id2").
+			Name("test-2").
+			State(cmv1.ClusterStateHibernating).
+			Version(cmv1.NewVersion().RawID("4.13.2")).
+			Build()
+		clusters = []*cmv1.Cluster{cluster1, cluster2}
+
+		sut = NewClusterCmd()
+	})
+
+	AfterEach(func() {
+		mockCtrl.Finish()
+	})
+
+	Context("search clusters", func() {
+		It("should print the matching clusters as a table", func() {
+			mockOcmInterface.EXPECT().SearchClusters("test%").Return(clusters, nil)
+
+			sut.SetArgs([]string{"search", "test%"})
+			err := sut.Execute()
+
+			Expect(err).To(BeNil())
+		})
+
+		It("should print the matching clusters as json", func() {
+			mockOcmInterface.EXPECT().SearchClusters("test%").Return(clusters, nil)
+
+			sut.SetArgs([]string{"search", "test%", "-o", "json"})
+			err := sut.Execute()
+
+			Expect(err).To(BeNil())
+		})
+
+		It("should fail for an unsupported output format", func() {
+			sut.SetArgs([]string{"search", "test%", "-o", "yaml"})
+			err := sut.Execute()
+
+			Expect(err).NotTo(BeNil())
+		})
+
+		It("should fail when the search fails", func() {
+			mockOcmInterface.EXPECT().SearchClusters("test%").Return(nil, errors.New("no cluster"))
+
+			sut.SetArgs([]string{"search", "test%"})
+			err := sut.Execute()
+
+			Expect(err).NotTo(BeNil())
+		})
+	})
+
+	Context("search result", func() {
+		It("should contain the cluster details", func() {
+			result := newClusterSearchResult(clusters[0])
+
+			Expect(result).To(Equal(clusterSearchResult{
+				ID:         "id1",
+				ExternalID: "external1",
+				Name:       "test-1",
+				State:      "ready",
+				Version:    "4.14.1",
+				Product:    "rosa",
+				Region:     "us-east-1",
+			}))
+		})
+
+		It("should fall back to the version ID", func() {
+			result := newClusterSearchResult(clusters[1])
+
+			Expect(result.Version).To(Equal("4.13.2"))
+		})
+	})
+})
@@ -1,13 +1,12 @@
 package config
 
 import (
+	"fmt"
+	"strings"
+
 	"github.com/spf13/cobra"
-)
 
-const (
-	ProxyURLConfigVar = "proxy-url"
-	URLConfigVar      = "url"
-	SessionConfigVar  = "session-dir"
+	"github.com/openshift/backplane-cli/pkg/cli/config"
 )
 
 func NewConfigCmd() *cobra.Command {
@@ -18,14 +17,43 @@ func NewConfigCmd() *cobra.Command {
 The location of the configuration file is gleaned from ~/.config/backplane/config.json or the 'BACKPLANE_CONFIG' environment variable if set.
 
 The following variables are supported:
-url         Backplane API URL
-proxy-url   Squid proxy URL
-session-dir Backplane CLI session directory
+` + describeVariables() + `
+The configuration file can hold named profiles, eg. one per OCM environment, whose variables override the top level ones.
+Variables are set in a profile with the --profile flag, which creates the profile if needed.
+
+The profile in use is the one given by the --profile flag, then the one pinned by "config use-profile",
+then the one whose ocm-url matches the URL of the current ocm session. Without any, the top level variables apply.
 `,
 		SilenceUsage: true,
 	}
 
 	cmd.AddCommand(newGetCmd())
 	cmd.AddCommand(newSetCmd())
+	cmd.AddCommand(newUnsetCmd())
+	cmd.AddCommand(newListCmd())
+	cmd.AddCommand(newValidateCmd())
+	cmd.AddCommand(newUseProfileCmd())
+	cmd.AddCommand(newListProfilesCmd())
 	return cmd
 }
+
+// describeVariables returns a line per configuration variable of the schema
+func describeVariables() string {
+	width := 0
+	for _, key := range config.Keys {
+		width = max(width, len(key.Name))
+	}
+
+	var b strings.Builder
+	for _, key := range config.Keys {
+		description := key.Description
+		if key.Required {
+			description += " (required)"
+		}
+		if key.ProfileOnly {
+			description += " (profiles only)"
+		}
+		fmt.Fprintf(&b, "%-*s %s\n", width, key.Name, description)
+	}
+	return b.String()
+}
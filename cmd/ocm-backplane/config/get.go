@@ -1,45 +1,114 @@
 package config
 
 import (
+	"encoding/json"
 	"fmt"
+	"io"
+	"strings"
 
 	"github.com/spf13/cobra"
+	"sigs.k8s.io/yaml"
 
 	"github.com/openshift/backplane-cli/pkg/cli/config"
 )
 
+const (
+	getOutputText = "text"
+	getOutputJSON = "json"
+	getOutputYAML = "yaml"
+)
+
+var getArgs struct {
+	output string
+}
+
 func newGetCmd() *cobra.Command {
 	cmd := &cobra.Command{
-		Use:          "get",
+		Use:          "get <variable|all>",
 		Short:        "Get Backplane CLI configuration variables",
-		Example:      "ocm backplane config get url",
+		Example:      "ocm backplane config get url\nocm backplane config get all -o json",
 		SilenceUsage: true,
 		Args:         cobra.ExactArgs(1),
 		RunE:         getConfig,
 	}
+
+	cmd.Flags().StringVarP(
+		&getArgs.output,
+		"output",
+		"o",
+		getOutputText,
+		"Format of the output, one of text|json|yaml",
+	)
 	return cmd
 }
 
 func getConfig(cmd *cobra.Command, args []string) error {
-	config, err := config.GetBackplaneConfiguration()
+	switch getArgs.output {
+	case getOutputText, getOutputJSON, getOutputYAML:
+	default:
+		return fmt.Errorf("unsupported output format %s, supported formats are text|json|yaml", getArgs.output)
+	}
+
+	bpConfig, err := config.GetBackplaneConfiguration()
 	if err != nil {
 		return err
 	}
 
-	switch args[0] {
-	case URLConfigVar:
-		fmt.Printf("%s: %s\n", URLConfigVar, config.URL)
-	case ProxyURLConfigVar:
-		fmt.Printf("%s: %s\n", ProxyURLConfigVar, config.ProxyURL)
-	case SessionConfigVar:
-		fmt.Printf("%s: %s\n", SessionConfigVar, config.SessionDirectory)
-	case "all":
-		fmt.Printf("%s: %s\n", URLConfigVar, config.URL)
-		fmt.Printf("%s: %s\n", ProxyURLConfigVar, config.ProxyURL)
-		fmt.Printf("%s: %s\n", SessionConfigVar, config.SessionDirectory)
-	default:
-		return fmt.Errorf("supported config variables are %s, %s & %s", URLConfigVar, ProxyURLConfigVar, SessionConfigVar)
+	names := []string{args[0]}
+	if args[0] == "all" {
+		names = []string{}
+		for _, key := range config.Keys {
+			// Profile variables only make sense with a profile in use
+			if key.ProfileOnly && bpConfig.Profile == "" {
+				continue
+			}
+			names = append(names, key.Name)
+		}
 	}
 
+	values := map[string]interface{}{}
+	for _, name := range names {
+		value, err := bpConfig.Value(name)
+		if err != nil {
+			return err
+		}
+		values[name] = value
+	}
+
+	return renderValues(cmd.OutOrStdout(), getArgs.output, names, values, bpConfig)
+}
+
+// renderValues writes the values of the configuration variables in the given format
+func renderValues(w io.Writer, output string, names []string, values map[string]interface{}, bpConfig config.BackplaneConfiguration) error {
+	switch output {
+	case getOutputJSON:
+		out, err := json.MarshalIndent(values, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(w, string(out))
+	case getOutputYAML:
+		out, err := yaml.Marshal(values)
+		if err != nil {
+			return err
+		}
+		fmt.Fprint(w, string(out))
+	default:
+		for _, name := range names {
+			fmt.Fprintf(w, "%s: %s\n", name, formatValue(values[name]))
+		}
+		if len(names) > 1 && bpConfig.Profile != "" {
+			fmt.Fprintf(w, "profile: %s (selected by %s)\n", bpConfig.Profile, bpConfig.ProfileSource)
+		}
+	}
 	return nil
 }
+
+// formatValue formats the value of a configuration variable for the text output,
+// the lists are comma separated as when they are set
+func formatValue(value interface{}) string {
+	if list, ok := value.([]string); ok {
+		return strings.Join(list, ",")
+	}
+	return fmt.Sprintf("%v", value)
+}
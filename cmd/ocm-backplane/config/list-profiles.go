@@ -0,0 +1,51 @@
+package config
+
+import (
+	"fmt"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	"github.com/openshift/backplane-cli/pkg/cli/config"
+)
+
+func newListProfilesCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:          "list-profiles",
+		Short:        "List the configuration profiles",
+		Long:         "List the configuration profiles. The profile in use is marked with '*', with the reason it was selected.",
+		Example:      "ocm backplane config list-profiles",
+		SilenceUsage: true,
+		Args:         cobra.NoArgs,
+		RunE:         listProfiles,
+	}
+	return cmd
+}
+
+func listProfiles(cmd *cobra.Command, args []string) error {
+	profiles, err := config.GetProfiles()
+	if err != nil {
+		return err
+	}
+
+	if len(profiles) == 0 {
+		fmt.Fprintln(cmd.OutOrStdout(), "No profile configured, set one with \"ocm backplane config set --profile <name> <variable> <value>\"")
+		return nil
+	}
+
+	bpConfig, err := config.GetBackplaneConfiguration()
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 3, ' ', 0)
+	fmt.Fprintln(w, "CURRENT\tNAME\tOCM URL\tURL\tPROXY URL")
+	for _, profile := range profiles {
+		current := ""
+		if profile.Name == bpConfig.Profile {
+			current = fmt.Sprintf("* (%s)", bpConfig.ProfileSource)
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", current, profile.Name, profile.OCMURL, profile.URL, profile.ProxyURL)
+	}
+	return w.Flush()
+}
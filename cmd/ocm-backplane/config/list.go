@@ -0,0 +1,47 @@
+package config
+
+import (
+	"fmt"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	"github.com/openshift/backplane-cli/pkg/cli/config"
+)
+
+func newListCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List the Backplane CLI configuration variables",
+		Long: `List every configuration variable with its type, its value and where the value comes from:
+env for an environment variable, profile for the profile in use, file for the configuration file, default otherwise.`,
+		Example:      "ocm backplane config list",
+		SilenceUsage: true,
+		Args:         cobra.NoArgs,
+		RunE:         listConfig,
+	}
+	return cmd
+}
+
+func listConfig(cmd *cobra.Command, args []string) error {
+	bpConfig, err := config.GetBackplaneConfiguration()
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 3, ' ', 0)
+	fmt.Fprintln(w, "VARIABLE\tTYPE\tVALUE\tSOURCE")
+	for _, key := range config.Keys {
+		value, err := bpConfig.Value(key.Name)
+		if err != nil {
+			return err
+		}
+
+		source := bpConfig.Sources[key.Name]
+		if source == config.ValueSourceProfile {
+			source = fmt.Sprintf("%s %s", source, bpConfig.Profile)
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", key.Name, key.Type, formatValue(value), source)
+	}
+	return w.Flush()
+}
@@ -6,7 +6,6 @@ import (
 	"path"
 
 	"github.com/spf13/cobra"
-	"github.com/spf13/viper"
 	"golang.org/x/term"
 	"gopkg.in/AlecAivazis/survey.v1"
 
@@ -17,7 +16,7 @@ func newSetCmd() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:          "set",
 		Short:        "Set Backplane CLI configuration variables",
-		Example:      "ocm backplane config set url https://example.com",
+		Example:      "ocm backplane config set url https://example.com\nocm backplane config set --profile stage ocm-url staging",
 		SilenceUsage: true,
 		Args:         cobra.ExactArgs(2),
 		RunE:         setConfig,
@@ -27,23 +26,25 @@ func newSetCmd() *cobra.Command {
 }
 
 func setConfig(cmd *cobra.Command, args []string) error {
-	bpConfig := &config.BackplaneConfiguration{}
+	key, err := config.LookupKey(args[0])
+	if err != nil {
+		return err
+	}
 
-	// Retrieve default Backplane CLI config path, $HOME/.config/backplane/config.json
-	configPath, err := config.GetConfigFilePath()
+	value, err := key.Parse(args[1])
 	if err != nil {
 		return err
 	}
 
-	if _, err = os.Stat(configPath); err == nil {
-		viper.SetConfigFile(configPath)
-		if err := viper.ReadInConfig(); err != nil {
-			return err
-		}
+	profile := config.GetSelectedProfile()
+	if key.ProfileOnly && profile == "" {
+		return fmt.Errorf("%s is a profile variable, set it with --profile", key.Name)
+	}
 
-		bpConfig.URL = viper.GetString("url")
-		bpConfig.ProxyURL = viper.GetString("proxy-url")
-		bpConfig.SessionDirectory = viper.GetString("session-dir")
+	// Retrieve default Backplane CLI config path, $HOME/.config/backplane/config.json
+	configPath, err := config.GetConfigFilePath()
+	if err != nil {
+		return err
 	}
 
 	// create config directory if it doesn't exist
@@ -72,25 +73,14 @@ func setConfig(cmd *cobra.Command, args []string) error {
 		}
 	}
 
-	switch args[0] {
-	case URLConfigVar:
-		bpConfig.URL = args[1]
-	case ProxyURLConfigVar:
-		bpConfig.ProxyURL = args[1]
-	case SessionConfigVar:
-		bpConfig.SessionDirectory = args[1]
-	default:
-		return fmt.Errorf("supported config variables are %s, %s & %s", URLConfigVar, ProxyURLConfigVar, SessionConfigVar)
+	// With --profile, the variable is set in the profile, which is created if needed
+	if err := config.SetValue(profile, key.Name, value); err != nil {
+		return err
 	}
 
-	viper.SetConfigType("json")
-	viper.Set(URLConfigVar, bpConfig.URL)
-	viper.Set(ProxyURLConfigVar, bpConfig.ProxyURL)
-	viper.Set(SessionConfigVar, bpConfig.SessionDirectory)
-
-	err = viper.WriteConfigAs(configPath)
-	if err != nil {
-		return err
+	if profile != "" {
+		fmt.Printf("Profile %s updated in configuration file %s\n", profile, configPath)
+		return nil
 	}
 	fmt.Println("Configuration file updated at " + configPath)
 
@@ -0,0 +1,45 @@
+package config
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/openshift/backplane-cli/pkg/cli/config"
+)
+
+func newUnsetCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:          "unset <variable>",
+		Short:        "Unset Backplane CLI configuration variables",
+		Long:         "Remove a variable from the configuration file, or from the profile given by --profile, so its default value applies.",
+		Example:      "ocm backplane config unset proxy-url\nocm backplane config unset --profile stage proxy-url",
+		SilenceUsage: true,
+		Args:         cobra.ExactArgs(1),
+		RunE:         unsetConfig,
+	}
+	return cmd
+}
+
+func unsetConfig(cmd *cobra.Command, args []string) error {
+	key, err := config.LookupKey(args[0])
+	if err != nil {
+		return err
+	}
+
+	profile := config.GetSelectedProfile()
+	if key.ProfileOnly && profile == "" {
+		return fmt.Errorf("%s is a profile variable, unset it with --profile", key.Name)
+	}
+
+	if err := config.UnsetValue(profile, key.Name); err != nil {
+		return err
+	}
+
+	if profile != "" {
+		fmt.Fprintf(cmd.OutOrStdout(), "%s unset in profile %s\n", key.Name, profile)
+		return nil
+	}
+	fmt.Fprintf(cmd.OutOrStdout(), "%s unset\n", key.Name)
+	return nil
+}
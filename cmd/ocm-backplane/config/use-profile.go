@@ -0,0 +1,59 @@
+package config
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/openshift/backplane-cli/pkg/cli/config"
+)
+
+var useProfileArgs struct {
+	clear bool
+}
+
+func newUseProfileCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "use-profile [PROFILE]",
+		Short: "Set the configuration profile to use",
+		Long: `Pin the configuration profile used when no --profile flag is given.
+With --clear, the profile is selected again by matching its ocm-url with the URL of the current ocm session.`,
+		Example:      "ocm backplane config use-profile stage\nocm backplane config use-profile --clear",
+		SilenceUsage: true,
+		Args:         cobra.MaximumNArgs(1),
+		RunE:         useProfile,
+	}
+
+	cmd.Flags().BoolVar(
+		&useProfileArgs.clear,
+		"clear",
+		false,
+		"Unpin the profile, to select it by the URL of the current ocm session.",
+	)
+	return cmd
+}
+
+func useProfile(cmd *cobra.Command, args []string) error {
+	if useProfileArgs.clear == (len(args) == 1) {
+		return fmt.Errorf("expected either a profile name or --clear")
+	}
+
+	name := ""
+	if len(args) == 1 {
+		name = args[0]
+		if err := config.ValidateProfileName(name); err != nil {
+			return err
+		}
+	}
+
+	if err := config.SetCurrentProfile(name); err != nil {
+		return err
+	}
+
+	if name == "" {
+		fmt.Fprintln(cmd.OutOrStdout(), "Profile unpinned, the profile matching the ocm session URL is used")
+		return nil
+	}
+	fmt.Fprintf(cmd.OutOrStdout(), "Using profile %s\n", name)
+	return nil
+}
@@ -0,0 +1,44 @@
+package config
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/openshift/backplane-cli/pkg/cli/config"
+)
+
+func newValidateCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:          "validate",
+		Short:        "Validate the Backplane CLI configuration file",
+		Long:         "Validate the configuration file and its profiles, reporting unknown variables, malformed values and missing required values.",
+		Example:      "ocm backplane config validate",
+		SilenceUsage: true,
+		Args:         cobra.NoArgs,
+		RunE:         validateConfig,
+	}
+	return cmd
+}
+
+func validateConfig(cmd *cobra.Command, args []string) error {
+	configPath, err := config.GetConfigFilePath()
+	if err != nil {
+		return err
+	}
+
+	problems, err := config.ValidateConfigFile()
+	if err != nil {
+		return err
+	}
+
+	if len(problems) == 0 {
+		fmt.Fprintf(cmd.OutOrStdout(), "Configuration file %s is valid\n", configPath)
+		return nil
+	}
+
+	for _, problem := range problems {
+		fmt.Fprintln(cmd.OutOrStdout(), problem)
+	}
+	return fmt.Errorf("configuration file %s has %d problem(s)", configPath, len(problems))
+}
@@ -133,6 +133,44 @@ func init() {
 	)
 }
 
+// FindContainerEngine picks the container engine to run the console with.
+// If the user specifies one by -c, it checks it exists, otherwise it finds an available engine in PATH.
+func FindContainerEngine(engineFlag string) (string, error) {
+	containerEngine := ""
+	if len(engineFlag) > 0 {
+		for _, ce := range validContainerEngines {
+			if strings.EqualFold(engineFlag, ce) {
+				containerEngine = ce
+			}
+		}
+		if len(containerEngine) == 0 {
+			return "", fmt.Errorf("container engine can only be one of %s", strings.Join(validContainerEngines, "|"))
+		}
+		if _, err := exec.LookPath(containerEngine); err != nil {
+			return "", fmt.Errorf("can't find %s in PATH", containerEngine)
+		}
+	} else {
+		// Get the container engine via env vars
+		engine, hasEngine := os.LookupEnv("CONTAINER_ENGINE")
+
+		if hasEngine {
+			containerEngine = engine
+		} else {
+			// Fetch container engine via path
+			for _, ce := range validContainerEngines {
+				if _, err := exec.LookPath(ce); err == nil {
+					containerEngine = ce
+					break
+				}
+			}
+			if len(containerEngine) == 0 {
+				return "", fmt.Errorf("can't find %s in PATH, please install one of the container engines", strings.Join(validContainerEngines, "|"))
+			}
+		}
+	}
+	return containerEngine, nil
+}
+
 func checkContainerExists(containerName string, containerEngine string) (exists bool, err error) {
 	existCheckArgs := []string{
 		"container",
@@ -222,40 +260,9 @@ func runConsole(cmd *cobra.Command, argv []string) (err error) {
 			return fmt.Errorf("unable to parse boolean value from environment variable %s", EnvBrowserDefault)
 		}
 	}
-	// Pick a container engine
-	// If user specify by -c, check if it exists.
-	// Otherwise find an available engine in PATH
-	containerEngine := ""
-	if len(consoleArgs.containerEngine) > 0 {
-		for _, ce := range validContainerEngines {
-			if strings.EqualFold(consoleArgs.containerEngine, ce) {
-				containerEngine = ce
-			}
-		}
-		if len(containerEngine) == 0 {
-			return fmt.Errorf("container engine can only be one of %s", strings.Join(validContainerEngines, "|"))
-		}
-		if _, err := exec.LookPath(containerEngine); err != nil {
-			return fmt.Errorf("can't find %s in PATH", containerEngine)
-		}
-	} else {
-		// Get the container engine via env vars
-		engine, hasEngine := os.LookupEnv("CONTAINER_ENGINE")
-
-		if hasEngine {
-			containerEngine = engine
-		} else {
-			// Fetch container engine via path
-			for _, ce := range validContainerEngines {
-				if _, err := exec.LookPath(ce); err == nil {
-					containerEngine = ce
-					break
-				}
-			}
-			if len(containerEngine) == 0 {
-				return fmt.Errorf("can't find %s in PATH, please install one of the container engines", strings.Join(validContainerEngines, "|"))
-			}
-		}
+	containerEngine, err := FindContainerEngine(consoleArgs.containerEngine)
+	if err != nil {
+		return err
 	}
 	logger.Infof("Using container engine %s\n", containerEngine)
 
@@ -364,7 +371,7 @@ func runConsole(cmd *cobra.Command, argv []string) (err error) {
 	}
 
 	// Set proxy URL to the container
-	proxyURL, err := getProxyURL()
+	proxyURL, err := getProxyURL(apiURL)
 	if err != nil {
 		return err
 	}
@@ -509,17 +516,15 @@ func replaceConsoleURL(original string) (string, error) {
 	return original, nil
 }
 
-// Get the proxy url
-func getProxyURL() (proxyURL string, err error) {
+// Get the proxy url reaching the cluster API, the one chosen by the proxy auto-config file when no proxy is set
+func getProxyURL(apiURL string) (proxyURL string, err error) {
 	bpConfig, err := config.GetBackplaneConfiguration()
 
 	if err != nil {
 		return "", err
 	}
 
-	proxyURL = bpConfig.ProxyURL
-
-	return proxyURL, nil
+	return bpConfig.ResolveProxyURL(bpConfig.SelectProxyURL(), apiURL)
 }
 
 // getImageFromCluster get the image from the console deployment
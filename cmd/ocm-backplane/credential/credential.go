@@ -0,0 +1,37 @@
+package credential
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/openshift/backplane-cli/pkg/login"
+	"github.com/openshift/backplane-cli/pkg/utils"
+)
+
+// CredentialCmd represents the credential command
+var CredentialCmd = &cobra.Command{
+	Use:   "credential",
+	Short: "Print an ExecCredential containing a fresh OCM token",
+	Long: `Credential command is a client-go credential plugin. It is called by
+oc/kubectl through the exec entry written to kubeconfig by the login
+command, and prints a client.authentication.k8s.io/v1 ExecCredential
+holding the current OCM access token.`,
+	Args:         cobra.ExactArgs(0),
+	RunE:         runCredential,
+	Hidden:       true,
+	SilenceUsage: true,
+}
+
+func runCredential(cmd *cobra.Command, argv []string) error {
+	accessToken, err := utils.DefaultOCMInterface.GetOCMAccessToken()
+	if err != nil {
+		return fmt.Errorf("failed to get OCM access token: %v", err)
+	}
+
+	credential := login.NewExecCredential(*accessToken)
+
+	return json.NewEncoder(os.Stdout).Encode(credential)
+}
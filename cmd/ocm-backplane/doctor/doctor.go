@@ -0,0 +1,314 @@
+package doctor
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os/exec"
+	"runtime"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/openshift/backplane-cli/cmd/ocm-backplane/console"
+	"github.com/openshift/backplane-cli/pkg/cli/config"
+	"github.com/openshift/backplane-cli/pkg/info"
+	"github.com/openshift/backplane-cli/pkg/utils"
+)
+
+const (
+	statusPass = "pass"
+	statusWarn = "warn"
+	statusFail = "fail"
+
+	doctorOutputText = "text"
+	doctorOutputJSON = "json"
+)
+
+var (
+	doctorArgs struct {
+		output string
+	}
+
+	// For mocking
+	getOCMAccessToken         = func() (*string, error) { return utils.DefaultOCMInterface.GetOCMAccessToken() }
+	lookPath                  = exec.LookPath
+	findContainerEngine       = console.FindContainerEngine
+	readKubeconfigRaw         = utils.ReadKubeconfigRaw
+	getLatestBackplaneVersion = utils.GetLatestBackplaneVersion
+	getBackplaneConfiguration = config.GetBackplaneConfiguration
+	validateConfigFile        = config.ValidateConfigFile
+	checkAPIConnection        = func(bpConfig config.BackplaneConfiguration) error { return bpConfig.CheckAPIConnection() }
+)
+
+// checkResult is the outcome of a diagnostic check, with a remediation hint unless it passed
+type checkResult struct {
+	Name    string `json:"name"`
+	Status  string `json:"status"`
+	Message string `json:"message"`
+	Hint    string `json:"hint,omitempty"`
+}
+
+// report is the outcome of every check, with the environment they ran in
+type report struct {
+	Version string        `json:"version"`
+	OS      string        `json:"os"`
+	Arch    string        `json:"arch"`
+	Checks  []checkResult `json:"checks"`
+}
+
+func NewDoctorCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "doctor",
+		Short: "Diagnose the backplane-cli setup",
+		Long: `Run diagnostic checks of the backplane-cli setup: the OCM token, the backplane configuration,
+the connection to the backplane API through the VPN or proxy, the oc and container engine binaries,
+the kubeconfig and the backplane-cli version. Each check passes, warns or fails, with a hint to fix it.
+Use -o json to attach the report to a support ticket.`,
+		Example:      " backplane doctor\n backplane doctor -o json",
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		RunE:         runDoctor,
+	}
+
+	cmd.Flags().StringVarP(
+		&doctorArgs.output,
+		"output",
+		"o",
+		doctorOutputText,
+		"Format of the output, one of text|json",
+	)
+	return cmd
+}
+
+func runDoctor(cmd *cobra.Command, argv []string) error {
+	if doctorArgs.output != doctorOutputText && doctorArgs.output != doctorOutputJSON {
+		return fmt.Errorf("unsupported output format %s, supported formats are text|json", doctorArgs.output)
+	}
+
+	ctx := cmd.Context()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	r := runChecks(ctx)
+	if err := renderReport(cmd.OutOrStdout(), doctorArgs.output, r); err != nil {
+		return err
+	}
+
+	failed := 0
+	for _, check := range r.Checks {
+		if check.Status == statusFail {
+			failed++
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d check(s) failed", failed)
+	}
+	return nil
+}
+
+// runChecks runs every check, in the order a new user would fix them
+func runChecks(ctx context.Context) report {
+	r := report{
+		Version: info.Version,
+		OS:      runtime.GOOS,
+		Arch:    runtime.GOARCH,
+	}
+
+	bpConfig, configCheck := checkConfiguration()
+	r.Checks = append(r.Checks,
+		checkOCMToken(),
+		configCheck,
+		checkBackplaneAPI(bpConfig),
+		checkOC(),
+		checkContainerEngine(),
+		checkKubeconfig(),
+		checkVersion(ctx),
+	)
+	return r
+}
+
+func checkOCMToken() checkResult {
+	result := checkResult{Name: "ocm-token"}
+	if _, err := getOCMAccessToken(); err != nil {
+		result.Status = statusFail
+		result.Message = fmt.Sprintf("unable to get the OCM access token: %v", err)
+		result.Hint = "Log into OCM with \"ocm login --use-auth-code\", or \"ocm login --token <token>\""
+		return result
+	}
+
+	result.Status = statusPass
+	result.Message = "OCM access token found"
+	return result
+}
+
+// checkConfiguration checks the backplane configuration, and returns it for the API connection check
+func checkConfiguration() (*config.BackplaneConfiguration, checkResult) {
+	result := checkResult{Name: "backplane-config"}
+
+	bpConfig, err := getBackplaneConfiguration()
+	if err != nil {
+		result.Status = statusFail
+		result.Message = fmt.Sprintf("unable to read the backplane configuration: %v", err)
+		result.Hint = "Run \"ocm backplane config validate\" and fix the reported problems"
+		return nil, result
+	}
+
+	if bpConfig.URL == "" {
+		result.Status = statusFail
+		result.Message = "the backplane URL isn't configured"
+		result.Hint = "Set it with \"ocm backplane config set url <backplane API URL>\", or the BACKPLANE_URL environment variable"
+		return nil, result
+	}
+
+	problems, err := validateConfigFile()
+	if err != nil {
+		result.Status = statusWarn
+		result.Message = fmt.Sprintf("unable to validate the configuration file: %v", err)
+		return &bpConfig, result
+	}
+	if len(problems) > 0 {
+		result.Status = statusWarn
+		result.Message = "the configuration file has problems: " + strings.Join(problems, "; ")
+		result.Hint = "Fix them with \"ocm backplane config set\" and \"ocm backplane config unset\""
+		return &bpConfig, result
+	}
+
+	result.Status = statusPass
+	result.Message = fmt.Sprintf("backplane URL is %s", bpConfig.URL)
+	if bpConfig.Profile != "" {
+		result.Message += fmt.Sprintf(", from profile %s", bpConfig.Profile)
+	}
+	return &bpConfig, result
+}
+
+func checkBackplaneAPI(bpConfig *config.BackplaneConfiguration) checkResult {
+	result := checkResult{Name: "backplane-api"}
+	if bpConfig == nil {
+		result.Status = statusWarn
+		result.Message = "skipped, the backplane configuration is broken"
+		result.Hint = "Fix the backplane-config check first"
+		return result
+	}
+
+	// The proxy selection is cached, the connection check uses the same proxy
+	proxyURL := bpConfig.SelectProxyURL()
+	if err := checkAPIConnection(*bpConfig); err != nil {
+		result.Status = statusFail
+		result.Message = fmt.Sprintf("unable to reach the backplane API %s: %v", bpConfig.URL, err)
+		if proxyURL == "" {
+			result.Hint = "Connect to the VPN, or set a proxy with \"ocm backplane config set proxy-url <proxy URL>\""
+		} else {
+			result.Hint = fmt.Sprintf("Connect to the VPN, and check the proxy %s is reachable", proxyURL)
+		}
+		return result
+	}
+
+	result.Status = statusPass
+	result.Message = fmt.Sprintf("backplane API %s is reachable", bpConfig.URL)
+	if proxyURL != "" {
+		result.Message += fmt.Sprintf(" through the proxy %s", proxyURL)
+	}
+	return result
+}
+
+func checkOC() checkResult {
+	result := checkResult{Name: "oc"}
+	path, err := lookPath("oc")
+	if err != nil {
+		result.Status = statusFail
+		result.Message = "oc not found in PATH"
+		result.Hint = "Install the OpenShift CLI from https://mirror.openshift.com/pub/openshift-v4/clients/ocp/latest/"
+		return result
+	}
+
+	result.Status = statusPass
+	result.Message = fmt.Sprintf("oc found at %s", path)
+	return result
+}
+
+func checkContainerEngine() checkResult {
+	result := checkResult{Name: "container-engine"}
+	engine, err := findContainerEngine("")
+	if err != nil {
+		// Only the console command needs a container engine
+		result.Status = statusWarn
+		result.Message = err.Error()
+		result.Hint = "Install podman or docker to use \"ocm backplane console\""
+		return result
+	}
+
+	result.Status = statusPass
+	result.Message = fmt.Sprintf("using %s", engine)
+	return result
+}
+
+func checkKubeconfig() checkResult {
+	result := checkResult{Name: "kubeconfig"}
+	rc, err := readKubeconfigRaw()
+	if err != nil {
+		result.Status = statusFail
+		result.Message = fmt.Sprintf("unable to read the kubeconfig: %v", err)
+		result.Hint = "Fix or move the kubeconfig file, backups of the previous versions are kept as <kubeconfig>.backup.<n>"
+		return result
+	}
+
+	if rc.CurrentContext == "" {
+		result.Status = statusPass
+		result.Message = "kubeconfig is readable, with no current context"
+		return result
+	}
+	if _, ok := rc.Contexts[rc.CurrentContext]; !ok {
+		result.Status = statusWarn
+		result.Message = fmt.Sprintf("the current context %s doesn't exist", rc.CurrentContext)
+		result.Hint = "Log into a cluster with \"ocm backplane login <cluster>\""
+		return result
+	}
+
+	result.Status = statusPass
+	result.Message = fmt.Sprintf("kubeconfig is readable, current context is %s", rc.CurrentContext)
+	return result
+}
+
+func checkVersion(ctx context.Context) checkResult {
+	result := checkResult{Name: "version"}
+	latestVersion, err := getLatestBackplaneVersion(ctx)
+	if err != nil {
+		result.Status = statusWarn
+		result.Message = fmt.Sprintf("unable to fetch the latest version: %v", err)
+		return result
+	}
+
+	if latestVersion != info.Version {
+		result.Status = statusWarn
+		result.Message = fmt.Sprintf("version %s is outdated, the latest version is %s", info.Version, latestVersion)
+		result.Hint = "Run \"ocm backplane upgrade\""
+		return result
+	}
+
+	result.Status = statusPass
+	result.Message = fmt.Sprintf("version %s is up-to-date", info.Version)
+	return result
+}
+
+// renderReport writes the report in the given format
+func renderReport(w io.Writer, output string, r report) error {
+	if output == doctorOutputJSON {
+		out, err := json.MarshalIndent(r, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(w, string(out))
+		return nil
+	}
+
+	for _, check := range r.Checks {
+		fmt.Fprintf(w, "[%s] %s: %s\n", strings.ToUpper(check.Status), check.Name, check.Message)
+		if check.Hint != "" {
+			fmt.Fprintf(w, "       %s\n", check.Hint)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,153 @@
+package doctor
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"k8s.io/client-go/tools/clientcmd/api"
+
+	"github.com/openshift/backplane-cli/pkg/cli/config"
+	"github.com/openshift/backplane-cli/pkg/info"
+)
+
+// findCheck returns the result of the named check of the report
+func findCheck(r report, name string) checkResult {
+	for _, check := range r.Checks {
+		if check.Name == name {
+			return check
+		}
+	}
+	Fail("check " + name + " not found")
+	return checkResult{}
+}
+
+var _ = Describe("doctor command", func() {
+
+	var (
+		token     = "token"
+		tokenErr  error
+		bpConfig  config.BackplaneConfiguration
+		apiErr    error
+		kubeRaw   api.Config
+		latestErr error
+	)
+
+	BeforeEach(func() {
+		tokenErr = nil
+		bpConfig = config.BackplaneConfiguration{URL: "https://api.backplane.example.com"}
+		apiErr = nil
+		kubeRaw = api.Config{
+			CurrentContext: "default/cluster/user",
+			Contexts:       map[string]*api.Context{"default/cluster/user": {}},
+		}
+		latestErr = nil
+
+		getOCMAccessToken = func() (*string, error) {
+			if tokenErr != nil {
+				return nil, tokenErr
+			}
+			return &token, nil
+		}
+		lookPath = func(file string) (string, error) { return "/usr/bin/" + file, nil }
+		findContainerEngine = func(string) (string, error) { return "podman", nil }
+		readKubeconfigRaw = func() (api.Config, error) { return kubeRaw, nil }
+		getLatestBackplaneVersion = func(context.Context) (string, error) { return info.Version, latestErr }
+		getBackplaneConfiguration = func() (config.BackplaneConfiguration, error) { return bpConfig, nil }
+		validateConfigFile = func() ([]string, error) { return nil, nil }
+		checkAPIConnection = func(config.BackplaneConfiguration) error { return apiErr }
+	})
+
+	Context("when the setup is complete", func() {
+		It("passes every check", func() {
+			r := runChecks(context.Background())
+			Expect(r.Checks).To(HaveLen(7))
+			for _, check := range r.Checks {
+				Expect(check.Status).To(Equal(statusPass), check.Name)
+				Expect(check.Hint).To(BeEmpty())
+			}
+		})
+	})
+
+	Context("when the setup is broken", func() {
+		It("fails without OCM token", func() {
+			tokenErr = errors.New("not logged in")
+
+			check := findCheck(runChecks(context.Background()), "ocm-token")
+			Expect(check.Status).To(Equal(statusFail))
+			Expect(check.Hint).To(ContainSubstring("ocm login"))
+		})
+
+		It("fails without backplane URL and skips the API check", func() {
+			bpConfig.URL = ""
+			checkAPIConnection = func(config.BackplaneConfiguration) error {
+				Fail("the API connection shouldn't be checked")
+				return nil
+			}
+
+			r := runChecks(context.Background())
+			Expect(findCheck(r, "backplane-config").Status).To(Equal(statusFail))
+			Expect(findCheck(r, "backplane-api").Status).To(Equal(statusWarn))
+		})
+
+		It("hints the VPN and proxy when the API is unreachable", func() {
+			apiErr = errors.New("connection refused")
+
+			check := findCheck(runChecks(context.Background()), "backplane-api")
+			Expect(check.Status).To(Equal(statusFail))
+			Expect(check.Hint).To(ContainSubstring("proxy-url"))
+		})
+
+		It("fails without oc and warns without container engine", func() {
+			lookPath = func(file string) (string, error) { return "", errors.New("not found") }
+			findContainerEngine = func(string) (string, error) { return "", errors.New("can't find podman|docker in PATH") }
+
+			r := runChecks(context.Background())
+			Expect(findCheck(r, "oc").Status).To(Equal(statusFail))
+			Expect(findCheck(r, "container-engine").Status).To(Equal(statusWarn))
+		})
+
+		It("fails with a broken kubeconfig", func() {
+			readKubeconfigRaw = func() (api.Config, error) { return api.Config{}, errors.New("yaml: line 3") }
+
+			Expect(findCheck(runChecks(context.Background()), "kubeconfig").Status).To(Equal(statusFail))
+		})
+
+		It("warns when the version is outdated", func() {
+			getLatestBackplaneVersion = func(context.Context) (string, error) { return "999.0.0", nil }
+
+			check := findCheck(runChecks(context.Background()), "version")
+			Expect(check.Status).To(Equal(statusWarn))
+			Expect(check.Hint).To(ContainSubstring("ocm backplane upgrade"))
+		})
+	})
+
+	Context("when printing the report", func() {
+		It("prints JSON", func() {
+			tokenErr = errors.New("not logged in")
+
+			out := &bytes.Buffer{}
+			Expect(renderReport(out, doctorOutputJSON, runChecks(context.Background()))).To(Succeed())
+
+			r := report{}
+			Expect(json.Unmarshal(out.Bytes(), &r)).To(Succeed())
+			Expect(r.Version).To(Equal(info.Version))
+			Expect(findCheck(r, "ocm-token").Status).To(Equal(statusFail))
+		})
+
+		It("fails the command when a check fails", func() {
+			tokenErr = errors.New("not logged in")
+
+			cmd := NewDoctorCmd()
+			out := &bytes.Buffer{}
+			cmd.SetOut(out)
+			cmd.SetArgs([]string{})
+			err := cmd.Execute()
+			Expect(err).To(MatchError("1 check(s) failed"))
+			Expect(out.String()).To(ContainSubstring("[FAIL] ocm-token"))
+		})
+	})
+})
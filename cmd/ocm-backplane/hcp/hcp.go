@@ -0,0 +1,16 @@
+package hcp
+
+import (
+	"github.com/spf13/cobra"
+)
+
+func NewHCPCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:          "hcp",
+		Short:        "Inspect the hosted control plane of HyperShift clusters",
+		SilenceUsage: true,
+	}
+
+	cmd.AddCommand(newInfoCmd())
+	return cmd
+}
@@ -0,0 +1,13 @@
+package hcp
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+func TestHCP(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "HCP Test Suite")
+}
@@ -0,0 +1,200 @@
+package hcp
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/yaml"
+
+	"github.com/openshift/backplane-cli/cmd/ocm-backplane/login"
+	"github.com/openshift/backplane-cli/pkg/cli/config"
+	"github.com/openshift/backplane-cli/pkg/utils"
+)
+
+var (
+	hostedClusterGVR = schema.GroupVersionResource{Group: "hypershift.openshift.io", Version: "v1beta1", Resource: "hostedclusters"}
+	nodePoolGVR      = schema.GroupVersionResource{Group: "hypershift.openshift.io", Version: "v1beta1", Resource: "nodepools"}
+
+	// getRestConfig and newDynamicClient are overridden in tests
+	getRestConfig    = login.GetRestConfig
+	newDynamicClient = func(cfg *rest.Config) (dynamic.Interface, error) {
+		return dynamic.NewForConfig(cfg)
+	}
+)
+
+// hcpInfo is the JSON representation of the hosted control plane of a cluster
+type hcpInfo struct {
+	ClusterID             string                   `json:"cluster_id"`
+	ClusterName           string                   `json:"cluster_name"`
+	ManagementClusterID   string                   `json:"management_cluster_id"`
+	ManagementClusterName string                   `json:"management_cluster_name"`
+	Namespaces            utils.HCPNamespaces      `json:"namespaces"`
+	HostedCluster         map[string]interface{}   `json:"hosted_cluster"`
+	NodePools             []map[string]interface{} `json:"node_pools"`
+}
+
+func newInfoCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "info [CLUSTERID|EXTERNAL_ID|CLUSTER_NAME|CLUSTER_NAME_SEARCH]",
+		Short: "Show the hosted control plane of a hosted cluster",
+		Long: `Print the namespaces of the hosted control plane on the management cluster,
+the HostedCluster object and the NodePools of the given hosted cluster.
+The cluster defaults to the one of the current kubeconfig context.`,
+		Example:      " backplane hcp info <CLUSTERID>\n backplane hcp info <CLUSTERID> -o json",
+		Args:         cobra.MaximumNArgs(1),
+		SilenceUsage: true,
+		RunE:         runInfo,
+	}
+
+	cmd.Flags().StringP(
+		"output",
+		"o",
+		"text",
+		"Format the output. One of text|json",
+	)
+
+	return cmd
+}
+
+func runInfo(cmd *cobra.Command, argv []string) error {
+	output, err := cmd.Flags().GetString("output")
+	if err != nil {
+		return err
+	}
+	if output != "text" && output != "json" {
+		return fmt.Errorf("unsupported output format %s, supported formats are text|json", output)
+	}
+
+	bpCluster, err := utils.DefaultClusterUtils.GetBackplaneCluster(argv...)
+	if err != nil {
+		return err
+	}
+
+	info, err := getHCPInfo(bpCluster.ClusterID)
+	if err != nil {
+		return err
+	}
+
+	if output == "json" {
+		return utils.RenderJSONBytes(info)
+	}
+
+	return printHCPInfo(os.Stdout, info)
+}
+
+// getHCPInfo finds the hosted control plane of the given cluster on its management cluster
+func getHCPInfo(clusterID string) (*hcpInfo, error) {
+	cluster, err := utils.DefaultOCMInterface.GetClusterInfoByID(clusterID)
+	if err != nil {
+		return nil, err
+	}
+	if !cluster.Hypershift().Enabled() {
+		return nil, fmt.Errorf("cluster %s is not a hosted cluster", cluster.Name())
+	}
+
+	ocmURL, err := utils.DefaultOCMInterface.GetOCMEnvironmentURL()
+	if err != nil {
+		return nil, err
+	}
+	env, err := utils.GetOCMEnvironmentName(ocmURL)
+	if err != nil {
+		return nil, err
+	}
+
+	mcID, mcName, err := utils.DefaultOCMInterface.GetManagingCluster(cluster.ID())
+	if err != nil {
+		return nil, err
+	}
+
+	info := &hcpInfo{
+		ClusterID:             cluster.ID(),
+		ClusterName:           cluster.Name(),
+		ManagementClusterID:   mcID,
+		ManagementClusterName: mcName,
+		Namespaces:            utils.GetHCPNamespaces(env, cluster.ID(), cluster.Name()),
+	}
+
+	bpConfig, err := config.GetBackplaneConfiguration()
+	if err != nil {
+		return nil, err
+	}
+	cfg, err := getRestConfig(bpConfig, mcID)
+	if err != nil {
+		return nil, err
+	}
+	client, err := newDynamicClient(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx := context.Background()
+	hostedCluster, err := client.Resource(hostedClusterGVR).Namespace(info.Namespaces.HostedCluster).Get(ctx, cluster.Name(), metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the HostedCluster of %s: %v", cluster.Name(), err)
+	}
+	info.HostedCluster = hostedCluster.Object
+
+	nodePools, err := client.Resource(nodePoolGVR).Namespace(info.Namespaces.HostedCluster).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list the NodePools of %s: %v", cluster.Name(), err)
+	}
+	info.NodePools = []map[string]interface{}{}
+	for _, nodePool := range nodePools.Items {
+		// The namespace can host the NodePools of several HostedClusters
+		if name, _, _ := unstructured.NestedString(nodePool.Object, "spec", "clusterName"); name != cluster.Name() {
+			continue
+		}
+		info.NodePools = append(info.NodePools, nodePool.Object)
+	}
+
+	return info, nil
+}
+
+// printHCPInfo prints the namespaces, the HostedCluster as YAML and a summary of the NodePools
+func printHCPInfo(w io.Writer, info *hcpInfo) error {
+	fmt.Fprintf(w, "Cluster:                      %s (%s)\n", info.ClusterName, info.ClusterID)
+	fmt.Fprintf(w, "Management cluster:           %s (%s)\n", info.ManagementClusterName, info.ManagementClusterID)
+	fmt.Fprintf(w, "HostedCluster namespace:      %s\n", info.Namespaces.HostedCluster)
+	fmt.Fprintf(w, "HostedControlPlane namespace: %s\n", info.Namespaces.HostedControlPlane)
+
+	hostedCluster, err := yaml.Marshal(info.HostedCluster)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(w, "\nHostedCluster:\n%s\n", hostedCluster)
+
+	fmt.Fprintln(w, "NodePools:")
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "NAME\tDESIRED\tCURRENT\tVERSION\tINSTANCE TYPE")
+	for _, nodePool := range info.NodePools {
+		name, _, _ := unstructured.NestedString(nodePool, "metadata", "name")
+		version, _, _ := unstructured.NestedString(nodePool, "status", "version")
+		instanceType, _, _ := unstructured.NestedString(nodePool, "spec", "platform", "aws", "instanceType")
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n",
+			name,
+			nestedCount(nodePool, "spec", "replicas"),
+			nestedCount(nodePool, "status", "replicas"),
+			version,
+			instanceType,
+		)
+	}
+	return tw.Flush()
+}
+
+// nestedCount returns the given replica count, or - when it is not set, e.g. with autoscaling
+func nestedCount(obj map[string]interface{}, fields ...string) string {
+	count, found, err := unstructured.NestedInt64(obj, fields...)
+	if err != nil || !found {
+		return "-"
+	}
+	return fmt.Sprint(count)
+}
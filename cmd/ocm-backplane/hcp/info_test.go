@@ -0,0 +1,152 @@
+package hcp
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/rest"
+
+	"github.com/openshift/backplane-cli/pkg/cli/config"
+	"github.com/openshift/backplane-cli/pkg/info"
+	"github.com/openshift/backplane-cli/pkg/utils"
+	mocks2 "github.com/openshift/backplane-cli/pkg/utils/mocks"
+)
+
+func newHypershiftObject(kind, namespace, name string, spec map[string]interface{}) *unstructured.Unstructured {
+	return &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "hypershift.openshift.io/v1beta1",
+		"kind":       kind,
+		"metadata": map[string]interface{}{
+			"namespace": namespace,
+			"name":      name,
+		},
+		"spec": spec,
+	}}
+}
+
+var _ = Describe("hcp info command", func() {
+
+	var (
+		configDir string
+
+		mockCtrl         *gomock.Controller
+		mockOcmInterface *mocks2.MockOCMInterface
+
+		hostedCluster *cmv1.Cluster
+		fakeClient    *dynamicfake.FakeDynamicClient
+	)
+
+	BeforeEach(func() {
+		mockCtrl = gomock.NewController(GinkgoT())
+		mockOcmInterface = mocks2.NewMockOCMInterface(mockCtrl)
+		utils.DefaultOCMInterface = mockOcmInterface
+
+		var err error
+		configDir, err = os.MkdirTemp("", "backplane")
+		Expect(err).To(BeNil())
+		os.Setenv(info.BackplaneConfigPathEnvName, filepath.Join(configDir, "config.json"))
+		os.Setenv(info.BackplaneURLEnvName, "https://api.backplane.example.com")
+
+		hostedCluster, _ = cmv1.NewCluster().ID("hcp123").Name("my-hcp").
+			Hypershift(cmv1.NewHypershift().Enabled(true)).
+			Build()
+
+		scheme := runtime.NewScheme()
+		fakeClient = dynamicfake.NewSimpleDynamicClientWithCustomListKinds(scheme,
+			map[schema.GroupVersionResource]string{
+				hostedClusterGVR: "HostedClusterList",
+				nodePoolGVR:      "NodePoolList",
+			},
+			newHypershiftObject("HostedCluster", "ocm-staging-hcp123", "my-hcp", map[string]interface{}{
+				"release": map[string]interface{}{"image": "quay.io/openshift-release-dev/ocp-release:4.14.0"},
+			}),
+			newHypershiftObject("NodePool", "ocm-staging-hcp123", "my-hcp-workers", map[string]interface{}{
+				"clusterName": "my-hcp",
+				"replicas":    int64(2),
+			}),
+			newHypershiftObject("NodePool", "ocm-staging-hcp123", "other-workers", map[string]interface{}{
+				"clusterName": "other",
+				"replicas":    int64(3),
+			}),
+		)
+
+		getRestConfig = func(bp config.BackplaneConfiguration, clusterID string) (*rest.Config, error) {
+			Expect(clusterID).To(Equal("mc123"))
+			return &rest.Config{}, nil
+		}
+		newDynamicClient = func(cfg *rest.Config) (dynamic.Interface, error) {
+			return fakeClient, nil
+		}
+	})
+
+	AfterEach(func() {
+		os.Unsetenv(info.BackplaneConfigPathEnvName)
+		os.Unsetenv(info.BackplaneURLEnvName)
+		os.RemoveAll(configDir)
+		mockCtrl.Finish()
+	})
+
+	It("should find the hosted control plane of a hosted cluster", func() {
+		mockOcmInterface.EXPECT().GetClusterInfoByID("hcp123").Return(hostedCluster, nil)
+		mockOcmInterface.EXPECT().GetOCMEnvironmentURL().Return("https://api.stage.openshift.com", nil)
+		mockOcmInterface.EXPECT().GetManagingCluster("hcp123").Return("mc123", "my-mc", nil)
+
+		hcp, err := getHCPInfo("hcp123")
+		Expect(err).To(BeNil())
+
+		Expect(hcp.ManagementClusterID).To(Equal("mc123"))
+		Expect(hcp.Namespaces.HostedCluster).To(Equal("ocm-staging-hcp123"))
+		Expect(hcp.Namespaces.HostedControlPlane).To(Equal("ocm-staging-hcp123-my-hcp"))
+		Expect(hcp.HostedCluster["metadata"]).To(HaveKeyWithValue("name", "my-hcp"))
+		Expect(hcp.NodePools).To(HaveLen(1))
+		Expect(hcp.NodePools[0]["metadata"]).To(HaveKeyWithValue("name", "my-hcp-workers"))
+
+		out := &bytes.Buffer{}
+		Expect(printHCPInfo(out, hcp)).To(Succeed())
+		Expect(out.String()).To(ContainSubstring("HostedControlPlane namespace: ocm-staging-hcp123-my-hcp"))
+		Expect(out.String()).To(ContainSubstring("quay.io/openshift-release-dev/ocp-release:4.14.0"))
+		Expect(out.String()).To(MatchRegexp(`my-hcp-workers\s+2\s+-`))
+		Expect(out.String()).NotTo(ContainSubstring("other-workers"))
+	})
+
+	It("should fail when the cluster is not a hosted cluster", func() {
+		classicCluster, _ := cmv1.NewCluster().ID("classic123").Name("classic").Build()
+		mockOcmInterface.EXPECT().GetClusterInfoByID("classic123").Return(classicCluster, nil)
+
+		_, err := getHCPInfo("classic123")
+		Expect(err).To(MatchError("cluster classic is not a hosted cluster"))
+	})
+
+	It("should fail when the management cluster can't be found", func() {
+		mockOcmInterface.EXPECT().GetClusterInfoByID("hcp123").Return(hostedCluster, nil)
+		mockOcmInterface.EXPECT().GetOCMEnvironmentURL().Return("https://api.stage.openshift.com", nil)
+		mockOcmInterface.EXPECT().GetManagingCluster("hcp123").Return("", "", errors.New("no management cluster"))
+
+		_, err := getHCPInfo("hcp123")
+		Expect(err).To(MatchError("no management cluster"))
+	})
+
+	It("should fail when the HostedCluster doesn't exist", func() {
+		otherCluster, _ := cmv1.NewCluster().ID("hcp456").Name("missing").
+			Hypershift(cmv1.NewHypershift().Enabled(true)).
+			Build()
+		mockOcmInterface.EXPECT().GetClusterInfoByID("hcp456").Return(otherCluster, nil)
+		mockOcmInterface.EXPECT().GetOCMEnvironmentURL().Return("https://api.stage.openshift.com", nil)
+		mockOcmInterface.EXPECT().GetManagingCluster("hcp456").Return("mc123", "my-mc", nil)
+
+		_, err := getHCPInfo("hcp456")
+		Expect(err).NotTo(BeNil())
+		Expect(err.Error()).To(ContainSubstring("failed to get the HostedCluster of missing"))
+	})
+})
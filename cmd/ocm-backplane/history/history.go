@@ -0,0 +1,73 @@
+package history
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/openshift/backplane-cli/pkg/login"
+	"github.com/openshift/backplane-cli/pkg/utils"
+)
+
+var (
+	historyArgs struct {
+		limit int
+	}
+
+	// HistoryCmd represents the history command
+	HistoryCmd = &cobra.Command{
+		Use:   "history",
+		Short: "List the recently logged in clusters",
+		Long: `History command lists the clusters recently logged into with backplane, the most recent first.
+Use 'login -' to go back to the previous cluster, or 'login --recent N' to choose one of them.`,
+		Example:      " backplane history\n backplane history -n 20",
+		Args:         cobra.ExactArgs(0),
+		RunE:         runHistory,
+		SilenceUsage: true,
+	}
+)
+
+func init() {
+	flags := HistoryCmd.Flags()
+
+	flags.IntVarP(
+		&historyArgs.limit,
+		"limit",
+		"n",
+		10,
+		"Number of clusters to list.",
+	)
+}
+
+func runHistory(cmd *cobra.Command, argv []string) error {
+	history, err := login.ReadHistory()
+	if err != nil {
+		return err
+	}
+
+	if len(history) == 0 {
+		fmt.Println("No cluster in the login history")
+		return nil
+	}
+
+	if historyArgs.limit > 0 && historyArgs.limit < len(history) {
+		history = history[:historyArgs.limit]
+	}
+
+	headers := []string{"#", "ID", "NAME", "MODE", "LAST LOGIN"}
+	rows := make([][]string, 0, len(history))
+	for i, e := range history {
+		rows = append(rows, []string{
+			strconv.Itoa(i + 1),
+			e.ClusterID,
+			e.ClusterName,
+			e.Mode,
+			e.Timestamp.Local().Format(time.RFC3339),
+		})
+	}
+	utils.RenderTable(headers, rows)
+
+	return nil
+}
@@ -5,14 +5,13 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
-	"net/url"
 	"os"
 	"strings"
 
-	"github.com/golang-jwt/jwt/v4"
 	logger "github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
-	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"github.com/spf13/pflag"
+	"gopkg.in/AlecAivazis/survey.v1"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/clientcmd/api"
 
@@ -20,6 +19,7 @@ import (
 
 	"github.com/openshift/backplane-cli/pkg/cli/config"
 	"github.com/openshift/backplane-cli/pkg/cli/globalflags"
+	"github.com/openshift/backplane-cli/pkg/info"
 	"github.com/openshift/backplane-cli/pkg/login"
 	"github.com/openshift/backplane-cli/pkg/utils"
 )
@@ -31,20 +31,29 @@ var (
 	args struct {
 		multiCluster   bool
 		kubeConfigPath string
+		fromFile       string
+		parallel       int
+		namespace      string
+		recent         int
+		force          bool
+		output         string
 	}
 
 	globalOpts = &globalflags.GlobalOptions{}
 
+	// namespaceFlag tells whether the namespace was given
+	namespaceFlag *pflag.Flag
+
 	// LoginCmd represents the login command
 	LoginCmd = &cobra.Command{
-		Use:   "login <CLUSTERID|EXTERNAL_ID|CLUSTER_NAME|CLUSTER_NAME_SEARCH>",
+		Use:   "login <CLUSTERID|EXTERNAL_ID|CLUSTER_NAME|CLUSTER_NAME_SEARCH> [<CLUSTERID|EXTERNAL_ID|CLUSTER_NAME|CLUSTER_NAME_SEARCH>...]",
 		Short: "Login to a target cluster",
 		Long: `Running login command will send a request to backplane api
 		using OCM token. The backplane api will return a proxy url for
 		target cluster. The url will be written to kubeconfig, so we can
 		run oc command later to operate the target cluster.`,
-		Example:      " backplane login <id>\n backplane login %test%\n backplane login <external_id>",
-		Args:         cobra.ExactArgs(1),
+		Example:      " backplane login <id>\n backplane login %test%\n backplane login <external_id>\n backplane login -\n backplane login --recent 5\n backplane login <id1> <id2> <id3> --multi\n backplane login --from-file clusters.txt --multi",
+		Args:         cobra.ArbitraryArgs,
 		RunE:         runLogin,
 		SilenceUsage: true,
 	}
@@ -70,6 +79,52 @@ func init() {
 		"Save kube configuration in the specific path when login to multi clusters.",
 	)
 
+	flags.StringVarP(
+		&args.namespace,
+		"namespace",
+		"n",
+		"default",
+		"The namespace of the backplane context.",
+	)
+
+	namespaceFlag = flags.Lookup("namespace")
+
+	flags.IntVar(
+		&args.recent,
+		"recent",
+		0,
+		"Choose the cluster to login to among the given number of recently logged in clusters.",
+	)
+
+	flags.BoolVar(
+		&args.force,
+		"force",
+		false,
+		"Login to the cluster even if it is not ready.",
+	)
+
+	flags.StringVarP(
+		&args.output,
+		"output",
+		"o",
+		loginOutputText,
+		"Format the result of the login. One of text|json|yaml|env",
+	)
+
+	flags.StringVar(
+		&args.fromFile,
+		"from-file",
+		"",
+		"Login to the clusters listed in the given file, one cluster key per line. Requires --multi flag.",
+	)
+
+	flags.IntVar(
+		&args.parallel,
+		"parallel",
+		defaultLoginParallelism,
+		"Maximum number of concurrent logins when login to several clusters.",
+	)
+
 }
 
 func runLogin(cmd *cobra.Command, argv []string) (err error) {
@@ -77,8 +132,34 @@ func runLogin(cmd *cobra.Command, argv []string) (err error) {
 
 	utils.CheckBackplaneVersion(cmd)
 
+	isMultiLogin := len(argv) > 1 || args.fromFile != ""
+	if err := validateLoginOutput(args.output, isMultiLogin); err != nil {
+		return err
+	}
+
+	// Login to several clusters at once
+	if isMultiLogin {
+		return runMultiLogin(argv)
+	}
+
 	// Get The cluster ID
-	if len(argv) == 1 {
+	if len(argv) == 1 && argv[0] == "-" {
+		// if - given, go back to the previously logged in cluster
+		previous, err := login.GetPreviousHistoryEntry()
+		if err != nil {
+			return err
+		}
+		clusterKey = previous.ClusterID
+		logger.WithField("Search Key", clusterKey).Debugln("Finding previous cluster")
+	} else if args.recent > 0 {
+		// if --recent given, let the user choose one of the recently logged in clusters
+		recent, err := chooseRecentCluster(args.recent)
+		if err != nil {
+			return err
+		}
+		clusterKey = recent.ClusterID
+		logger.WithField("Search Key", clusterKey).Debugln("Finding recent cluster")
+	} else if len(argv) == 1 {
 		// if explicitly one cluster key given, use it to log in.
 		clusterKey = argv[0]
 		logger.WithField("Search Key", clusterKey).Debugln("Finding target cluster")
@@ -98,34 +179,190 @@ func runLogin(cmd *cobra.Command, argv []string) (err error) {
 	}
 
 	// Set proxy url to http client
+	proxyURL, err := getProxyURL(bpConfig)
+	if err != nil {
+		return err
+	}
+
+	targetClusterID, targetClusterName, err := getTargetCluster(clusterKey)
+	if err != nil {
+		return err
+	}
+
+	// Show the cluster health and refuse to login to a cluster which isn't ready.
+	// It is the health of the target cluster, even when login to its management or service cluster.
+	if err := checkClusterHealth(targetClusterID, clusterKey, args.force); err != nil {
+		return err
+	}
+
+	clusterID, clusterName, namespace, err := getLoginCluster(targetClusterID, targetClusterName)
+	if err != nil {
+		return err
+	}
+
+	// validate kubeconfig save path when login into multi clusters
+	if err := validateKubeConfigPath(); err != nil {
+		return err
+	}
+
+	// Get Backplane URL
+	bpURL, err := getBackplaneURL(bpConfig)
+	if err != nil {
+		return err
+	}
+
+	// Get ocm access token
+	logger.Debugln("Finding ocm token")
+	accessToken, err := utils.DefaultOCMInterface.GetOCMAccessToken()
+	if err != nil {
+		return err
+	}
+	logger.Debugln("Found OCM access token")
+
+	// Query backplane-api for proxy url
+	bpAPIClusterURL, err := doLogin(bpURL, clusterID, *accessToken)
+	if err != nil {
+		// Check API connection with configured proxy
+		if connErr := bpConfig.CheckAPIConnection(); connErr != nil {
+			return fmt.Errorf("cannot connect to backplane API URL, check if you need to use a proxy/VPN to access backplane: %v", connErr)
+		}
+
+		// Otherwise, return the failure
+		return fmt.Errorf("can't login to cluster: %v", err)
+	}
+	logger.WithField("URL", bpAPIClusterURL).Debugln("Proxy")
+
+	// Check PS1 env is set or not
+
+	EnvPs1, ok := os.LookupEnv(EnvPs1)
+	if !ok {
+		logger.Warn("Env KUBE_PS1_CLUSTER_FUNCTION is not detected. It is recommended to set PS1 to learn which cluster you are operating on, run 'ocm backplane ps1 init bash|zsh' or refer https://github.com/openshift/backplane-cli/blob/main/docs/PS1-setup.md", EnvPs1)
+	}
+
+	// The kubeconfig takes a single proxy, the one reaching the backplane API
+	proxyURL, err = bpConfig.ResolveProxyURL(proxyURL, bpAPIClusterURL)
+	if err != nil {
+		return err
+	}
+
+	tls, err := getClusterTLS(bpConfig, proxyURL)
+	if err != nil {
+		return err
+	}
+
+	// Add a new cluster & context & user, they are merged into the kubeconfig when saved
+	logger.Debugln("Writing OCM configuration ")
+	rc := api.NewConfig()
+	contextName := addClusterToKubeConfig(rc, clusterName, namespace, bpAPIClusterURL, proxyURL, *accessToken, tls)
+
+	// Save the config.
+	kubeConfigPath, err := login.SaveKubeConfig(clusterID, *rc, args.multiCluster, args.kubeConfigPath)
+	if err != nil {
+		return err
+	}
+
+	recordLoginHistory(clusterID, clusterName)
+
+	if args.output != loginOutputText {
+		return renderLoginResult(os.Stdout, args.output, loginResult{
+			ClusterID:   clusterID,
+			ClusterName: clusterName,
+			ProxyURL:    bpAPIClusterURL,
+			Context:     contextName,
+			Namespace:   namespace,
+			KubeConfig:  kubeConfigPath,
+		})
+	}
+
+	if args.multiCluster && args.kubeConfigPath == "" {
+		// Inform how to setup kube config
+		fmt.Printf("Execute the following command to log into the cluster %s \n", clusterID)
+		fmt.Println("export " + info.BackplaneKubeconfigEnvName + "=" + kubeConfigPath)
+	}
+
+	return nil
+}
+
+// getProxyURL returns the proxy url passed by flag or configured in the backplane configuration.
+// A proxy url passed by flag is also set to the backplane api client.
+func getProxyURL(bpConfig config.BackplaneConfiguration) (string, error) {
 	proxyURL := globalOpts.ProxyURL
 	if proxyURL != "" {
-		err = utils.DefaultClientUtils.SetClientProxyURL(proxyURL)
+		err := utils.DefaultClientUtils.SetClientProxyURL(proxyURL)
 
 		if err != nil {
-			return err
+			return "", err
 		}
 		logger.Debugf("Using backplane Proxy URL: %s\n", proxyURL)
 	}
 
 	if len(proxyURL) == 0 {
-		proxyURL = bpConfig.ProxyURL
+		proxyURL = bpConfig.SelectProxyURL()
 	}
 
-	clusterID, clusterName, err := utils.DefaultOCMInterface.GetTargetCluster(clusterKey)
+	return proxyURL, nil
+}
+
+// getBackplaneURL returns the backplane url passed by flag or configured in the backplane configuration
+func getBackplaneURL(bpConfig config.BackplaneConfiguration) (string, error) {
+	bpURL := globalOpts.BackplaneURL
+	if bpURL == "" {
+		bpURL = bpConfig.URL
+	}
+
+	if bpURL == "" {
+		return "", errors.New("empty backplane url - check your backplane-cli configuration")
+	}
+
+	logger.Debugf("Using backplane URL: %s\n", bpURL)
+
+	return bpURL, nil
+}
+
+// validateKubeConfigPath validates the kubeconfig save path when login into multi clusters
+func validateKubeConfigPath() error {
+	if args.kubeConfigPath != "" {
+		if !args.multiCluster {
+			return fmt.Errorf("can't save the kube config into a specific location if multi-cluster is not enabled. Please specify --multi flag")
+		}
+		if _, err := os.Stat(args.kubeConfigPath); errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("kube config save path is not exist")
+		}
+	}
+	return nil
+}
+
+// getTargetCluster returns the cluster of the given cluster key
+func getTargetCluster(clusterKey string) (clusterID, clusterName string, err error) {
+	clusterID, clusterName, err = utils.DefaultOCMInterface.GetTargetCluster(clusterKey)
 	if err != nil {
-		return err
+		return "", "", err
 	}
 
 	logger.WithFields(logger.Fields{
 		"ID":   clusterID,
 		"Name": clusterName}).Infoln("Target cluster")
 
+	return clusterID, clusterName, nil
+}
+
+// getLoginCluster returns the cluster to login to for the given target cluster,
+// which is the managing or service cluster when requested, and the namespace of the context.
+// The namespace is the hosted control plane namespace when login to the management cluster
+// of a hosted cluster, unless a namespace is given.
+func getLoginCluster(clusterID, clusterName string) (string, string, string, error) {
+	var err error
+	namespace := args.namespace
+
 	if globalOpts.Manager {
+		if !namespaceFlag.Changed {
+			namespace = getHCPNamespace(clusterID, clusterName, namespace)
+		}
+
 		logger.WithField("Cluster ID", clusterID).Debugln("Finding managing cluster")
 		clusterID, clusterName, err = utils.DefaultOCMInterface.GetManagingCluster(clusterID)
 		if err != nil {
-			return err
+			return "", "", "", err
 		}
 
 		logger.WithFields(logger.Fields{
@@ -137,7 +374,7 @@ func runLogin(cmd *cobra.Command, argv []string) (err error) {
 		logger.WithField("Cluster ID", clusterID).Debugln("Finding service cluster")
 		clusterID, clusterName, err = utils.DefaultOCMInterface.GetServiceCluster(clusterID)
 		if err != nil {
-			return err
+			return "", "", "", err
 		}
 
 		logger.WithFields(logger.Fields{
@@ -145,76 +382,129 @@ func runLogin(cmd *cobra.Command, argv []string) (err error) {
 			"Name": clusterName}).Infoln("Service cluster")
 	}
 
-	// validate kubeconfig save path when login into multi clusters
-	if args.kubeConfigPath != "" {
-		if !args.multiCluster {
-			return fmt.Errorf("can't save the kube config into a specific location if multi-cluster is not enabled. Please specify --multi flag")
-		}
-		if _, err := os.Stat(args.kubeConfigPath); errors.Is(err, os.ErrNotExist) {
-			return fmt.Errorf("kube config save path is not exist")
-		}
-	}
+	return clusterID, clusterName, namespace, nil
+}
 
-	// Get Backplane URL
-	bpURL := globalOpts.BackplaneURL
-	if bpURL == "" {
-		bpURL = bpConfig.URL
+// getHCPNamespace returns the hosted control plane namespace of the given cluster if it is
+// a hosted cluster, the given default namespace otherwise
+func getHCPNamespace(clusterID, clusterName, defaultNamespace string) string {
+	cluster, err := utils.DefaultOCMInterface.GetClusterInfoByID(clusterID)
+	if err != nil {
+		logger.Debugf("Unable to check if cluster %s is a hosted cluster: %v", clusterID, err)
+		return defaultNamespace
+	}
+	if !cluster.Hypershift().Enabled() {
+		return defaultNamespace
 	}
 
-	if bpURL == "" {
-		return errors.New("empty backplane url - check your backplane-cli configuration")
+	ocmURL, err := utils.DefaultOCMInterface.GetOCMEnvironmentURL()
+	if err != nil {
+		logger.Warnf("Unable to find the hosted control plane namespace: %v", err)
+		return defaultNamespace
+	}
+	env, err := utils.GetOCMEnvironmentName(ocmURL)
+	if err != nil {
+		logger.Warnf("Unable to find the hosted control plane namespace: %v", err)
+		return defaultNamespace
 	}
 
-	logger.Debugf("Using backplane URL: %s\n", bpURL)
+	namespace := utils.GetHCPNamespaces(env, clusterID, clusterName).HostedControlPlane
+	logger.WithField("Namespace", namespace).Infoln("Hosted control plane namespace")
+
+	return namespace
+}
+
+// GetRestConfig returns a client-go *rest.Config which can be used to programmatically interact with the
+// Kubernetes API of a provided clusterID
+func GetRestConfig(bp config.BackplaneConfiguration, clusterID string) (*rest.Config, error) {
+	cluster, err := utils.DefaultOCMInterface.GetClusterInfoByID(clusterID)
+	if err != nil {
+		return nil, err
+	}
 
-	// Get ocm access token
-	logger.Debugln("Finding ocm token")
 	accessToken, err := utils.DefaultOCMInterface.GetOCMAccessToken()
 	if err != nil {
-		return err
+		return nil, err
 	}
-	logger.Debugln("Found OCM access token")
 
-	// Not great if there's an error checking if the cluster is hibernating, but ignore it for now and continue
-	if isHibernating, _ := utils.DefaultOCMInterface.IsClusterHibernating(clusterID); isHibernating {
-		// If it is hibernating, don't try to connect as it will fail
-		return fmt.Errorf("cluster %s is hibernating, login failed", clusterKey)
+	bpAPIClusterURL, err := doLogin(bp.URL, clusterID, *accessToken)
+	if err != nil {
+		return nil, fmt.Errorf("failed to backplane login to cluster %s: %v", cluster.Name(), err)
 	}
 
-	// Query backplane-api for proxy url
-	bpAPIClusterURL, err := doLogin(bpURL, clusterID, *accessToken)
+	cfg := &rest.Config{
+		Host:        bpAPIClusterURL,
+		BearerToken: *accessToken,
+	}
+
+	configuredProxyURL := bp.SelectProxyURL()
+	proxyURL, err := bp.ResolveProxyURL(configuredProxyURL, bpAPIClusterURL)
 	if err != nil {
-		// If login failed, we try to find out if the cluster is hibernating
-		isHibernating, _ := utils.DefaultOCMInterface.IsClusterHibernating(clusterID)
-		if isHibernating {
-			// Hibernating, print an error
-			return fmt.Errorf("cluster %s is hibernating, login failed", clusterKey)
-		}
-		// Check API connection with configured proxy
-		if connErr := bpConfig.CheckAPIConnection(); connErr != nil {
-			return fmt.Errorf("cannot connect to backplane API URL, check if you need to use a proxy/VPN to access backplane: %v", connErr)
+		return nil, err
+	}
+	if configuredProxyURL != "" || bp.PACURL != "" {
+		proxyFunc, err := bp.ProxyFunc(configuredProxyURL)
+		if err != nil {
+			return nil, err
 		}
+		cfg.Proxy = proxyFunc
+	}
 
-		// Otherwise, return the failure
-		return fmt.Errorf("can't login to cluster: %v", err)
+	tls, err := getClusterTLS(bp, proxyURL)
+	if err != nil {
+		return nil, err
+	}
+	cfg.TLSClientConfig = rest.TLSClientConfig{
+		CAData:   tls.caData,
+		Insecure: tls.insecure,
 	}
-	logger.WithField("URL", bpAPIClusterURL).Debugln("Proxy")
 
-	cf := genericclioptions.NewConfigFlags(true)
-	rc, err := cf.ToRawKubeConfigLoader().RawConfig()
+	return cfg, nil
+}
+
+// GetRestConfigAsUser returns a client-go *rest.Config like GetRestConfig, but supports configuring an
+// impersonation username. Commonly, this is "backplane-cluster-admin"
+func GetRestConfigAsUser(bp config.BackplaneConfiguration, clusterID, username string) (*rest.Config, error) {
+	cfg, err := GetRestConfig(bp, clusterID)
 	if err != nil {
-		return err
+		return nil, err
 	}
-	// Check PS1 env is set or not
 
-	EnvPs1, ok := os.LookupEnv(EnvPs1)
-	if !ok {
-		logger.Warn("Env KUBE_PS1_CLUSTER_FUNCTION is not detected. It is recommended to set PS1 to learn which cluster you are operating on, refer https://github.com/openshift/backplane-cli/blob/main/docs/PS1-setup.md", EnvPs1)
+	cfg.Impersonate = rest.ImpersonationConfig{UserName: username}
+
+	return cfg, nil
+}
+
+// clusterTLS is the TLS settings of the backplane cluster in the kubeconfig
+type clusterTLS struct {
+	// caData is the CA bundle verifying the backplane API through the proxy, the system CAs are used when empty
+	caData []byte
+	// insecure skips the TLS verification, for TLS-intercepting proxies
+	insecure bool
+}
+
+// getClusterTLS returns the TLS settings of the backplane cluster reached through the given proxy.
+// client-go doesn't allow both a CA and skipping the verification, skipping it wins.
+// The kubeconfig CA replaces the system CAs, so the CA file is only used through a proxy, for the
+// direct connections to keep the system CAs.
+func getClusterTLS(bpConfig config.BackplaneConfiguration, proxyURL string) (clusterTLS, error) {
+	if bpConfig.SkipTLSVerify(proxyURL) {
+		return clusterTLS{insecure: true}, nil
+	}
+	if proxyURL == "" {
+		return clusterTLS{}, nil
 	}
 
-	// Add a new cluster & context & user
-	logger.Debugln("Writing OCM configuration ")
+	caData, err := bpConfig.ReadCAFile()
+	if err != nil {
+		return clusterTLS{}, err
+	}
+	return clusterTLS{caData: caData}, nil
+}
 
+// addClusterToKubeConfig adds the backplane cluster, user and context to the given kubeconfig,
+// makes the new context the current one and returns its name
+func addClusterToKubeConfig(rc *api.Config, clusterName, namespace, bpAPIClusterURL, proxyURL, accessToken string, tls clusterTLS) string {
 	targetCluster := api.NewCluster()
 	targetUser := api.NewAuthInfo()
 	targetContext := api.NewContext()
@@ -222,18 +512,22 @@ func runLogin(cmd *cobra.Command, argv []string) (err error) {
 	targetCluster.Server = bpAPIClusterURL
 
 	// Add proxy URL to target cluster
-
 	if proxyURL != "" {
 		targetCluster.ProxyURL = proxyURL
 	}
 
-	targetUserNickName := getUsernameFromJWT(*accessToken)
+	// The kubeconfig CA replaces the system CAs, so the CA file must verify the backplane API through the proxy
+	targetCluster.CertificateAuthorityData = tls.caData
+	targetCluster.InsecureSkipTLSVerify = tls.insecure
 
-	targetUser.Token = *accessToken
+	targetUserNickName := getUsernameFromJWT(accessToken)
+
+	// The token is not stored in kubeconfig, oc calls the credential plugin to get a fresh one
+	targetUser.Exec = login.NewExecConfig()
 
 	targetContext.AuthInfo = targetUserNickName
 	targetContext.Cluster = clusterName
-	targetContext.Namespace = "default"
+	targetContext.Namespace = namespace
 	targetContextNickName := getContextNickname(targetContext.Namespace, targetContext.Cluster, targetContext.AuthInfo)
 
 	// Put user, cluster, context into rawconfig
@@ -242,55 +536,66 @@ func runLogin(cmd *cobra.Command, argv []string) (err error) {
 	rc.Contexts[targetContextNickName] = targetContext
 	rc.CurrentContext = targetContextNickName
 
-	// Save the config.
-	err = login.SaveKubeConfig(clusterID, rc, args.multiCluster, args.kubeConfigPath)
-
-	return err
+	return targetContextNickName
 }
 
-// GetRestConfig returns a client-go *rest.Config which can be used to programmatically interact with the
-// Kubernetes API of a provided clusterID
-func GetRestConfig(bp config.BackplaneConfiguration, clusterID string) (*rest.Config, error) {
-	cluster, err := utils.DefaultOCMInterface.GetClusterInfoByID(clusterID)
-	if err != nil {
-		return nil, err
+// recordLoginHistory adds the logged in cluster to the login history.
+// Failing to record the history doesn't fail the login.
+func recordLoginHistory(clusterID, clusterName string) {
+	entry := login.HistoryEntry{
+		ClusterID:   clusterID,
+		ClusterName: clusterName,
+	}
+	if globalOpts.Manager {
+		entry.Mode = login.HistoryModeManager
+	}
+	if globalOpts.Service {
+		entry.Mode = login.HistoryModeService
 	}
 
-	accessToken, err := utils.DefaultOCMInterface.GetOCMAccessToken()
-	if err != nil {
-		return nil, err
+	if err := login.AddHistoryEntry(entry); err != nil {
+		logger.Warnf("Unable to record login history: %v", err)
 	}
+}
 
-	bpAPIClusterURL, err := doLogin(bp.URL, clusterID, *accessToken)
+// chooseRecentCluster asks the user to choose one of the given number of recently logged in clusters
+func chooseRecentCluster(recent int) (login.HistoryEntry, error) {
+	history, err := login.ReadHistory()
 	if err != nil {
-		return nil, fmt.Errorf("failed to backplane login to cluster %s: %v", cluster.Name(), err)
+		return login.HistoryEntry{}, err
 	}
-
-	cfg := &rest.Config{
-		Host:        bpAPIClusterURL,
-		BearerToken: *accessToken,
+	if len(history) == 0 {
+		return login.HistoryEntry{}, fmt.Errorf("the login history is empty")
 	}
-
-	if bp.ProxyURL != "" {
-		cfg.Proxy = func(*http.Request) (*url.URL, error) {
-			return url.Parse(bp.ProxyURL)
-		}
+	if recent < len(history) {
+		history = history[:recent]
+	}
+	if utils.DefaultOCMInterface.IsNonInteractive() {
+		return login.HistoryEntry{}, fmt.Errorf("--recent prompts to choose a cluster, which --non-interactive disables: use \"login -\" or the cluster ID listed by \"ocm backplane history\" instead")
 	}
 
-	return cfg, nil
-}
+	options := []string{}
+	for _, e := range history {
+		options = append(options, fmt.Sprintf("Name: %s, ID: %s", e.ClusterName, e.ClusterID))
+	}
 
-// GetRestConfigAsUser returns a client-go *rest.Config like GetRestConfig, but supports configuring an
-// impersonation username. Commonly, this is "backplane-cluster-admin"
-func GetRestConfigAsUser(bp config.BackplaneConfiguration, clusterID, username string) (*rest.Config, error) {
-	cfg, err := GetRestConfig(bp, clusterID)
-	if err != nil {
-		return nil, err
+	choice := ""
+	prompt := &survey.Select{
+		Message: "Please choose a cluster:",
+		Options: options,
+		Default: options[0],
+	}
+	if err := survey.AskOne(prompt, &choice, nil); err != nil {
+		return login.HistoryEntry{}, err
 	}
 
-	cfg.Impersonate = rest.ImpersonationConfig{UserName: username}
+	for i, option := range options {
+		if option == choice {
+			return history[i], nil
+		}
+	}
 
-	return cfg, nil
+	return login.HistoryEntry{}, fmt.Errorf("the cluster you choose is not valid: %s", choice)
 }
 
 // getContextNickname returns a nickname of a context
@@ -301,22 +606,11 @@ func getContextNickname(namespace, clusterNick, userNick string) string {
 
 // getUsernameFromJWT returns the username extracted from JWT token
 func getUsernameFromJWT(token string) string {
-	var jwtToken *jwt.Token
-	var err error
-	parser := new(jwt.Parser)
-	jwtToken, _, err = parser.ParseUnverified(token, jwt.MapClaims{})
-	if err != nil {
+	claims, err := utils.ParseOCMClaims(token)
+	if err != nil || claims.Username == "" {
 		return "anonymous"
 	}
-	claims, ok := jwtToken.Claims.(jwt.MapClaims)
-	if !ok {
-		return "anonymous"
-	}
-	claim, ok := claims["username"]
-	if !ok {
-		return "anonymous"
-	}
-	return claim.(string)
+	return claims.Username
 }
 
 // doLogin returns the proxy url for the target cluster.
@@ -328,6 +622,11 @@ func doLogin(api, clusterID, accessToken string) (string, error) {
 		return "", fmt.Errorf("unable to create backplane api client")
 	}
 
+	return doLoginWithClient(client, api, clusterID)
+}
+
+// doLoginWithClient returns the proxy url for the target cluster using the given backplane api client.
+func doLoginWithClient(client BackplaneApi.ClientInterface, api, clusterID string) (string, error) {
 	resp, err := client.LoginCluster(context.TODO(), clusterID)
 	// Print the whole response if we can't parse it. Eg. 5xx error from http server.
 	if err != nil {
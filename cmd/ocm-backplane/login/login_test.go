@@ -1,20 +1,26 @@
 package login
 
 import (
+	"bytes"
+	"encoding/pem"
 	"errors"
 	"fmt"
 	"io"
 	"net/http"
+	"net/http/httptest"
 	"os"
+	"path/filepath"
 	"strings"
 
 	"github.com/golang/mock/gomock"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
 	"k8s.io/client-go/tools/clientcmd"
 	"k8s.io/client-go/tools/clientcmd/api"
 
 	"github.com/openshift/backplane-cli/pkg/client/mocks"
+	"github.com/openshift/backplane-cli/pkg/info"
 	"github.com/openshift/backplane-cli/pkg/login"
 	"github.com/openshift/backplane-cli/pkg/utils"
 	mocks2 "github.com/openshift/backplane-cli/pkg/utils/mocks"
@@ -28,6 +34,8 @@ func MakeIoReader(s string) io.ReadCloser {
 var _ = Describe("Login command", func() {
 
 	var (
+		configDir string
+
 		mockCtrl           *gomock.Controller
 		mockClient         *mocks.MockClientInterface
 		mockClientWithResp *mocks.MockClientWithResponsesInterface
@@ -42,6 +50,8 @@ var _ = Describe("Login command", func() {
 		serviceClusterID   string
 		serviceClusterName string
 		fakeResp           *http.Response
+		clusterInfo        *cmv1.Cluster
+		clusterInfoErr     error
 	)
 
 	BeforeEach(func() {
@@ -72,11 +82,24 @@ var _ = Describe("Login command", func() {
 		}
 		fakeResp.Header.Add("Content-Type", "json")
 
+		// Keep the login history out of the user's home
+		var err error
+		configDir, err = os.MkdirTemp("", "backplane")
+		Expect(err).To(BeNil())
+		os.Setenv(info.BackplaneConfigPathEnvName, filepath.Join(configDir, "config.json"))
+
 		// Clear config file
 		_ = clientcmd.ModifyConfig(clientcmd.NewDefaultPathOptions(), api.Config{}, true)
 		clientcmd.UseModifyConfigLock = false
 
 		globalOpts.BackplaneURL = backplaneAPIURI
+
+		// The cluster is ready unless a test says otherwise
+		clusterInfo, _ = cmv1.NewCluster().ID(trueClusterID).Name(testClusterID).State(cmv1.ClusterStateReady).Build()
+		clusterInfoErr = nil
+		mockOcmInterface.EXPECT().GetClusterInfoByID(gomock.Any()).DoAndReturn(func(clusterID string) (*cmv1.Cluster, error) {
+			return clusterInfo, clusterInfoErr
+		}).AnyTimes()
 	})
 
 	AfterEach(func() {
@@ -84,7 +107,11 @@ var _ = Describe("Login command", func() {
 		globalOpts.Service = false
 		globalOpts.BackplaneURL = ""
 		globalOpts.ProxyURL = ""
+		args.force = false
+		args.output = loginOutputText
 		os.Setenv("HTTPS_PROXY", "")
+		os.RemoveAll(configDir)
+		os.Unsetenv(info.BackplaneConfigPathEnvName)
 		mockCtrl.Finish()
 		utils.RemoveTempKubeConfig()
 	})
@@ -101,7 +128,7 @@ var _ = Describe("Login command", func() {
 			Expect(err).ToNot(BeNil())
 		})
 
-		It("should save ocm token to kube config", func() {
+		It("should save the credential plugin instead of the ocm token to kube config", func() {
 			err := utils.CreateTempKubeConfig(nil)
 			Expect(err).To(BeNil())
 			globalOpts.ProxyURL = "https://squid.myproxy.com"
@@ -119,7 +146,11 @@ var _ = Describe("Login command", func() {
 			cfg, err := utils.ReadKubeconfigRaw()
 
 			Expect(err).To(BeNil())
-			Expect(cfg.AuthInfos["anonymous"].Token).To(Equal(testToken))
+			Expect(cfg.AuthInfos["anonymous"].Token).To(BeEmpty())
+			Expect(cfg.AuthInfos["anonymous"].Exec).NotTo(BeNil())
+			Expect(cfg.AuthInfos["anonymous"].Exec.Command).To(Equal("ocm"))
+			Expect(cfg.AuthInfos["anonymous"].Exec.Args).To(Equal([]string{"backplane", "credential"}))
+			Expect(cfg.AuthInfos["anonymous"].Exec.APIVersion).To(Equal(login.ExecCredentialAPIVersion))
 		})
 	})
 
@@ -172,6 +203,83 @@ var _ = Describe("Login command", func() {
 			Expect(cfg.Contexts["default/test123/anonymous"].Namespace).To(Equal("default"))
 		})
 
+		It("should write the configured CA file to the kubeconfig when going through the proxy", func() {
+			svr := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+			defer svr.Close()
+			caData := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: svr.Certificate().Raw})
+			caFile := filepath.Join(configDir, "ca.pem")
+			Expect(os.WriteFile(caFile, caData, 0600)).To(Succeed())
+			Expect(os.WriteFile(filepath.Join(configDir, "config.json"), []byte(`{"ca-file": "`+caFile+`"}`), 0600)).To(Succeed())
+
+			err := utils.CreateTempKubeConfig(nil)
+			Expect(err).To(BeNil())
+			globalOpts.ProxyURL = "https://squid.myproxy.com"
+			mockClientUtil.EXPECT().SetClientProxyURL(globalOpts.ProxyURL).Return(nil)
+			mockOcmInterface.EXPECT().GetTargetCluster(testClusterID).Return(trueClusterID, testClusterID, nil)
+			mockOcmInterface.EXPECT().IsClusterHibernating(gomock.Eq(trueClusterID)).Return(false, nil).AnyTimes()
+			mockOcmInterface.EXPECT().GetOCMAccessToken().Return(&testToken, nil)
+			mockClientUtil.EXPECT().MakeRawBackplaneAPIClientWithAccessToken(backplaneAPIURI, testToken).Return(mockClient, nil)
+			mockClient.EXPECT().LoginCluster(gomock.Any(), gomock.Eq(trueClusterID)).Return(fakeResp, nil)
+
+			err = runLogin(nil, []string{testClusterID})
+
+			Expect(err).To(BeNil())
+
+			cfg, err := utils.ReadKubeconfigRaw()
+			Expect(err).To(BeNil())
+			Expect(cfg.Clusters[testClusterID].CertificateAuthorityData).To(Equal(caData))
+			Expect(cfg.Clusters[testClusterID].InsecureSkipTLSVerify).To(BeFalse())
+		})
+
+		It("should keep the system CAs in the kubeconfig of a direct connection", func() {
+			svr := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+			defer svr.Close()
+			caData := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: svr.Certificate().Raw})
+			caFile := filepath.Join(configDir, "ca.pem")
+			Expect(os.WriteFile(caFile, caData, 0600)).To(Succeed())
+			Expect(os.WriteFile(filepath.Join(configDir, "config.json"), []byte(`{"ca-file": "`+caFile+`"}`), 0600)).To(Succeed())
+
+			err := utils.CreateTempKubeConfig(nil)
+			Expect(err).To(BeNil())
+			mockOcmInterface.EXPECT().GetTargetCluster(testClusterID).Return(trueClusterID, testClusterID, nil)
+			mockOcmInterface.EXPECT().IsClusterHibernating(gomock.Eq(trueClusterID)).Return(false, nil).AnyTimes()
+			mockOcmInterface.EXPECT().GetOCMAccessToken().Return(&testToken, nil)
+			mockClientUtil.EXPECT().MakeRawBackplaneAPIClientWithAccessToken(backplaneAPIURI, testToken).Return(mockClient, nil)
+			mockClient.EXPECT().LoginCluster(gomock.Any(), gomock.Eq(trueClusterID)).Return(fakeResp, nil)
+
+			err = runLogin(nil, []string{testClusterID})
+
+			Expect(err).To(BeNil())
+
+			cfg, err := utils.ReadKubeconfigRaw()
+			Expect(err).To(BeNil())
+			Expect(cfg.Clusters[testClusterID].ProxyURL).To(BeEmpty())
+			Expect(cfg.Clusters[testClusterID].CertificateAuthorityData).To(BeEmpty())
+			Expect(cfg.Clusters[testClusterID].InsecureSkipTLSVerify).To(BeFalse())
+		})
+
+		It("should skip the TLS verification through the proxy when configured", func() {
+			Expect(os.WriteFile(filepath.Join(configDir, "config.json"), []byte(`{"insecure-skip-verify-proxy": true}`), 0600)).To(Succeed())
+
+			err := utils.CreateTempKubeConfig(nil)
+			Expect(err).To(BeNil())
+			globalOpts.ProxyURL = "https://squid.myproxy.com"
+			mockClientUtil.EXPECT().SetClientProxyURL(globalOpts.ProxyURL).Return(nil)
+			mockOcmInterface.EXPECT().GetTargetCluster(testClusterID).Return(trueClusterID, testClusterID, nil)
+			mockOcmInterface.EXPECT().IsClusterHibernating(gomock.Eq(trueClusterID)).Return(false, nil).AnyTimes()
+			mockOcmInterface.EXPECT().GetOCMAccessToken().Return(&testToken, nil)
+			mockClientUtil.EXPECT().MakeRawBackplaneAPIClientWithAccessToken(backplaneAPIURI, testToken).Return(mockClient, nil)
+			mockClient.EXPECT().LoginCluster(gomock.Any(), gomock.Eq(trueClusterID)).Return(fakeResp, nil)
+
+			err = runLogin(nil, []string{testClusterID})
+
+			Expect(err).To(BeNil())
+
+			cfg, err := utils.ReadKubeconfigRaw()
+			Expect(err).To(BeNil())
+			Expect(cfg.Clusters[testClusterID].InsecureSkipTLSVerify).To(BeTrue())
+		})
+
 		It("should fail if unable to create api client", func() {
 			mockOcmInterface.EXPECT().GetTargetCluster(testClusterID).Return(trueClusterID, testClusterID, nil)
 			mockOcmInterface.EXPECT().IsClusterHibernating(gomock.Eq(trueClusterID)).Return(false, nil).AnyTimes()
@@ -211,6 +319,78 @@ var _ = Describe("Login command", func() {
 			Expect(cfg.Contexts["default/test123/anonymous"].Namespace).To(Equal("default"))
 		})
 
+		It("should set the namespace of the context if one is requested", func() {
+			err := utils.CreateTempKubeConfig(nil)
+			Expect(err).To(BeNil())
+			args.namespace = "openshift-monitoring"
+			defer func() { args.namespace = "default" }()
+			mockOcmInterface.EXPECT().GetTargetCluster(testClusterID).Return(trueClusterID, testClusterID, nil)
+			mockOcmInterface.EXPECT().IsClusterHibernating(gomock.Eq(trueClusterID)).Return(false, nil).AnyTimes()
+			mockOcmInterface.EXPECT().GetOCMAccessToken().Return(&testToken, nil)
+			mockClientUtil.EXPECT().MakeRawBackplaneAPIClientWithAccessToken(backplaneAPIURI, testToken).Return(mockClient, nil)
+			mockClient.EXPECT().LoginCluster(gomock.Any(), gomock.Eq(trueClusterID)).Return(fakeResp, nil)
+
+			err = runLogin(nil, []string{testClusterID})
+
+			Expect(err).To(BeNil())
+
+			cfg, err := utils.ReadKubeconfigRaw()
+			Expect(err).To(BeNil())
+			Expect(cfg.CurrentContext).To(Equal("openshift-monitoring/test123/anonymous"))
+			Expect(cfg.Contexts["openshift-monitoring/test123/anonymous"].Namespace).To(Equal("openshift-monitoring"))
+		})
+
+		It("should record the login history and login to the previous cluster", func() {
+			err := utils.CreateTempKubeConfig(nil)
+			Expect(err).To(BeNil())
+			mockOcmInterface.EXPECT().GetTargetCluster(testClusterID).Return(trueClusterID, testClusterID, nil)
+			mockOcmInterface.EXPECT().GetTargetCluster(serviceClusterID).Return(serviceClusterID, serviceClusterName, nil)
+			mockOcmInterface.EXPECT().GetTargetCluster(trueClusterID).Return(trueClusterID, testClusterID, nil)
+			mockOcmInterface.EXPECT().IsClusterHibernating(gomock.Any()).Return(false, nil).AnyTimes()
+			mockOcmInterface.EXPECT().GetOCMAccessToken().Return(&testToken, nil).Times(3)
+			mockClientUtil.EXPECT().MakeRawBackplaneAPIClientWithAccessToken(backplaneAPIURI, testToken).Return(mockClient, nil).Times(3)
+			mockClient.EXPECT().LoginCluster(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx interface{}, clusterID string, reqEditors ...interface{}) (*http.Response, error) {
+				return &http.Response{
+					Body:       MakeIoReader(`{"proxy_uri":"proxy", "statusCode":200, "message":"msg"}`),
+					Header:     map[string][]string{"Content-Type": {"json"}},
+					StatusCode: http.StatusOK,
+				}, nil
+			}).Times(3)
+
+			err = runLogin(nil, []string{testClusterID})
+			Expect(err).To(BeNil())
+			err = runLogin(nil, []string{serviceClusterID})
+			Expect(err).To(BeNil())
+
+			err = runLogin(nil, []string{"-"})
+			Expect(err).To(BeNil())
+
+			history, err := login.ReadHistory()
+			Expect(err).To(BeNil())
+			Expect(len(history)).To(Equal(2))
+			Expect(history[0].ClusterID).To(Equal(trueClusterID))
+			Expect(history[1].ClusterID).To(Equal(serviceClusterID))
+		})
+
+		It("should fail to login to the previous cluster without history", func() {
+			err := runLogin(nil, []string{"-"})
+
+			Expect(err).NotTo(BeNil())
+			Expect(err.Error()).To(Equal("no previous cluster in the login history"))
+		})
+
+		It("should not prompt for a recent cluster in non-interactive mode", func() {
+			Expect(login.AddHistoryEntry(login.HistoryEntry{ClusterID: trueClusterID, ClusterName: testClusterID})).To(Succeed())
+			mockOcmInterface.EXPECT().IsNonInteractive().Return(true)
+			args.recent = 5
+			defer func() { args.recent = 0 }()
+
+			err := runLogin(nil, []string{})
+
+			Expect(err).NotTo(BeNil())
+			Expect(err.Error()).To(ContainSubstring("--non-interactive"))
+		})
+
 		It("Should fail when trying to find a non existent cluster", func() {
 			mockOcmInterface.EXPECT().GetOCMAccessToken().Return(&testToken, nil).AnyTimes()
 			mockOcmInterface.EXPECT().IsClusterHibernating(gomock.Eq(trueClusterID)).Return(false, nil).AnyTimes()
@@ -236,6 +416,31 @@ var _ = Describe("Login command", func() {
 			Expect(err).To(BeNil())
 		})
 
+		It("should set the hosted control plane namespace when login to the management cluster of a hosted cluster", func() {
+			err := utils.CreateTempKubeConfig(nil)
+			Expect(err).To(BeNil())
+			globalOpts.Manager = true
+			clusterInfo, _ = cmv1.NewCluster().ID(trueClusterID).Name(testClusterID).
+				State(cmv1.ClusterStateReady).
+				Hypershift(cmv1.NewHypershift().Enabled(true)).
+				Build()
+			mockOcmInterface.EXPECT().GetTargetCluster(testClusterID).Return(trueClusterID, testClusterID, nil)
+			mockOcmInterface.EXPECT().GetOCMEnvironmentURL().Return("https://api.stage.openshift.com", nil)
+			// The cluster health of the hosted cluster shows its management cluster too
+			mockOcmInterface.EXPECT().GetManagingCluster(trueClusterID).Return(managingClusterID, managingClusterID, nil).Times(2)
+			mockOcmInterface.EXPECT().GetOCMAccessToken().Return(&testToken, nil)
+			mockClientUtil.EXPECT().MakeRawBackplaneAPIClientWithAccessToken(backplaneAPIURI, testToken).Return(mockClient, nil)
+			mockClient.EXPECT().LoginCluster(gomock.Any(), gomock.Eq(managingClusterID)).Return(fakeResp, nil)
+
+			err = runLogin(nil, []string{testClusterID})
+
+			Expect(err).To(BeNil())
+
+			cfg, err := utils.ReadKubeconfigRaw()
+			Expect(err).To(BeNil())
+			Expect(cfg.Contexts[cfg.CurrentContext].Namespace).To(Equal("ocm-staging-trueID123-test123"))
+		})
+
 		It("should failed if managing cluster not exist in same env", func() {
 			globalOpts.Manager = true
 			mockOcmInterface.EXPECT().GetTargetCluster(testClusterID).Return(trueClusterID, testClusterID, nil)
@@ -336,4 +541,271 @@ var _ = Describe("Login command", func() {
 
 		})
 	})
+
+	Context("check cluster health", func() {
+
+		BeforeEach(func() {
+			err := utils.CreateTempKubeConfig(nil)
+			Expect(err).To(BeNil())
+			mockOcmInterface.EXPECT().GetTargetCluster(testClusterID).Return(trueClusterID, testClusterID, nil)
+			mockOcmInterface.EXPECT().GetOCMAccessToken().Return(&testToken, nil).AnyTimes()
+		})
+
+		It("should refuse to login to a hibernating cluster", func() {
+			clusterInfo, _ = cmv1.NewCluster().ID(trueClusterID).Name(testClusterID).State(cmv1.ClusterStateHibernating).Build()
+
+			err := runLogin(nil, []string{testClusterID})
+
+			Expect(err).NotTo(BeNil())
+			Expect(err.Error()).To(Equal("cluster test123 is hibernating, login failed"))
+		})
+
+		It("should check the target cluster before switching to its management cluster", func() {
+			globalOpts.Manager = true
+			clusterInfo, _ = cmv1.NewCluster().ID(trueClusterID).Name(testClusterID).State(cmv1.ClusterStateHibernating).Build()
+
+			err := runLogin(nil, []string{testClusterID})
+
+			Expect(err).NotTo(BeNil())
+			Expect(err.Error()).To(Equal("cluster test123 is hibernating, login failed"))
+		})
+
+		It("should refuse to login to a cluster which is not ready", func() {
+			clusterInfo, _ = cmv1.NewCluster().ID(trueClusterID).Name(testClusterID).State(cmv1.ClusterStateInstalling).Build()
+
+			err := runLogin(nil, []string{testClusterID})
+
+			Expect(err).NotTo(BeNil())
+			Expect(err.Error()).To(Equal("cluster test123 is installing, use --force to login anyway"))
+		})
+
+		It("should login to a cluster which is not ready with --force", func() {
+			clusterInfo, _ = cmv1.NewCluster().ID(trueClusterID).Name(testClusterID).State(cmv1.ClusterStateError).Build()
+			args.force = true
+			mockClientUtil.EXPECT().MakeRawBackplaneAPIClientWithAccessToken(backplaneAPIURI, testToken).Return(mockClient, nil)
+			mockClient.EXPECT().LoginCluster(gomock.Any(), gomock.Eq(trueClusterID)).Return(fakeResp, nil)
+
+			err := runLogin(nil, []string{testClusterID})
+
+			Expect(err).To(BeNil())
+		})
+
+		It("should login when the cluster health can't be retrieved", func() {
+			clusterInfoErr = errors.New("ocm is down")
+			mockClientUtil.EXPECT().MakeRawBackplaneAPIClientWithAccessToken(backplaneAPIURI, testToken).Return(mockClient, nil)
+			mockClient.EXPECT().LoginCluster(gomock.Any(), gomock.Eq(trueClusterID)).Return(fakeResp, nil)
+
+			err := runLogin(nil, []string{testClusterID})
+
+			Expect(err).To(BeNil())
+		})
+	})
+
+	Context("check cluster health summary", func() {
+
+		It("should show the management cluster and the limited support reasons of a HCP cluster", func() {
+			clusterInfo, _ = cmv1.NewCluster().ID(trueClusterID).Name(testClusterID).
+				State(cmv1.ClusterStateReady).
+				OpenshiftVersion("4.14.5").
+				CloudProvider(cmv1.NewCloudProvider().ID("aws")).
+				Region(cmv1.NewCloudRegion().ID("us-east-1")).
+				Hypershift(cmv1.NewHypershift().Enabled(true)).
+				Status(cmv1.NewClusterStatus().LimitedSupportReasonCount(1)).
+				Build()
+			reason, _ := cmv1.NewLimitedSupportReason().Summary("Cluster is in limited support").Build()
+			mockOcmInterface.EXPECT().GetManagingCluster(trueClusterID).Return(managingClusterID, "mc-name", nil)
+			mockOcmInterface.EXPECT().GetClusterLimitedSupportReasons(trueClusterID).Return([]*cmv1.LimitedSupportReason{reason}, nil)
+
+			health, err := getClusterHealth(trueClusterID)
+			Expect(err).To(BeNil())
+
+			out := &strings.Builder{}
+			printClusterHealth(out, health)
+
+			Expect(out.String()).To(ContainSubstring("State:      ready"))
+			Expect(out.String()).To(ContainSubstring("Version:    4.14.5"))
+			Expect(out.String()).To(ContainSubstring("Cloud:      aws us-east-1"))
+			Expect(out.String()).To(ContainSubstring("Topology:   HCP"))
+			Expect(out.String()).To(ContainSubstring("Management: mc-name"))
+			Expect(out.String()).To(ContainSubstring("  - Cluster is in limited support"))
+		})
+	})
+
+	Context("check multi cluster login", func() {
+		var kubePath string
+
+		BeforeEach(func() {
+			var err error
+			kubePath, err = os.MkdirTemp("", ".kube")
+			Expect(err).To(BeNil())
+
+			args.multiCluster = true
+			args.kubeConfigPath = kubePath
+			args.parallel = defaultLoginParallelism
+		})
+
+		AfterEach(func() {
+			args.multiCluster = false
+			args.kubeConfigPath = ""
+			args.fromFile = ""
+			os.RemoveAll(kubePath)
+		})
+
+		newLoginResp := func(ctx interface{}, clusterID string, reqEditors ...interface{}) (*http.Response, error) {
+			return &http.Response{
+				Body:       MakeIoReader(`{"proxy_uri":"/backplane/cluster/` + clusterID + `", "statusCode":200, "message":"msg"}`),
+				Header:     map[string][]string{"Content-Type": {"json"}},
+				StatusCode: http.StatusOK,
+			}, nil
+		}
+
+		It("should fail if multi-cluster is not enabled", func() {
+			args.multiCluster = false
+			args.kubeConfigPath = ""
+
+			err := runLogin(nil, []string{"cluster1", "cluster2"})
+
+			Expect(err).NotTo(BeNil())
+			Expect(err.Error()).To(ContainSubstring("--multi"))
+		})
+
+		It("should write one kubeconfig per cluster", func() {
+			mockOcmInterface.EXPECT().GetTargetCluster("cluster1").Return("id1", "name1", nil)
+			mockOcmInterface.EXPECT().GetTargetCluster("cluster2").Return("id2", "name2", nil)
+			mockOcmInterface.EXPECT().IsClusterHibernating(gomock.Any()).Return(false, nil).Times(2)
+			mockOcmInterface.EXPECT().GetOCMAccessToken().Return(&testToken, nil)
+			mockClientUtil.EXPECT().MakeRawBackplaneAPIClientWithAccessToken(backplaneAPIURI, testToken).Return(mockClient, nil)
+			mockClient.EXPECT().LoginCluster(gomock.Any(), gomock.Any()).DoAndReturn(newLoginResp).Times(2)
+
+			err := runLogin(nil, []string{"cluster1", "cluster2"})
+			Expect(err).To(BeNil())
+
+			for _, id := range []string{"id1", "id2"} {
+				cfg, err := clientcmd.LoadFromFile(filepath.Join(kubePath, id, "config"))
+				Expect(err).To(BeNil())
+				Expect(cfg.Clusters[cfg.Contexts[cfg.CurrentContext].Cluster].Server).To(Equal(backplaneAPIURI + "/backplane/cluster/" + id))
+			}
+		})
+
+		It("should read the clusters from file and report failures", func() {
+			clusterFile := filepath.Join(kubePath, "clusters.txt")
+			err := os.WriteFile(clusterFile, []byte("# clusters\ncluster1\n\ncluster2\ncluster3\n"), 0600)
+			Expect(err).To(BeNil())
+			args.fromFile = clusterFile
+
+			mockOcmInterface.EXPECT().GetTargetCluster("cluster1").Return("id1", "name1", nil)
+			mockOcmInterface.EXPECT().GetTargetCluster("cluster2").Return("id2", "name2", nil)
+			mockOcmInterface.EXPECT().GetTargetCluster("cluster3").Return("", "", errors.New("not found"))
+			mockOcmInterface.EXPECT().IsClusterHibernating("id1").Return(false, nil)
+			mockOcmInterface.EXPECT().IsClusterHibernating("id2").Return(true, nil)
+			mockOcmInterface.EXPECT().GetOCMAccessToken().Return(&testToken, nil)
+			mockClientUtil.EXPECT().MakeRawBackplaneAPIClientWithAccessToken(backplaneAPIURI, testToken).Return(mockClient, nil)
+			mockClient.EXPECT().LoginCluster(gomock.Any(), "id1").DoAndReturn(newLoginResp)
+
+			err = runLogin(nil, nil)
+
+			Expect(err).NotTo(BeNil())
+			Expect(err.Error()).To(Equal("failed to login to 2 of 3 clusters"))
+
+			_, err = os.Stat(filepath.Join(kubePath, "id1", "config"))
+			Expect(err).To(BeNil())
+			_, err = os.Stat(filepath.Join(kubePath, "id2", "config"))
+			Expect(os.IsNotExist(err)).To(BeTrue())
+		})
+
+		It("should fail the login to the clusters which are not ready", func() {
+			clusterInfo, _ = cmv1.NewCluster().State(cmv1.ClusterStateInstalling).Build()
+			mockOcmInterface.EXPECT().GetTargetCluster("cluster1").Return("id1", "name1", nil)
+			mockOcmInterface.EXPECT().GetTargetCluster("cluster2").Return("id2", "name2", nil)
+			mockOcmInterface.EXPECT().IsClusterHibernating(gomock.Any()).Return(false, nil).Times(2)
+			mockOcmInterface.EXPECT().GetOCMAccessToken().Return(&testToken, nil)
+			mockClientUtil.EXPECT().MakeRawBackplaneAPIClientWithAccessToken(backplaneAPIURI, testToken).Return(mockClient, nil)
+
+			err := runLogin(nil, []string{"cluster1", "cluster2"})
+
+			Expect(err).NotTo(BeNil())
+			Expect(err.Error()).To(Equal("failed to login to 2 of 2 clusters"))
+
+			for _, id := range []string{"id1", "id2"} {
+				_, err = os.Stat(filepath.Join(kubePath, id, "config"))
+				Expect(os.IsNotExist(err)).To(BeTrue())
+			}
+		})
+	})
+
+	Context("login output", func() {
+		var result loginResult
+
+		BeforeEach(func() {
+			result = loginResult{
+				ClusterID:   trueClusterID,
+				ClusterName: testClusterID,
+				ProxyURL:    backplaneAPIURI + "/backplane/cluster/" + trueClusterID,
+				Context:     "default/" + testClusterID + "/anonymous",
+				Namespace:   "default",
+				KubeConfig:  "/home/user/.kube/config",
+			}
+		})
+
+		It("should refuse an unsupported output format", func() {
+			args.output = "xml"
+
+			err := runLogin(nil, []string{testClusterID})
+
+			Expect(err).To(MatchError("unsupported output format xml, supported formats are text|json|yaml|env"))
+		})
+
+		It("should refuse the env output when login to several clusters", func() {
+			args.output = loginOutputEnv
+
+			err := runLogin(nil, []string{"cluster1", "cluster2"})
+
+			Expect(err).NotTo(BeNil())
+			Expect(err.Error()).To(ContainSubstring("env output is not supported"))
+		})
+
+		It("should render the login result as json", func() {
+			out := &bytes.Buffer{}
+
+			err := renderLoginResult(out, loginOutputJSON, result)
+
+			Expect(err).To(BeNil())
+			Expect(out.String()).To(MatchJSON(`{
+				"cluster_id": "trueID123",
+				"cluster_name": "test123",
+				"proxy_url": "https://shard.apps/backplane/cluster/trueID123",
+				"context": "default/test123/anonymous",
+				"namespace": "default",
+				"kubeconfig": "/home/user/.kube/config"
+			}`))
+		})
+
+		It("should render the login result as yaml", func() {
+			out := &bytes.Buffer{}
+
+			err := renderLoginResult(out, loginOutputYAML, result)
+
+			Expect(err).To(BeNil())
+			Expect(out.String()).To(ContainSubstring("cluster_id: trueID123\n"))
+			Expect(out.String()).To(ContainSubstring("kubeconfig: /home/user/.kube/config\n"))
+		})
+
+		It("should render the login result as shell exports", func() {
+			out := &bytes.Buffer{}
+			result.ClusterName = "it's"
+
+			err := renderLoginResult(out, loginOutputEnv, result)
+
+			Expect(err).To(BeNil())
+			Expect(out.String()).To(ContainSubstring("export BACKPLANE_CLUSTER_ID='trueID123'\n"))
+			Expect(out.String()).To(ContainSubstring(`export BACKPLANE_CLUSTER_NAME='it'\''s'`))
+			Expect(out.String()).To(ContainSubstring("export KUBECONFIG='/home/user/.kube/config'\n"))
+		})
+
+		It("should report the failed cluster logins", func() {
+			l := &clusterLogin{ClusterKey: "cluster3", Status: loginStatusFailed, Err: errors.New("not found")}
+
+			Expect(l.result()).To(Equal(loginResult{ClusterKey: "cluster3", Status: loginStatusFailed, Error: "not found"}))
+		})
+	})
 })
@@ -0,0 +1,288 @@
+package login
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	logger "github.com/sirupsen/logrus"
+	"k8s.io/client-go/tools/clientcmd/api"
+
+	BackplaneApi "github.com/openshift/backplane-api/pkg/client"
+
+	"github.com/openshift/backplane-cli/pkg/cli/config"
+	"github.com/openshift/backplane-cli/pkg/info"
+	"github.com/openshift/backplane-cli/pkg/login"
+	"github.com/openshift/backplane-cli/pkg/utils"
+)
+
+const (
+	// defaultLoginParallelism is the default number of concurrent logins
+	defaultLoginParallelism = 5
+
+	loginStatusSuccess     = "Logged in"
+	loginStatusHibernating = "Hibernating"
+	loginStatusFailed      = "Failed"
+)
+
+// clusterLogin holds the target and the result of one cluster login
+type clusterLogin struct {
+	ClusterKey string
+	// TargetClusterID is the cluster of the cluster key, ClusterID is the management
+	// or service cluster instead when login to it
+	TargetClusterID string
+	ClusterID       string
+	ClusterName     string
+	Namespace       string
+	ProxyURL        string
+	Context         string
+	KubeConfigPath  string
+	Status          string
+	Err             error
+}
+
+// runMultiLogin logs into several clusters concurrently and writes one kubeconfig per cluster
+func runMultiLogin(argv []string) error {
+	if !args.multiCluster {
+		return fmt.Errorf("login to several clusters requires multi-cluster login. Please specify --multi flag")
+	}
+
+	if args.parallel < 1 {
+		return fmt.Errorf("--parallel must be greater than 0")
+	}
+
+	clusterKeys, err := getClusterKeys(argv, args.fromFile)
+	if err != nil {
+		return err
+	}
+
+	bpConfig, err := config.GetBackplaneConfiguration()
+	if err != nil {
+		return err
+	}
+
+	proxyURL, err := getProxyURL(bpConfig)
+	if err != nil {
+		return err
+	}
+
+	if err := validateKubeConfigPath(); err != nil {
+		return err
+	}
+
+	bpURL, err := getBackplaneURL(bpConfig)
+	if err != nil {
+		return err
+	}
+
+	// The kubeconfig takes a single proxy, the one reaching the backplane API
+	proxyURL, err = bpConfig.ResolveProxyURL(proxyURL, bpURL)
+	if err != nil {
+		return err
+	}
+
+	tls, err := getClusterTLS(bpConfig, proxyURL)
+	if err != nil {
+		return err
+	}
+
+	accessToken, err := utils.DefaultOCMInterface.GetOCMAccessToken()
+	if err != nil {
+		return err
+	}
+
+	if args.kubeConfigPath != "" {
+		if err := login.SetKubeConfigBasePath(args.kubeConfigPath); err != nil {
+			return err
+		}
+	}
+
+	// Resolve the clusters one by one, as the cluster search may ask the user to choose
+	logins := make([]*clusterLogin, 0, len(clusterKeys))
+	for _, clusterKey := range clusterKeys {
+		l := &clusterLogin{ClusterKey: clusterKey}
+		l.TargetClusterID, l.ClusterName, l.Err = getTargetCluster(clusterKey)
+		if l.Err == nil {
+			l.ClusterID, l.ClusterName, l.Namespace, l.Err = getLoginCluster(l.TargetClusterID, l.ClusterName)
+		}
+		if l.Err != nil {
+			l.Status = loginStatusFailed
+		}
+		logins = append(logins, l)
+	}
+
+	// Share one client between the logins, creating it sets up the http transport
+	client, err := utils.DefaultClientUtils.MakeRawBackplaneAPIClientWithAccessToken(bpURL, *accessToken)
+	if err != nil {
+		return fmt.Errorf("unable to create backplane api client")
+	}
+
+	doMultiLogin(logins, client, bpURL, proxyURL, *accessToken, tls, args.parallel)
+
+	for _, l := range logins {
+		if l.Err == nil {
+			recordLoginHistory(l.ClusterID, l.ClusterName)
+		}
+	}
+
+	if args.output == loginOutputText {
+		renderLoginSummary(logins)
+	} else {
+		results := make([]loginResult, 0, len(logins))
+		for _, l := range logins {
+			results = append(results, l.result())
+		}
+		if err := renderLoginResult(os.Stdout, args.output, results); err != nil {
+			return err
+		}
+	}
+
+	failed := 0
+	for _, l := range logins {
+		if l.Err != nil {
+			failed++
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("failed to login to %d of %d clusters", failed, len(logins))
+	}
+
+	return nil
+}
+
+// getClusterKeys returns the cluster keys given as arguments and listed in the given file.
+// Empty lines and lines starting with # are ignored in the file.
+func getClusterKeys(argv []string, fromFile string) ([]string, error) {
+	clusterKeys := append([]string{}, argv...)
+
+	if fromFile != "" {
+		file, err := os.Open(filepath.Clean(fromFile))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read cluster list: %v", err)
+		}
+		defer file.Close()
+
+		scanner := bufio.NewScanner(file)
+		for scanner.Scan() {
+			line := strings.TrimSpace(scanner.Text())
+			if line == "" || strings.HasPrefix(line, "#") {
+				continue
+			}
+			clusterKeys = append(clusterKeys, line)
+		}
+		if err := scanner.Err(); err != nil {
+			return nil, fmt.Errorf("failed to read cluster list: %v", err)
+		}
+	}
+
+	if len(clusterKeys) == 0 {
+		return nil, fmt.Errorf("no cluster to login to")
+	}
+
+	return clusterKeys, nil
+}
+
+// doMultiLogin logs into the resolved clusters with at most parallel logins at a time
+func doMultiLogin(logins []*clusterLogin, client BackplaneApi.ClientInterface, bpURL, proxyURL, accessToken string, tls clusterTLS, parallel int) {
+	var wg sync.WaitGroup
+	queue := make(chan *clusterLogin)
+
+	for i := 0; i < parallel; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for l := range queue {
+				doClusterLogin(l, client, bpURL, proxyURL, accessToken, tls)
+			}
+		}()
+	}
+
+	for _, l := range logins {
+		if l.Err != nil {
+			continue
+		}
+		queue <- l
+	}
+	close(queue)
+
+	wg.Wait()
+}
+
+// doClusterLogin logs into one cluster and writes its kubeconfig
+func doClusterLogin(l *clusterLogin, client BackplaneApi.ClientInterface, bpURL, proxyURL, accessToken string, tls clusterTLS) {
+	logger.WithFields(logger.Fields{
+		"ID":   l.ClusterID,
+		"Name": l.ClusterName}).Debugln("Logging into cluster")
+
+	// Not great if there's an error checking if the cluster is hibernating, but ignore it for now and continue
+	if isHibernating, _ := utils.DefaultOCMInterface.IsClusterHibernating(l.ClusterID); isHibernating {
+		l.Status = loginStatusHibernating
+		l.Err = fmt.Errorf("cluster %s is hibernating", l.ClusterKey)
+		return
+	}
+
+	if err := checkClusterHealth(l.TargetClusterID, l.ClusterKey, args.force); err != nil {
+		l.Status = loginStatusFailed
+		l.Err = err
+		return
+	}
+
+	bpAPIClusterURL, err := doLoginWithClient(client, bpURL, l.ClusterID)
+	if err != nil {
+		l.Status = loginStatusFailed
+		l.Err = err
+		return
+	}
+
+	l.ProxyURL = bpAPIClusterURL
+
+	rc := api.NewConfig()
+	l.Context = addClusterToKubeConfig(rc, l.ClusterName, l.Namespace, bpAPIClusterURL, proxyURL, accessToken, tls)
+
+	l.KubeConfigPath, err = login.CreateClusterKubeConfig(l.ClusterID, *rc)
+	if err != nil {
+		l.Status = loginStatusFailed
+		l.Err = err
+		return
+	}
+
+	l.Status = loginStatusSuccess
+}
+
+// result returns the machine-readable result of the cluster login
+func (l *clusterLogin) result() loginResult {
+	r := loginResult{
+		ClusterKey:  l.ClusterKey,
+		ClusterID:   l.ClusterID,
+		ClusterName: l.ClusterName,
+		ProxyURL:    l.ProxyURL,
+		Context:     l.Context,
+		Namespace:   l.Namespace,
+		KubeConfig:  l.KubeConfigPath,
+		Status:      l.Status,
+	}
+	if l.Err != nil {
+		r.Error = l.Err.Error()
+	}
+	return r
+}
+
+// renderLoginSummary prints the result of every cluster login
+func renderLoginSummary(logins []*clusterLogin) {
+	headers := []string{"CLUSTER KEY", "ID", "NAME", "STATUS", "KUBECONFIG/ERROR"}
+	rows := make([][]string, 0, len(logins))
+	for _, l := range logins {
+		detail := l.KubeConfigPath
+		if l.Err != nil {
+			detail = l.Err.Error()
+		}
+		rows = append(rows, []string{l.ClusterKey, l.ClusterID, l.ClusterName, l.Status, detail})
+	}
+	utils.RenderTable(headers, rows)
+
+	fmt.Printf("\nExecute the following command to log into one of the clusters\n")
+	fmt.Println("export " + info.BackplaneKubeconfigEnvName + "=<KUBECONFIG>")
+}
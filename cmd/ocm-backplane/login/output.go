@@ -0,0 +1,94 @@
+package login
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"sigs.k8s.io/yaml"
+
+	"github.com/openshift/backplane-cli/pkg/info"
+)
+
+const (
+	loginOutputText = "text"
+	loginOutputJSON = "json"
+	loginOutputYAML = "yaml"
+	loginOutputEnv  = "env"
+)
+
+// loginResult is the machine-readable result of a cluster login
+type loginResult struct {
+	ClusterKey  string `json:"cluster_key,omitempty"`
+	ClusterID   string `json:"cluster_id"`
+	ClusterName string `json:"cluster_name"`
+	ProxyURL    string `json:"proxy_url"`
+	Context     string `json:"context"`
+	Namespace   string `json:"namespace"`
+	KubeConfig  string `json:"kubeconfig"`
+	Status      string `json:"status,omitempty"`
+	Error       string `json:"error,omitempty"`
+}
+
+// FmtExport returns the result as shell export statements
+func (r loginResult) FmtExport() string {
+	vars := [][2]string{
+		{"BACKPLANE_CLUSTER_ID", r.ClusterID},
+		{"BACKPLANE_CLUSTER_NAME", r.ClusterName},
+		{"BACKPLANE_PROXY_URL", r.ProxyURL},
+		{"BACKPLANE_CONTEXT", r.Context},
+		{info.BackplaneKubeconfigEnvName, r.KubeConfig},
+	}
+
+	lines := make([]string, 0, len(vars))
+	for _, v := range vars {
+		lines = append(lines, fmt.Sprintf("export %s=%s", v[0], shellQuote(v[1])))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// validateLoginOutput checks the given output format is supported
+func validateLoginOutput(output string, multi bool) error {
+	switch output {
+	case loginOutputText, loginOutputJSON, loginOutputYAML:
+		return nil
+	case loginOutputEnv:
+		if multi {
+			return fmt.Errorf("env output is not supported when login to several clusters, use json or yaml")
+		}
+		return nil
+	default:
+		return fmt.Errorf("unsupported output format %s, supported formats are text|json|yaml|env", output)
+	}
+}
+
+// renderLoginResult writes the result of the login in the given format
+func renderLoginResult(w io.Writer, output string, result interface{}) error {
+	switch output {
+	case loginOutputJSON:
+		out, err := json.MarshalIndent(result, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(w, string(out))
+	case loginOutputYAML:
+		out, err := yaml.Marshal(result)
+		if err != nil {
+			return err
+		}
+		fmt.Fprint(w, string(out))
+	case loginOutputEnv:
+		r, ok := result.(loginResult)
+		if !ok {
+			return fmt.Errorf("env output is not supported for this login")
+		}
+		fmt.Fprintln(w, r.FmtExport())
+	}
+	return nil
+}
+
+// shellQuote quotes the given value so a POSIX shell reads it verbatim
+func shellQuote(value string) string {
+	return "'" + strings.ReplaceAll(value, "'", `'\''`) + "'"
+}
@@ -0,0 +1,131 @@
+package login
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+	logger "github.com/sirupsen/logrus"
+
+	"github.com/openshift/backplane-cli/pkg/utils"
+)
+
+// clusterHealth is the cluster information shown before login
+type clusterHealth struct {
+	ClusterID             string
+	ClusterName           string
+	State                 cmv1.ClusterState
+	Version               string
+	CloudProvider         string
+	Region                string
+	Hypershift            bool
+	ManagementCluster     string
+	LimitedSupportReasons []string
+}
+
+// getClusterHealth fetches the cluster from OCM and summarizes its health
+func getClusterHealth(clusterID string) (*clusterHealth, error) {
+	cluster, err := utils.DefaultOCMInterface.GetClusterInfoByID(clusterID)
+	if err != nil {
+		return nil, err
+	}
+
+	health := &clusterHealth{
+		ClusterID:     cluster.ID(),
+		ClusterName:   cluster.Name(),
+		State:         cluster.State(),
+		Version:       cluster.OpenshiftVersion(),
+		CloudProvider: cluster.CloudProvider().ID(),
+		Region:        cluster.Region().ID(),
+		Hypershift:    cluster.Hypershift().Enabled(),
+	}
+	if health.ClusterID == "" {
+		health.ClusterID = clusterID
+	}
+	if health.State == "" {
+		health.State = cluster.Status().State()
+	}
+	if health.Version == "" {
+		health.Version = cluster.Version().RawID()
+	}
+
+	if health.Hypershift {
+		// The management cluster is informational only, don't fail the login if it can't be found
+		_, mcName, err := utils.DefaultOCMInterface.GetManagingCluster(clusterID)
+		if err != nil {
+			logger.Debugf("Unable to find the management cluster of %s: %v", clusterID, err)
+		} else {
+			health.ManagementCluster = mcName
+		}
+	}
+
+	if cluster.Status().LimitedSupportReasonCount() > 0 {
+		reasons, err := utils.DefaultOCMInterface.GetClusterLimitedSupportReasons(clusterID)
+		if err != nil {
+			logger.Debugf("Unable to get the limited support reasons of %s: %v", clusterID, err)
+			health.LimitedSupportReasons = []string{"unknown"}
+		}
+		for _, reason := range reasons {
+			health.LimitedSupportReasons = append(health.LimitedSupportReasons, reason.Summary())
+		}
+	}
+
+	return health, nil
+}
+
+// printClusterHealth prints a compact summary of the cluster health
+func printClusterHealth(w io.Writer, health *clusterHealth) {
+	topology := "Classic"
+	if health.Hypershift {
+		topology = "HCP"
+	}
+
+	fmt.Fprintf(w, "Cluster:    %s (%s)\n", health.ClusterName, health.ClusterID)
+	fmt.Fprintf(w, "State:      %s\n", health.State)
+	fmt.Fprintf(w, "Version:    %s\n", health.Version)
+	fmt.Fprintf(w, "Cloud:      %s %s\n", health.CloudProvider, health.Region)
+	fmt.Fprintf(w, "Topology:   %s\n", topology)
+	if health.ManagementCluster != "" {
+		fmt.Fprintf(w, "Management: %s\n", health.ManagementCluster)
+	}
+	if len(health.LimitedSupportReasons) > 0 {
+		fmt.Fprintf(w, "Limited support:\n")
+		for _, reason := range health.LimitedSupportReasons {
+			fmt.Fprintf(w, "  - %s\n", reason)
+		}
+	}
+}
+
+// checkClusterHealth shows the cluster health summary and refuses to login to
+// a cluster which isn't ready, unless force is set. A hibernating cluster is
+// always refused as the login can't succeed.
+func checkClusterHealth(clusterID, clusterKey string, force bool) error {
+	health, err := getClusterHealth(clusterID)
+	if err != nil {
+		// Not great if there's an error getting the cluster, but ignore it for now and continue
+		logger.Warnf("Unable to get the health of cluster %s: %v", clusterKey, err)
+		return nil
+	}
+
+	// Print the summary at once, as the clusters are checked concurrently with multi-cluster login
+	summary := &bytes.Buffer{}
+	printClusterHealth(summary, health)
+	_, _ = os.Stderr.Write(summary.Bytes())
+
+	switch health.State {
+	case cmv1.ClusterStateReady, "":
+		return nil
+	case cmv1.ClusterStateHibernating:
+		// If it is hibernating, don't try to connect as it will fail
+		return fmt.Errorf("cluster %s is hibernating, login failed", clusterKey)
+	}
+
+	if force {
+		logger.Warnf("Cluster %s is %s, continuing the login as --force is set", clusterKey, health.State)
+		return nil
+	}
+
+	return fmt.Errorf("cluster %s is %s, use --force to login anyway", clusterKey, health.State)
+}
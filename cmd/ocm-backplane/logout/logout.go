@@ -3,29 +3,71 @@ package logout
 import (
 	"fmt"
 	"regexp"
+	"sort"
+	"time"
 
 	logger "github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
-	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/client-go/tools/clientcmd/api"
 
 	"github.com/openshift/backplane-cli/pkg/login"
 	"github.com/openshift/backplane-cli/pkg/utils"
 )
 
-// LogoutCmd represents the logout command
-var LogoutCmd = &cobra.Command{
-	Use:   "logout",
-	Short: "Logout of the current cluster by deleting the related reference in kubeconfig",
-	Long: `Logout command will remove the current kubeconfig context and
+var (
+	args struct {
+		all       bool
+		olderThan time.Duration
+		dryRun    bool
+	}
+
+	// LogoutCmd represents the logout command
+	LogoutCmd = &cobra.Command{
+		Use:   "logout",
+		Short: "Logout of the current cluster by deleting the related reference in kubeconfig",
+		Long: `Logout command will remove the current kubeconfig context and
            remove the reference to the current cluster if you have logged on
            with backplane`,
-	Example:      "ocm backplane logout",
-	RunE:         runLogout,
-	SilenceUsage: true,
+		Example:      "ocm backplane logout\nocm backplane logout --all\nocm backplane logout --older-than 24h --dry-run",
+		RunE:         runLogout,
+		SilenceUsage: true,
+	}
+)
+
+func init() {
+	flags := LogoutCmd.Flags()
+
+	flags.BoolVar(
+		&args.all,
+		"all",
+		false,
+		"Remove every backplane context, cluster and user from the kubeconfig.",
+	)
+
+	flags.DurationVar(
+		&args.olderThan,
+		"older-than",
+		0,
+		"Remove the cluster specific kubeconfigs which weren't used for the given duration, eg. 24h.",
+	)
+
+	flags.BoolVar(
+		&args.dryRun,
+		"dry-run",
+		false,
+		"List what would be removed by --all or --older-than without removing it.",
+	)
 }
 
 func runLogout(cmd *cobra.Command, argv []string) error {
 
+	if args.all || args.olderThan > 0 {
+		return runPrune()
+	}
+	if args.dryRun {
+		return fmt.Errorf("--dry-run requires --all or --older-than")
+	}
+
 	// Logout specific cluster
 	if len(argv) == 1 {
 		clusterID, _, err := utils.DefaultOCMInterface.GetTargetCluster(argv[0])
@@ -40,65 +82,167 @@ func runLogout(cmd *cobra.Command, argv []string) error {
 		}
 		fmt.Printf("Logged out from backplane: %s\n", argv[0])
 	} else {
-		// Get raw kubeconfig from the default kubeconfig file
-		rc, err := utils.ReadKubeconfigRaw()
+		// Update the default kubeconfig file holding its lock, so a concurrent login isn't lost
+		var savedContext string
+		err := utils.UpdateDefaultKubeConfig(func(rc *api.Config) error {
+			var err error
+			savedContext, err = logoutCurrentContext(rc)
+			return err
+		})
 		if err != nil {
 			return err
 		}
+		logger.Debugln("Kubeconfig written")
+		fmt.Printf("Logged out from backplane: %s\n", savedContext)
+	}
 
-		// Kubeconfig has three main objects: Cluster/Context/User
-		// Context is the current Cluster/User combination Kubeconfig is
-		// currently working on
+	return nil
+}
 
-		// To cleanup, we use `CurrentContext` to obtain the cluster and user
-		// and delete all relevant info
-		currentContextObj := rc.Contexts[rc.CurrentContext]
-		if currentContextObj == nil {
-			return fmt.Errorf("current context does not exist, skipping")
-		}
-		currentUser := currentContextObj.AuthInfo
-		currentCluster := currentContextObj.Cluster
-		currentClusterObj := rc.Clusters[currentCluster]
-		if currentClusterObj == nil {
-			return fmt.Errorf("current cluster not found, skipping")
-		}
-		currentServer := currentClusterObj.Server
+// logoutCurrentContext deletes the current context of the kubeconfig with its cluster and user,
+// if it was created by backplane. It returns the name of the deleted context.
+func logoutCurrentContext(rc *api.Config) (string, error) {
+	// Kubeconfig has three main objects: Cluster/Context/User
+	// Context is the current Cluster/User combination Kubeconfig is
+	// currently working on
+
+	// To cleanup, we use `CurrentContext` to obtain the cluster and user
+	// and delete all relevant info
+	currentContextObj := rc.Contexts[rc.CurrentContext]
+	if currentContextObj == nil {
+		return "", fmt.Errorf("current context does not exist, skipping")
+	}
+	currentUser := currentContextObj.AuthInfo
+	currentCluster := currentContextObj.Cluster
+	currentClusterObj := rc.Clusters[currentCluster]
+	if currentClusterObj == nil {
+		return "", fmt.Errorf("current cluster not found, skipping")
+	}
+	currentServer := currentClusterObj.Server
 
-		// backplane should only handle `logout` associated context
-		// created with backplane itself, we check this via matching
-		// the cluster server endpoint
-		backplaneServerRegex := regexp.MustCompile(utils.BackplaneAPIURLRegexp)
+	// backplane should only handle `logout` associated context
+	// created with backplane itself, we check this via matching
+	// the cluster server endpoint
+	backplaneServerRegex := regexp.MustCompile(utils.BackplaneAPIURLRegexp)
 
-		logger.WithFields(logger.Fields{
-			"currentServer":  currentServer,
-			"currentUser":    currentUser,
-			"currentContext": rc.CurrentContext,
-		}).Debugln("Current context")
+	logger.WithFields(logger.Fields{
+		"currentServer":  currentServer,
+		"currentUser":    currentUser,
+		"currentContext": rc.CurrentContext,
+	}).Debugln("Current context")
 
-		if !backplaneServerRegex.MatchString(currentServer) {
-			return fmt.Errorf("you're not logged in using backplane, skipping")
-		}
+	if !backplaneServerRegex.MatchString(currentServer) {
+		return "", fmt.Errorf("you're not logged in using backplane, skipping")
+	}
+
+	logger.Debugln("Logging out of the current cluster")
 
-		logger.Debugln("Logging out of the current cluster")
+	// Delete the current cluster/context/user and set current-context to empty str
+	delete(rc.Clusters, currentCluster)
+	delete(rc.Contexts, rc.CurrentContext)
+	delete(rc.AuthInfos, currentUser)
+	savedContext := rc.CurrentContext
+	// Setting current-context to empty str will make `oc` command return
+	// errors saying that the config is incomplete, however, this is inline with
+	// the behavior of `oc config unset current-context`
+	rc.CurrentContext = ""
 
-		// Delete the current cluster/context/user and set current-context to empty str
-		delete(rc.Clusters, currentCluster)
-		delete(rc.Contexts, rc.CurrentContext)
-		delete(rc.AuthInfos, currentUser)
-		savedContext := rc.CurrentContext
-		// Setting current-context to empty str will make `oc` command return
-		// errors saying that the config is incomplete, however, this is inline with
-		// the behavior of `oc config unset current-context`
-		rc.CurrentContext = ""
+	return savedContext, nil
+}
 
-		pathOptions := clientcmd.NewDefaultPathOptions()
-		err = clientcmd.ModifyConfig(pathOptions, rc, true)
+// runPrune removes every backplane context from the kubeconfig when --all is set,
+// and the stale cluster specific kubeconfigs when --older-than is set
+func runPrune() error {
+	if args.all {
+		var contexts []string
+		var err error
+		if args.dryRun {
+			var rc api.Config
+			rc, err = utils.ReadKubeconfigRaw()
+			contexts = pruneBackplaneContexts(&rc)
+		} else {
+			// The kubeconfig isn't written when no context is removed, as it is unchanged
+			err = utils.UpdateDefaultKubeConfig(func(rc *api.Config) error {
+				contexts = pruneBackplaneContexts(rc)
+				return nil
+			})
+		}
 		if err != nil {
 			return err
 		}
-		logger.Debugln("Kubeconfig written")
-		fmt.Printf("Logged out from backplane: %s\n", savedContext)
+
+		if len(contexts) == 0 {
+			fmt.Println("No backplane context found in kubeconfig")
+		}
+		for _, context := range contexts {
+			if args.dryRun {
+				fmt.Printf("Would remove context: %s\n", context)
+			} else {
+				fmt.Printf("Removed context: %s\n", context)
+			}
+		}
+	}
+
+	if args.olderThan > 0 {
+		kubeConfigs, err := login.ListClusterKubeConfigs()
+		if err != nil {
+			return err
+		}
+
+		for _, kubeConfig := range kubeConfigs {
+			if time.Since(kubeConfig.ModTime) < args.olderThan {
+				continue
+			}
+
+			if args.dryRun {
+				fmt.Printf("Would remove kubeconfig: %s\n", kubeConfig.Path)
+				continue
+			}
+
+			err = login.RemoveClusterKubeConfig(kubeConfig.ClusterID)
+			if err != nil {
+				return err
+			}
+			fmt.Printf("Removed kubeconfig: %s\n", kubeConfig.Path)
+		}
 	}
 
 	return nil
 }
+
+// pruneBackplaneContexts deletes the contexts whose cluster server is a backplane API URL,
+// with their clusters and the users no other context refers to.
+// It returns the names of the deleted contexts.
+func pruneBackplaneContexts(rc *api.Config) []string {
+	backplaneServerRegex := regexp.MustCompile(utils.BackplaneAPIURLRegexp)
+
+	contexts := []string{}
+	for name, context := range rc.Contexts {
+		cluster := rc.Clusters[context.Cluster]
+		if cluster != nil && backplaneServerRegex.MatchString(cluster.Server) {
+			contexts = append(contexts, name)
+		}
+	}
+	sort.Strings(contexts)
+
+	users := map[string]bool{}
+	for _, name := range contexts {
+		users[rc.Contexts[name].AuthInfo] = true
+		delete(rc.Clusters, rc.Contexts[name].Cluster)
+		delete(rc.Contexts, name)
+
+		if rc.CurrentContext == name {
+			rc.CurrentContext = ""
+		}
+	}
+
+	// Keep the users still referred to by a remaining context
+	for _, context := range rc.Contexts {
+		delete(users, context.AuthInfo)
+	}
+	for user := range users {
+		delete(rc.AuthInfos, user)
+	}
+
+	return contexts
+}
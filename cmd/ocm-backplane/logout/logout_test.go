@@ -4,11 +4,14 @@ import (
 	"io"
 	"net/http"
 	"os"
+	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/golang/mock/gomock"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
 	"github.com/spf13/cobra"
 	"k8s.io/client-go/tools/clientcmd"
 	"k8s.io/client-go/tools/clientcmd/api"
@@ -16,6 +19,7 @@ import (
 	"github.com/openshift/backplane-cli/cmd/ocm-backplane/login"
 	"github.com/openshift/backplane-cli/pkg/client/mocks"
 	"github.com/openshift/backplane-cli/pkg/info"
+	bplogin "github.com/openshift/backplane-cli/pkg/login"
 	"github.com/openshift/backplane-cli/pkg/utils"
 	mocks2 "github.com/openshift/backplane-cli/pkg/utils/mocks"
 )
@@ -28,6 +32,8 @@ func MakeIoReader(s string) io.ReadCloser {
 var _ = Describe("Logout command", func() {
 
 	var (
+		configDir string
+
 		mockCtrl           *gomock.Controller
 		mockClient         *mocks.MockClientInterface
 		mockClientWithResp *mocks.MockClientWithResponsesInterface
@@ -39,7 +45,8 @@ var _ = Describe("Logout command", func() {
 		trueClusterID   string
 		backplaneAPIURI string
 
-		fakeResp *http.Response
+		fakeResp    *http.Response
+		clusterInfo *cmv1.Cluster
 
 		loginCmd *cobra.Command
 
@@ -72,6 +79,8 @@ var _ = Describe("Logout command", func() {
 		}
 		fakeResp.Header.Add("Content-Type", "json")
 
+		clusterInfo, _ = cmv1.NewCluster().ID(trueClusterID).Name(testClusterID).State(cmv1.ClusterStateReady).Build()
+
 		loginCmd = login.LoginCmd
 
 		kubeConfig = api.Config{
@@ -111,11 +120,20 @@ var _ = Describe("Logout command", func() {
 		}
 
 		os.Setenv(info.BackplaneURLEnvName, backplaneAPIURI)
+		var err error
+		configDir, err = os.MkdirTemp("", "backplane")
+		Expect(err).To(BeNil())
+		os.Setenv(info.BackplaneConfigPathEnvName, filepath.Join(configDir, "config.json"))
 	})
 
 	AfterEach(func() {
 		utils.RemoveTempKubeConfig()
 		os.Setenv(info.BackplaneURLEnvName, "")
+		os.RemoveAll(configDir)
+		os.Unsetenv(info.BackplaneConfigPathEnvName)
+		args.all = false
+		args.olderThan = 0
+		args.dryRun = false
 		mockCtrl.Finish()
 	})
 
@@ -126,7 +144,7 @@ var _ = Describe("Logout command", func() {
 			err := utils.CreateTempKubeConfig(&kubeConfig)
 			Expect(err).To(BeNil())
 			mockOcmInterface.EXPECT().GetTargetCluster(testClusterID).Return(trueClusterID, testClusterID, nil).AnyTimes()
-			mockOcmInterface.EXPECT().IsClusterHibernating(gomock.Eq(trueClusterID)).Return(false, nil).AnyTimes()
+			mockOcmInterface.EXPECT().GetClusterInfoByID(gomock.Eq(trueClusterID)).Return(clusterInfo, nil).AnyTimes()
 			mockOcmInterface.EXPECT().GetOCMAccessToken().Return(&testToken, nil).AnyTimes()
 			mockClientUtil.EXPECT().MakeRawBackplaneAPIClientWithAccessToken(backplaneAPIURI, testToken).Return(mockClient, nil).AnyTimes()
 			mockClient.EXPECT().LoginCluster(gomock.Any(), gomock.Eq(trueClusterID)).Return(fakeResp, nil).AnyTimes()
@@ -189,4 +207,125 @@ var _ = Describe("Logout command", func() {
 
 		})
 	})
+
+	Context("Test logout --all", func() {
+
+		var mixedConfig api.Config
+
+		BeforeEach(func() {
+			mixedConfig = api.Config{
+				Kind:       "Config",
+				APIVersion: "v1",
+				Clusters: map[string]*api.Cluster{
+					"cluster1":           {Server: "https://api.backplane.example.com/backplane/cluster/cluster1"},
+					"cluster2":           {Server: "https://api.integration.backplane.example.com/backplane/cluster/cluster2"},
+					"myopenshiftcluster": {Server: "https://myopenshiftcluster.openshiftapps.com"},
+				},
+				AuthInfos: map[string]*api.AuthInfo{
+					"anonymous":           {Token: "token"},
+					"example.openshift":   {Token: "token"},
+					"backplane-only-user": {Token: "token"},
+				},
+				Contexts: map[string]*api.Context{
+					"default/cluster1/anonymous":                   {Cluster: "cluster1", AuthInfo: "backplane-only-user"},
+					"default/cluster2/anonymous":                   {Cluster: "cluster2", AuthInfo: "anonymous"},
+					"default/myopenshiftcluster/example.openshift": {Cluster: "myopenshiftcluster", AuthInfo: "example.openshift"},
+					"default/myopenshiftcluster/anonymous":         {Cluster: "myopenshiftcluster", AuthInfo: "anonymous"},
+				},
+				CurrentContext: "default/cluster1/anonymous",
+			}
+		})
+
+		It("should remove every backplane context", func() {
+			err := utils.CreateTempKubeConfig(&mixedConfig)
+			Expect(err).To(BeNil())
+			args.all = true
+
+			err = runLogout(nil, nil)
+			Expect(err).To(BeNil())
+
+			after, err := utils.ReadKubeconfigRaw()
+			Expect(err).To(BeNil())
+			Expect(after.Contexts).To(HaveLen(2))
+			Expect(after.Contexts).To(HaveKey("default/myopenshiftcluster/example.openshift"))
+			Expect(after.Clusters).To(HaveLen(1))
+			Expect(after.Clusters).To(HaveKey("myopenshiftcluster"))
+			// anonymous is still used by a non backplane context
+			Expect(after.AuthInfos).To(HaveKey("anonymous"))
+			Expect(after.AuthInfos).NotTo(HaveKey("backplane-only-user"))
+			Expect(after.CurrentContext).To(BeEmpty())
+		})
+
+		It("should not alter the kubeconfig with --dry-run", func() {
+			err := utils.CreateTempKubeConfig(&mixedConfig)
+			Expect(err).To(BeNil())
+			args.all = true
+			args.dryRun = true
+
+			initial, err := utils.ReadKubeconfigRaw()
+			Expect(err).To(BeNil())
+
+			err = runLogout(nil, nil)
+			Expect(err).To(BeNil())
+
+			after, err := utils.ReadKubeconfigRaw()
+			Expect(err).To(BeNil())
+			Expect(initial).To(Equal(after))
+		})
+
+		It("should fail for --dry-run alone", func() {
+			args.dryRun = true
+
+			err := runLogout(nil, nil)
+			Expect(err).NotTo(BeNil())
+			Expect(err.Error()).To(Equal("--dry-run requires --all or --older-than"))
+		})
+	})
+
+	Context("Test logout --older-than", func() {
+
+		var kubePath string
+
+		BeforeEach(func() {
+			var err error
+			kubePath, err = os.MkdirTemp("", ".kube")
+			Expect(err).To(BeNil())
+			Expect(bplogin.SetKubeConfigBasePath(kubePath)).To(Succeed())
+
+			for _, clusterID := range []string{"stale", "fresh"} {
+				_, err = bplogin.CreateClusterKubeConfig(clusterID, kubeConfig)
+				Expect(err).To(BeNil())
+			}
+			old := time.Now().Add(-48 * time.Hour)
+			Expect(os.Chtimes(filepath.Join(kubePath, "stale", "config"), old, old)).To(Succeed())
+		})
+
+		AfterEach(func() {
+			os.RemoveAll(kubePath)
+			os.Unsetenv(info.BackplaneKubeconfigEnvName)
+		})
+
+		It("should only list the stale kubeconfigs with --dry-run", func() {
+			args.olderThan = 24 * time.Hour
+			args.dryRun = true
+
+			err := runLogout(nil, nil)
+			Expect(err).To(BeNil())
+
+			_, err = os.Stat(filepath.Join(kubePath, "stale", "config"))
+			Expect(err).To(BeNil())
+		})
+
+		It("should remove the stale kubeconfigs", func() {
+			args.olderThan = 24 * time.Hour
+
+			err := runLogout(nil, nil)
+			Expect(err).To(BeNil())
+
+			_, err = os.Stat(filepath.Join(kubePath, "stale"))
+			Expect(os.IsNotExist(err)).To(BeTrue())
+			_, err = os.Stat(filepath.Join(kubePath, "fresh", "config"))
+			Expect(err).To(BeNil())
+		})
+	})
 })
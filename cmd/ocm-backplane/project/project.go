@@ -0,0 +1,180 @@
+package project
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+
+	logger "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/client-go/tools/clientcmd/api"
+
+	"github.com/openshift/backplane-cli/pkg/login"
+	"github.com/openshift/backplane-cli/pkg/utils"
+)
+
+var (
+	projectArgs struct {
+		clusterKey string
+	}
+
+	// For mocking
+	createClientSet = func(c *rest.Config) (kubernetes.Interface, error) { return kubernetes.NewForConfig(c) }
+
+	// ProjectCmd represents the project command
+	ProjectCmd = &cobra.Command{
+		Use:   "project [NAMESPACE]",
+		Short: "Switch to another namespace of the current backplane cluster",
+		Long: `Project command sets the namespace of the current backplane kubeconfig context.
+Without argument, it lists the namespaces of the cluster and marks the current one.
+The namespace is validated through the backplane proxy before switching.
+Use --cluster-id to update the kubeconfig written by 'login --multi' for the given cluster.`,
+		Example:      " backplane project openshift-monitoring\n backplane project\n backplane project openshift-monitoring --cluster-id <id>",
+		Args:         cobra.MaximumNArgs(1),
+		RunE:         runProject,
+		SilenceUsage: true,
+	}
+)
+
+func init() {
+	flags := ProjectCmd.Flags()
+
+	flags.StringVarP(
+		&projectArgs.clusterKey,
+		"cluster-id",
+		"c",
+		"",
+		"Use the kubeconfig written by multi-cluster login for the given cluster.",
+	)
+}
+
+func runProject(cmd *cobra.Command, argv []string) error {
+	// Load the kubeconfig to update, the default one or the one of the given cluster
+	kubeConfigPath := ""
+	if projectArgs.clusterKey != "" {
+		clusterID, _, err := utils.DefaultOCMInterface.GetTargetCluster(projectArgs.clusterKey)
+		if err != nil {
+			return err
+		}
+		kubeConfigPath, err = login.GetClusterKubeConfigPath(clusterID)
+		if err != nil {
+			return err
+		}
+	}
+
+	rc, err := readKubeConfig(kubeConfigPath)
+	if err != nil {
+		return err
+	}
+
+	currentContextObj := rc.Contexts[rc.CurrentContext]
+	if currentContextObj == nil {
+		return fmt.Errorf("current context does not exist")
+	}
+	currentClusterObj := rc.Clusters[currentContextObj.Cluster]
+	if currentClusterObj == nil {
+		return fmt.Errorf("current cluster not found")
+	}
+
+	// backplane should only handle the context created with backplane itself,
+	// we check this via matching the cluster server endpoint
+	backplaneServerRegex := regexp.MustCompile(utils.BackplaneAPIURLRegexp)
+	if !backplaneServerRegex.MatchString(currentClusterObj.Server) {
+		return fmt.Errorf("you're not logged in using backplane, skipping")
+	}
+
+	restConfig, err := clientcmd.NewDefaultClientConfig(rc, &clientcmd.ConfigOverrides{}).ClientConfig()
+	if err != nil {
+		return err
+	}
+	clientSet, err := createClientSet(restConfig)
+	if err != nil {
+		return err
+	}
+
+	if len(argv) == 0 {
+		return listProjects(clientSet, currentContextObj.Namespace)
+	}
+
+	namespace := argv[0]
+
+	// Validate the namespace through backplane before switching
+	logger.WithField("Namespace", namespace).Debugln("Validating namespace")
+	_, err = clientSet.CoreV1().Namespaces().Get(context.TODO(), namespace, metav1.GetOptions{})
+	if err != nil {
+		if k8serrors.IsNotFound(err) {
+			return fmt.Errorf("namespace %s does not exist", namespace)
+		}
+		return fmt.Errorf("unable to validate namespace %s: %v", namespace, err)
+	}
+
+	err = setContextNamespace(kubeConfigPath, rc.CurrentContext, namespace)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Now using project \"%s\" on cluster \"%s\".\n", namespace, currentContextObj.Cluster)
+
+	return nil
+}
+
+// listProjects prints the namespaces of the cluster and marks the current one
+func listProjects(clientSet kubernetes.Interface, currentNamespace string) error {
+	namespaces, err := clientSet.CoreV1().Namespaces().List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		return fmt.Errorf("unable to list namespaces: %v", err)
+	}
+
+	names := []string{}
+	for _, ns := range namespaces.Items {
+		names = append(names, ns.Name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		marker := " "
+		if name == currentNamespace {
+			marker = "*"
+		}
+		fmt.Printf("%s %s\n", marker, name)
+	}
+
+	return nil
+}
+
+// readKubeConfig returns the kubeconfig of the given path, or the default kubeconfig if path is empty
+func readKubeConfig(path string) (api.Config, error) {
+	if path == "" {
+		return utils.ReadKubeconfigRaw()
+	}
+
+	cfg, err := clientcmd.LoadFromFile(path)
+	if err != nil {
+		return api.Config{}, fmt.Errorf("unable to read kubeconfig %s: %v", path, err)
+	}
+	return *cfg, nil
+}
+
+// setContextNamespace sets the namespace of the given context in the kubeconfig of the given path,
+// or in the default kubeconfig if path is empty
+func setContextNamespace(path, contextName, namespace string) error {
+	update := func(rc *api.Config) error {
+		context := rc.Contexts[contextName]
+		if context == nil {
+			return fmt.Errorf("context %s does not exist", contextName)
+		}
+		context.Namespace = namespace
+		return nil
+	}
+
+	if path == "" {
+		return utils.UpdateDefaultKubeConfig(update)
+	}
+	return utils.UpdateKubeConfig(path, update)
+}
@@ -0,0 +1,136 @@
+package project
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	testclient "k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/client-go/tools/clientcmd/api"
+
+	"github.com/openshift/backplane-cli/pkg/login"
+	"github.com/openshift/backplane-cli/pkg/utils"
+	mocks2 "github.com/openshift/backplane-cli/pkg/utils/mocks"
+)
+
+var _ = Describe("Project command", func() {
+
+	var (
+		mockCtrl         *gomock.Controller
+		mockOcmInterface *mocks2.MockOCMInterface
+
+		kubeConfig api.Config
+	)
+
+	BeforeEach(func() {
+		mockCtrl = gomock.NewController(GinkgoT())
+		mockOcmInterface = mocks2.NewMockOCMInterface(mockCtrl)
+		utils.DefaultOCMInterface = mockOcmInterface
+
+		createClientSet = func(c *rest.Config) (kubernetes.Interface, error) {
+			return testclient.NewSimpleClientset(
+				&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "default"}},
+				&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "openshift-monitoring"}},
+			), nil
+		}
+
+		kubeConfig = api.Config{
+			Kind:        "Config",
+			APIVersion:  "v1",
+			Preferences: api.Preferences{},
+			Clusters: map[string]*api.Cluster{
+				"testcluster": {
+					Server: "https://api.integration.backplane.example.com/backplane/cluster/test123",
+				},
+			},
+			AuthInfos: map[string]*api.AuthInfo{
+				"anonymous": {},
+			},
+			Contexts: map[string]*api.Context{
+				"default/testcluster/anonymous": {
+					Cluster:   "testcluster",
+					Namespace: "default",
+					AuthInfo:  "anonymous",
+				},
+			},
+			CurrentContext: "default/testcluster/anonymous",
+		}
+		projectArgs.clusterKey = ""
+	})
+
+	AfterEach(func() {
+		mockCtrl.Finish()
+		utils.RemoveTempKubeConfig()
+	})
+
+	Context("switch project", func() {
+		It("should set the namespace of the current context", func() {
+			err := utils.CreateTempKubeConfig(&kubeConfig)
+			Expect(err).To(BeNil())
+
+			err = runProject(nil, []string{"openshift-monitoring"})
+			Expect(err).To(BeNil())
+
+			cfg, err := utils.ReadKubeconfigRaw()
+			Expect(err).To(BeNil())
+			Expect(cfg.Contexts[cfg.CurrentContext].Namespace).To(Equal("openshift-monitoring"))
+		})
+
+		It("should fail if the namespace does not exist", func() {
+			err := utils.CreateTempKubeConfig(&kubeConfig)
+			Expect(err).To(BeNil())
+
+			err = runProject(nil, []string{"not-exist"})
+			Expect(err).NotTo(BeNil())
+			Expect(err.Error()).To(Equal("namespace not-exist does not exist"))
+
+			cfg, err := utils.ReadKubeconfigRaw()
+			Expect(err).To(BeNil())
+			Expect(cfg.Contexts[cfg.CurrentContext].Namespace).To(Equal("default"))
+		})
+
+		It("should fail if the current context is not a backplane one", func() {
+			kubeConfig.Clusters["testcluster"].Server = "https://api.example.com"
+			err := utils.CreateTempKubeConfig(&kubeConfig)
+			Expect(err).To(BeNil())
+
+			err = runProject(nil, []string{"openshift-monitoring"})
+			Expect(err).NotTo(BeNil())
+		})
+
+		It("should list the namespaces", func() {
+			err := utils.CreateTempKubeConfig(&kubeConfig)
+			Expect(err).To(BeNil())
+
+			err = runProject(nil, []string{})
+			Expect(err).To(BeNil())
+		})
+
+		It("should update the kubeconfig of the given cluster", func() {
+			kubePath, err := os.MkdirTemp("", ".kube")
+			Expect(err).To(BeNil())
+			defer os.RemoveAll(kubePath)
+			err = login.SetKubeConfigBasePath(kubePath)
+			Expect(err).To(BeNil())
+			_, err = login.CreateClusterKubeConfig("test123", kubeConfig)
+			Expect(err).To(BeNil())
+
+			projectArgs.clusterKey = "test123"
+			mockOcmInterface.EXPECT().GetTargetCluster("test123").Return("test123", "testcluster", nil)
+
+			err = runProject(nil, []string{"openshift-monitoring"})
+			Expect(err).To(BeNil())
+
+			cfg, err := clientcmd.LoadFromFile(filepath.Join(kubePath, "test123", "config"))
+			Expect(err).To(BeNil())
+			Expect(cfg.Contexts[cfg.CurrentContext].Namespace).To(Equal("openshift-monitoring"))
+		})
+	})
+})
@@ -0,0 +1,91 @@
+package ps1
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"time"
+
+	logger "github.com/sirupsen/logrus"
+
+	"github.com/openshift/backplane-cli/pkg/cli/config"
+	"github.com/openshift/backplane-cli/pkg/info"
+)
+
+// fileStamp identifies the version of a file the prompt segment is built from
+type fileStamp struct {
+	Path    string    `json:"path"`
+	ModTime time.Time `json:"mod_time"`
+	Size    int64     `json:"size"`
+}
+
+// promptCache is the last prompt info built, with the files it was built from
+type promptCache struct {
+	Files []fileStamp `json:"files"`
+	Info  promptInfo  `json:"info"`
+}
+
+// newFileStamps returns the current stamps of the given files, a missing file has an empty stamp
+func newFileStamps(paths []string) []fileStamp {
+	stamps := make([]fileStamp, 0, len(paths))
+	for _, path := range paths {
+		stamp := fileStamp{Path: path}
+		if fileInfo, err := os.Stat(path); err == nil {
+			stamp.ModTime = fileInfo.ModTime().UTC()
+			stamp.Size = fileInfo.Size()
+		}
+		stamps = append(stamps, stamp)
+	}
+	return stamps
+}
+
+// readCachedPromptInfo returns the cached prompt info if it was built from the same files
+func readCachedPromptInfo(stamps []fileStamp) (promptInfo, bool) {
+	path, err := config.GetConfigDirFilePath(info.BackplanePS1CacheFileName)
+	if err != nil {
+		return promptInfo{}, false
+	}
+
+	content, err := os.ReadFile(filepath.Clean(path))
+	if err != nil {
+		return promptInfo{}, false
+	}
+
+	cache := promptCache{}
+	if err := json.Unmarshal(content, &cache); err != nil {
+		return promptInfo{}, false
+	}
+
+	if len(cache.Files) != len(stamps) {
+		return promptInfo{}, false
+	}
+	for i, stamp := range stamps {
+		cached := cache.Files[i]
+		if cached.Path != stamp.Path || !cached.ModTime.Equal(stamp.ModTime) || cached.Size != stamp.Size {
+			return promptInfo{}, false
+		}
+	}
+	return cache.Info, true
+}
+
+// writeCachedPromptInfo saves the prompt info built from the given files.
+// Failing to save the cache only makes the next prompt slower.
+func writeCachedPromptInfo(stamps []fileStamp, prompt promptInfo) {
+	path, err := config.GetConfigDirFilePath(info.BackplanePS1CacheFileName)
+	if err != nil {
+		return
+	}
+
+	content, err := json.Marshal(promptCache{Files: stamps, Info: prompt})
+	if err != nil {
+		return
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0750); err != nil {
+		logger.Debugf("Unable to save the prompt cache: %v", err)
+		return
+	}
+	if err := os.WriteFile(path, content, 0600); err != nil {
+		logger.Debugf("Unable to save the prompt cache: %v", err)
+	}
+}
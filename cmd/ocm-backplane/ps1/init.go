@@ -0,0 +1,76 @@
+package ps1
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+const (
+	// bashInitScript makes the backplane segment the kube-ps1 cluster function, and prepends it to PS1
+	// when kube-ps1 isn't loaded
+	bashInitScript = `# backplane prompt segment, added by "ocm backplane ps1 init bash"
+__backplane_ps1() {
+  # kube-ps1 shows the namespace itself
+  ocm-backplane ps1 --namespace=false 2>/dev/null
+}
+export KUBE_PS1_CLUSTER_FUNCTION=__backplane_ps1
+if ! type kube_ps1 >/dev/null 2>&1; then
+  __backplane_ps1_prompt() {
+    local segment
+    segment="$(ocm-backplane ps1 2>/dev/null)"
+    [ -n "$segment" ] && printf '(%s) ' "$segment"
+  }
+  PS1='$(__backplane_ps1_prompt)'"$PS1"
+fi
+`
+
+	// zshInitScript makes the backplane segment the kube-ps1 cluster function, and prepends it to PROMPT
+	// when kube-ps1 isn't loaded
+	zshInitScript = `# backplane prompt segment, added by "ocm backplane ps1 init zsh"
+__backplane_ps1() {
+  # kube-ps1 shows the namespace itself
+  ocm-backplane ps1 --namespace=false 2>/dev/null
+}
+export KUBE_PS1_CLUSTER_FUNCTION=__backplane_ps1
+if (( ! $+functions[kube_ps1] )); then
+  __backplane_ps1_prompt() {
+    local segment
+    segment="$(ocm-backplane ps1 2>/dev/null)"
+    [[ -n "$segment" ]] && printf '(%s) ' "$segment"
+  }
+  setopt PROMPT_SUBST
+  PROMPT='$(__backplane_ps1_prompt)'"$PROMPT"
+fi
+`
+)
+
+var initScripts = map[string]string{
+	"bash": bashInitScript,
+	"zsh":  zshInitScript,
+}
+
+func newInitCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "init <bash|zsh>",
+		Short: "Print the shell snippet adding the backplane prompt segment",
+		Long: `Print the shell snippet adding the backplane prompt segment to the shell prompt.
+When kube-ps1 is loaded, the segment is used as its cluster function, otherwise it is prepended to the prompt.
+Append the snippet to the shell rc file after kube-ps1 is loaded.`,
+		Example:      " backplane ps1 init bash >> ~/.bashrc\n backplane ps1 init zsh >> ~/.zshrc",
+		Args:         cobra.ExactArgs(1),
+		ValidArgs:    []string{"bash", "zsh"},
+		SilenceUsage: true,
+		RunE:         runInit,
+	}
+}
+
+func runInit(cmd *cobra.Command, argv []string) error {
+	script, ok := initScripts[argv[0]]
+	if !ok {
+		return fmt.Errorf("unsupported shell %s, supported shells are bash|zsh", argv[0])
+	}
+
+	fmt.Fprint(cmd.OutOrStdout(), script)
+	return nil
+}
@@ -0,0 +1,194 @@
+package ps1
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+
+	logger "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/client-go/tools/clientcmd/api"
+
+	"github.com/openshift/backplane-cli/pkg/login"
+	"github.com/openshift/backplane-cli/pkg/utils"
+)
+
+const (
+	// elevatedUser is the user impersonated by backplane elevate
+	elevatedUser = "backplane-cluster-admin"
+
+	productionMarker = "PROD"
+	elevatedMarker   = "[elevated]"
+)
+
+// promptInfo is what the prompt segment shows about the current context, it is empty
+// when the current context isn't a backplane one
+type promptInfo struct {
+	Cluster    string `json:"cluster"`
+	Mode       string `json:"mode,omitempty"`
+	Namespace  string `json:"namespace,omitempty"`
+	Production bool   `json:"production,omitempty"`
+	Elevated   bool   `json:"elevated,omitempty"`
+}
+
+func NewPS1Cmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "ps1",
+		Short: "Print the backplane prompt segment of the current cluster",
+		Long: `Print a short prompt segment describing the current backplane cluster, to be used in the shell prompt.
+The segment contains the cluster name and namespace, whether the cluster was logged into as a management
+or service cluster, whether the privileges are elevated, and whether the cluster is a production cluster.
+It is built only from local files, with no call to OCM or backplane, and nothing is printed when the
+current context isn't a backplane one. Run "backplane ps1 init bash|zsh" to install it in the shell.`,
+		Example:      " backplane ps1\n backplane ps1 --namespace=false\n backplane ps1 init bash >> ~/.bashrc",
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		RunE:         runPS1,
+	}
+
+	cmd.Flags().Bool(
+		"namespace",
+		true,
+		"Show the namespace of the context in the segment.",
+	)
+
+	cmd.AddCommand(newInitCmd())
+	return cmd
+}
+
+func runPS1(cmd *cobra.Command, argv []string) error {
+	withNamespace, err := cmd.Flags().GetBool("namespace")
+	if err != nil {
+		return err
+	}
+
+	info, err := getPromptInfo()
+	if err != nil {
+		// A broken prompt is worse than an empty one
+		logger.Debugf("Unable to build the prompt segment: %v", err)
+		return nil
+	}
+
+	if segment := info.segment(withNamespace); segment != "" {
+		fmt.Fprintln(cmd.OutOrStdout(), segment)
+	}
+	return nil
+}
+
+// getPromptInfo returns the prompt info of the current kubeconfig context,
+// from the cache when the kubeconfig and the login history haven't changed
+func getPromptInfo() (promptInfo, error) {
+	loadingRules := clientcmd.NewDefaultClientConfigLoadingRules()
+
+	historyPath, err := login.GetHistoryFilePath()
+	if err != nil {
+		return promptInfo{}, err
+	}
+
+	paths := append([]string{}, loadingRules.GetLoadingPrecedence()...)
+	stamps := newFileStamps(append(paths, historyPath))
+	if info, ok := readCachedPromptInfo(stamps); ok {
+		return info, nil
+	}
+
+	rc, err := loadingRules.Load()
+	if err != nil {
+		return promptInfo{}, err
+	}
+
+	history, err := login.ReadHistory()
+	if err != nil {
+		logger.Debugf("Unable to read the login history: %v", err)
+	}
+
+	info := buildPromptInfo(*rc, history)
+	writeCachedPromptInfo(stamps, info)
+
+	return info, nil
+}
+
+// buildPromptInfo returns the prompt info of the current context of the kubeconfig
+func buildPromptInfo(rc api.Config, history []login.HistoryEntry) promptInfo {
+	currentContext := rc.Contexts[rc.CurrentContext]
+	if currentContext == nil {
+		return promptInfo{}
+	}
+	currentCluster := rc.Clusters[currentContext.Cluster]
+	if currentCluster == nil {
+		return promptInfo{}
+	}
+
+	backplaneServerRegex := regexp.MustCompile(utils.BackplaneAPIURLRegexp)
+	if !backplaneServerRegex.MatchString(currentCluster.Server) {
+		return promptInfo{}
+	}
+
+	info := promptInfo{
+		Cluster:    currentContext.Cluster,
+		Namespace:  currentContext.Namespace,
+		Production: isProductionServer(currentCluster.Server),
+	}
+
+	// The login history tells whether the cluster was logged into as a management or service cluster
+	clusterID, _, err := utils.DefaultClusterUtils.GetClusterIDAndHostFromClusterURL(currentCluster.Server)
+	if err == nil {
+		for _, entry := range history {
+			if entry.ClusterID == clusterID {
+				info.Mode = entry.Mode
+				break
+			}
+		}
+	}
+
+	if user := rc.AuthInfos[currentContext.AuthInfo]; user != nil && user.Impersonate == elevatedUser {
+		info.Elevated = true
+	}
+
+	return info
+}
+
+// segment formats the prompt info, like "PROD my-cluster(manager):my-namespace [elevated]"
+func (i promptInfo) segment(withNamespace bool) string {
+	if i.Cluster == "" {
+		return ""
+	}
+
+	segments := []string{}
+	if i.Production {
+		segments = append(segments, productionMarker)
+	}
+
+	cluster := i.Cluster
+	if i.Mode != "" {
+		cluster += "(" + i.Mode + ")"
+	}
+	if withNamespace && i.Namespace != "" {
+		cluster += ":" + i.Namespace
+	}
+	segments = append(segments, cluster)
+
+	if i.Elevated {
+		segments = append(segments, elevatedMarker)
+	}
+
+	return strings.Join(segments, " ")
+}
+
+// isProductionServer checks whether the given backplane cluster server belongs to the production
+// backplane API, whose host has no environment like api.stage.backplane... or api.integration.backplane...
+func isProductionServer(server string) bool {
+	u, err := url.Parse(server)
+	if err != nil {
+		return false
+	}
+
+	for _, label := range strings.Split(u.Hostname(), ".") {
+		switch label {
+		case "stage", "staging", "integration", "int":
+			return false
+		}
+	}
+	return true
+}
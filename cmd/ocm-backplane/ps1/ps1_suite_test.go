@@ -0,0 +1,13 @@
+package ps1
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+func TestPS1(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "PS1 Test Suite")
+}
@@ -0,0 +1,129 @@
+package ps1
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"k8s.io/client-go/tools/clientcmd/api"
+
+	"github.com/openshift/backplane-cli/pkg/info"
+	"github.com/openshift/backplane-cli/pkg/login"
+	"github.com/openshift/backplane-cli/pkg/utils"
+)
+
+func newBackplaneKubeConfig(server string) *api.Config {
+	rc := api.NewConfig()
+	rc.Clusters["my-cluster"] = &api.Cluster{Server: server}
+	rc.AuthInfos["user"] = &api.AuthInfo{}
+	rc.Contexts["ctx"] = &api.Context{Cluster: "my-cluster", AuthInfo: "user", Namespace: "default"}
+	rc.CurrentContext = "ctx"
+	return rc
+}
+
+var _ = Describe("ps1 command", func() {
+
+	const (
+		prodServer  = "https://api.backplane.openshift.com/backplane/cluster/abc123"
+		stageServer = "https://api.stage.backplane.openshift.com/backplane/cluster/abc123"
+	)
+
+	var configDir string
+
+	BeforeEach(func() {
+		var err error
+		configDir, err = os.MkdirTemp("", "backplane")
+		Expect(err).To(BeNil())
+		os.Setenv(info.BackplaneConfigPathEnvName, filepath.Join(configDir, "config.json"))
+	})
+
+	AfterEach(func() {
+		os.Unsetenv(info.BackplaneConfigPathEnvName)
+		os.RemoveAll(configDir)
+		utils.RemoveTempKubeConfig()
+	})
+
+	Context("build the prompt segment", func() {
+		It("should be empty when the current context isn't a backplane one", func() {
+			rc := newBackplaneKubeConfig("https://api.example.com:6443")
+
+			Expect(buildPromptInfo(*rc, nil).segment(true)).To(BeEmpty())
+		})
+
+		It("should show the cluster name and the namespace", func() {
+			rc := newBackplaneKubeConfig(stageServer)
+
+			Expect(buildPromptInfo(*rc, nil).segment(true)).To(Equal("my-cluster:default"))
+			Expect(buildPromptInfo(*rc, nil).segment(false)).To(Equal("my-cluster"))
+		})
+
+		It("should show the production, manager and elevated markers", func() {
+			rc := newBackplaneKubeConfig(prodServer)
+			rc.AuthInfos["user"].Impersonate = "backplane-cluster-admin"
+			history := []login.HistoryEntry{
+				{ClusterID: "other", Mode: login.HistoryModeService},
+				{ClusterID: "abc123", Mode: login.HistoryModeManager},
+			}
+
+			Expect(buildPromptInfo(*rc, history).segment(true)).To(Equal("PROD my-cluster(manager):default [elevated]"))
+		})
+	})
+
+	Context("run the command", func() {
+		It("should print the segment of the current kubeconfig and cache it", func() {
+			Expect(utils.CreateTempKubeConfig(newBackplaneKubeConfig(stageServer))).To(Succeed())
+
+			out := &bytes.Buffer{}
+			cmd := NewPS1Cmd()
+			cmd.SetOut(out)
+			cmd.SetArgs([]string{})
+			Expect(cmd.Execute()).To(Succeed())
+			Expect(out.String()).To(Equal("my-cluster:default\n"))
+
+			cache, err := os.ReadFile(filepath.Join(configDir, info.BackplanePS1CacheFileName))
+			Expect(err).To(BeNil())
+			Expect(string(cache)).To(ContainSubstring(`"cluster":"my-cluster"`))
+
+			// The cache is used while the files are unchanged
+			kubeConfig := os.Getenv(info.BackplaneKubeconfigEnvName)
+			stamps := newFileStamps([]string{kubeConfig, filepath.Join(configDir, info.BackplaneHistoryFileName)})
+			writeCachedPromptInfo(stamps, promptInfo{Cluster: "cached"})
+
+			out.Reset()
+			Expect(cmd.Execute()).To(Succeed())
+			Expect(out.String()).To(Equal("cached\n"))
+
+			// The cache is refreshed when the kubeconfig changes
+			Expect(utils.WriteKubeConfig(kubeConfig, *newBackplaneKubeConfig(prodServer))).To(Succeed())
+
+			out.Reset()
+			Expect(cmd.Execute()).To(Succeed())
+			Expect(out.String()).To(Equal("PROD my-cluster:default\n"))
+		})
+	})
+
+	Context("init the shell", func() {
+		It("should print the snippet of a supported shell", func() {
+			for _, shell := range []string{"bash", "zsh"} {
+				out := &bytes.Buffer{}
+				cmd := NewPS1Cmd()
+				cmd.SetOut(out)
+				cmd.SetArgs([]string{"init", shell})
+
+				Expect(cmd.Execute()).To(Succeed())
+				Expect(out.String()).To(ContainSubstring("KUBE_PS1_CLUSTER_FUNCTION=__backplane_ps1"))
+			}
+		})
+
+		It("should fail for an unsupported shell", func() {
+			cmd := NewPS1Cmd()
+			cmd.SetOut(&bytes.Buffer{})
+			cmd.SetErr(&bytes.Buffer{})
+			cmd.SetArgs([]string{"init", "tcsh"})
+
+			Expect(cmd.Execute()).To(MatchError("unsupported shell tcsh, supported shells are bash|zsh"))
+		})
+	})
+})
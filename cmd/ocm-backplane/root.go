@@ -17,26 +17,37 @@ limitations under the License.
 package main
 
 import (
+	"errors"
 	"os"
 
 	log "github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 
 	"github.com/openshift/backplane-cli/cmd/ocm-backplane/cloud"
+	"github.com/openshift/backplane-cli/cmd/ocm-backplane/cluster"
 	"github.com/openshift/backplane-cli/cmd/ocm-backplane/config"
 	"github.com/openshift/backplane-cli/cmd/ocm-backplane/console"
+	"github.com/openshift/backplane-cli/cmd/ocm-backplane/credential"
+	"github.com/openshift/backplane-cli/cmd/ocm-backplane/doctor"
 	"github.com/openshift/backplane-cli/cmd/ocm-backplane/elevate"
+	"github.com/openshift/backplane-cli/cmd/ocm-backplane/hcp"
+	"github.com/openshift/backplane-cli/cmd/ocm-backplane/history"
 	"github.com/openshift/backplane-cli/cmd/ocm-backplane/login"
 	"github.com/openshift/backplane-cli/cmd/ocm-backplane/logout"
 	"github.com/openshift/backplane-cli/cmd/ocm-backplane/managedJob"
 	"github.com/openshift/backplane-cli/cmd/ocm-backplane/monitoring"
+	"github.com/openshift/backplane-cli/cmd/ocm-backplane/project"
+	"github.com/openshift/backplane-cli/cmd/ocm-backplane/ps1"
 	"github.com/openshift/backplane-cli/cmd/ocm-backplane/script"
 	"github.com/openshift/backplane-cli/cmd/ocm-backplane/session"
 	"github.com/openshift/backplane-cli/cmd/ocm-backplane/status"
 	"github.com/openshift/backplane-cli/cmd/ocm-backplane/testJob"
 	"github.com/openshift/backplane-cli/cmd/ocm-backplane/upgrade"
 	"github.com/openshift/backplane-cli/cmd/ocm-backplane/version"
+	"github.com/openshift/backplane-cli/cmd/ocm-backplane/whoami"
 	"github.com/openshift/backplane-cli/pkg/cli/globalflags"
+	bpsession "github.com/openshift/backplane-cli/pkg/cli/session"
+	"github.com/openshift/backplane-cli/pkg/utils"
 )
 
 // rootCmd represents the base command when called without any subcommands
@@ -47,13 +58,27 @@ var rootCmd = &cobra.Command{
        The current function ocm-backplane provides is to login a cluster,
        which get a proxy url from backplane for the target cluster.
 	   After login, users can use oc command to operate the target cluster`,
-	SilenceErrors: true,
+	SilenceErrors:    true,
+	PersistentPreRun: applyGlobalOptions,
+}
+
+// globalOpts holds the global flags of the root command, shared by all commands
+var globalOpts = &globalflags.GlobalOptions{}
+
+// applyGlobalOptions configures the packages from the global flags, once they are parsed
+func applyGlobalOptions(cmd *cobra.Command, argv []string) {
+	utils.DefaultOCMInterface.SetNonInteractive(globalOpts.NonInteractive)
 }
 
 // Execute adds all child commands to the root command and sets flags appropriately.
 // This is called by main.main(). It only needs to happen once to the rootCmd.
 func Execute() {
 	if err := rootCmd.Execute(); err != nil {
+		// The command run in a session already reported its error, only its status is passed on
+		var commandErr *bpsession.CommandExitError
+		if errors.As(err, &commandErr) {
+			os.Exit(commandErr.Code)
+		}
 		log.Errorln(err.Error())
 		os.Exit(1)
 	}
@@ -63,19 +88,33 @@ func init() {
 	// Add Verbosity flag for all commands
 	globalflags.AddVerbosityFlag(rootCmd)
 
+	// Add non-interactive flag for all commands
+	globalflags.AddNonInteractiveFlag(rootCmd, globalOpts)
+
+	// Add configuration profile flag for all commands
+	globalflags.AddProfileFlag(rootCmd)
+
 	// Register sub-commands
 	rootCmd.AddCommand(console.ConsoleCmd)
 	rootCmd.AddCommand(config.NewConfigCmd())
 	rootCmd.AddCommand(cloud.CloudCmd)
+	rootCmd.AddCommand(cluster.NewClusterCmd())
+	rootCmd.AddCommand(credential.CredentialCmd)
+	rootCmd.AddCommand(doctor.NewDoctorCmd())
 	rootCmd.AddCommand(elevate.ElevateCmd)
+	rootCmd.AddCommand(hcp.NewHCPCmd())
+	rootCmd.AddCommand(history.HistoryCmd)
 	rootCmd.AddCommand(login.LoginCmd)
 	rootCmd.AddCommand(logout.LogoutCmd)
 	rootCmd.AddCommand(managedjob.NewManagedJobCmd())
+	rootCmd.AddCommand(project.ProjectCmd)
+	rootCmd.AddCommand(ps1.NewPS1Cmd())
 	rootCmd.AddCommand(script.NewScriptCmd())
 	rootCmd.AddCommand(status.StatusCmd)
 	rootCmd.AddCommand(session.NewCmdSession())
 	rootCmd.AddCommand(testjob.NewTestJobCommand())
 	rootCmd.AddCommand(upgrade.UpgradeCmd)
 	rootCmd.AddCommand(version.VersionCmd)
+	rootCmd.AddCommand(whoami.WhoamiCmd)
 	rootCmd.AddCommand(monitoring.MonitoringCmd)
 }
@@ -1,8 +1,12 @@
 package main
 
 import (
+	"github.com/golang/mock/gomock"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
+
+	"github.com/openshift/backplane-cli/pkg/utils"
+	mocks2 "github.com/openshift/backplane-cli/pkg/utils/mocks"
 )
 
 var _ = Describe("Backplane commands", func() {
@@ -22,5 +26,24 @@ var _ = Describe("Backplane commands", func() {
 			Expect(verbosityFlag.DefValue).To(Equal("warning"))
 
 		})
+
+		It("Check non-interactive persistent flag is applied after parsing", func() {
+			mockCtrl := gomock.NewController(GinkgoT())
+			mockOcmInterface := mocks2.NewMockOCMInterface(mockCtrl)
+			originalOCMInterface := utils.DefaultOCMInterface
+			utils.DefaultOCMInterface = mockOcmInterface
+			defer func() {
+				utils.DefaultOCMInterface = originalOCMInterface
+				globalOpts.NonInteractive = false
+				mockCtrl.Finish()
+			}()
+
+			// Parsing the flag doesn't change the cluster lookup, the pre-run does
+			Expect(rootCmd.PersistentFlags().Parse([]string{"--non-interactive"})).To(Succeed())
+			Expect(globalOpts.NonInteractive).To(BeTrue())
+
+			mockOcmInterface.EXPECT().SetNonInteractive(true)
+			rootCmd.PersistentPreRun(rootCmd, nil)
+		})
 	})
 })
@@ -0,0 +1,52 @@
+package session
+
+import (
+	"fmt"
+	"path/filepath"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/openshift/backplane-cli/pkg/cli/session"
+)
+
+func newInfoCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:               "info <session-alias>",
+		Short:             "Show the details of a backplane session",
+		Example:           " backplane session info my-session",
+		Args:              cobra.ExactArgs(1),
+		SilenceUsage:      true,
+		ValidArgsFunction: completeSessionAlias,
+		RunE:              runInfo,
+	}
+}
+
+func runInfo(cmd *cobra.Command, argv []string) error {
+	s, err := session.GetSession(argv[0])
+	if err != nil {
+		return err
+	}
+	recordings, err := session.ListRecordings(s)
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 2, ' ', 0)
+	fmt.Fprintf(w, "Alias:\t%s\n", s.Alias)
+	fmt.Fprintf(w, "Path:\t%s\n", s.Path)
+	fmt.Fprintf(w, "Cluster ID:\t%s\n", s.ClusterID)
+	fmt.Fprintf(w, "Cluster name:\t%s\n", s.ClusterName)
+	fmt.Fprintf(w, "Created:\t%s\n", s.CreatedAt.Local().Format(time.RFC3339))
+	fmt.Fprintf(w, "Last used:\t%s\n", s.LastUsedAt.Local().Format(time.RFC3339))
+	fmt.Fprintf(w, "History:\t%d commands\n", s.HistorySize)
+	for i, recording := range recordings {
+		label := ""
+		if i == 0 {
+			label = "Recordings:"
+		}
+		fmt.Fprintf(w, "%s\t%s\n", label, filepath.Base(recording))
+	}
+	return w.Flush()
+}
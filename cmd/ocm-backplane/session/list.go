@@ -0,0 +1,55 @@
+package session
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	logger "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+
+	"github.com/openshift/backplane-cli/pkg/cli/session"
+	"github.com/openshift/backplane-cli/pkg/utils"
+)
+
+func newListCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:          "list",
+		Short:        "List the backplane sessions",
+		Long:         "List the backplane sessions of the session directory with their cluster, creation and last used times and the number of commands in their history, the most recently used first.",
+		Example:      " backplane session list",
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		RunE:         runList,
+	}
+}
+
+func runList(cmd *cobra.Command, argv []string) error {
+	sessions, err := session.ListSessions()
+	if err != nil {
+		return err
+	}
+
+	if len(sessions) == 0 {
+		fmt.Println("No backplane session found")
+		return nil
+	}
+
+	headers := []string{"ALIAS", "CLUSTER ID", "CLUSTER NAME", "CREATED", "LAST USED", "HISTORY"}
+	rows := make([][]string, 0, len(sessions))
+	for _, s := range sessions {
+		if cmd.Parent() != nil && isReservedAlias(cmd.Parent(), s.Alias) {
+			logger.Warnf("Session %s can't be used as %s is a session subcommand, rename its directory %s", s.Alias, s.Alias, s.Path)
+		}
+		rows = append(rows, []string{
+			s.Alias,
+			s.ClusterID,
+			s.ClusterName,
+			s.CreatedAt.Local().Format(time.RFC3339),
+			s.LastUsedAt.Local().Format(time.RFC3339),
+			strconv.Itoa(s.HistorySize),
+		})
+	}
+	utils.RenderTable(headers, rows)
+	return nil
+}
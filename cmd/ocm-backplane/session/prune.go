@@ -0,0 +1,88 @@
+package session
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/openshift/backplane-cli/pkg/cli/session"
+)
+
+var pruneArgs struct {
+	olderThan time.Duration
+	dryRun    bool
+}
+
+func newPruneCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "prune",
+		Short: "Delete the backplane sessions which weren't used for a while",
+		Long: `Delete the backplane sessions which weren't used for the given duration, with their kubeconfig and history.
+The session the command is run from is kept.`,
+		Example:      " backplane session prune --older-than 168h\n backplane session prune --older-than 720h --dry-run",
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		RunE:         runPrune,
+	}
+
+	cmd.Flags().DurationVar(
+		&pruneArgs.olderThan,
+		"older-than",
+		0,
+		"Delete the sessions which weren't used for the given duration, eg. 168h.",
+	)
+	cmd.Flags().BoolVar(
+		&pruneArgs.dryRun,
+		"dry-run",
+		false,
+		"List the sessions which would be deleted without deleting them.",
+	)
+	return cmd
+}
+
+func runPrune(cmd *cobra.Command, argv []string) error {
+	if pruneArgs.olderThan <= 0 {
+		return fmt.Errorf("--older-than is required, eg. --older-than 168h")
+	}
+
+	sessions, err := session.ListSessions()
+	if err != nil {
+		return err
+	}
+
+	pruned := 0
+	for _, s := range sessions {
+		if time.Since(s.LastUsedAt) < pruneArgs.olderThan {
+			continue
+		}
+		if isCurrentSession(s) {
+			fmt.Printf("Keeping the current session: %s\n", s.Alias)
+			continue
+		}
+
+		pruned++
+		if pruneArgs.dryRun {
+			fmt.Printf("Would remove session: %s (last used %s)\n", s.Alias, s.LastUsedAt.Local().Format(time.RFC3339))
+			continue
+		}
+		if err := session.RemoveSession(s); err != nil {
+			return err
+		}
+		fmt.Printf("Removed session: %s\n", s.Alias)
+	}
+
+	if pruned == 0 {
+		fmt.Printf("No session unused for %s\n", pruneArgs.olderThan)
+	}
+	return nil
+}
+
+// isCurrentSession tells whether the command runs from the session, whose shell has its history file set
+func isCurrentSession(s session.Info) bool {
+	histFile := os.Getenv("HISTFILE")
+	return histFile != "" && strings.HasPrefix(histFile, s.Path+string(filepath.Separator))
+}
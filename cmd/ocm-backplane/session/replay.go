@@ -0,0 +1,81 @@
+package session
+
+import (
+	"fmt"
+	"path/filepath"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/openshift/backplane-cli/pkg/cli/session"
+)
+
+var replayArgs struct {
+	recording string
+	speed     float64
+	idleLimit time.Duration
+}
+
+func newReplayCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "replay <session-alias>",
+		Short: "Play back the recording of a backplane session",
+		Long: `Play back a recording of a backplane session started with --record, the latest one by default.
+The recordings are asciinema v2 files in the recordings directory of the session, they can also be played with asciinema.`,
+		Example:           " backplane session replay my-session\n backplane session replay my-session --speed 2 --idle-limit 2s",
+		Args:              cobra.ExactArgs(1),
+		SilenceUsage:      true,
+		ValidArgsFunction: completeSessionAlias,
+		RunE:              runReplay,
+	}
+
+	cmd.Flags().StringVar(
+		&replayArgs.recording,
+		"recording",
+		"",
+		"The recording to play, as listed by session info. Defaults to the latest one.",
+	)
+	cmd.Flags().Float64Var(
+		&replayArgs.speed,
+		"speed",
+		1,
+		"The playback speed, eg. 2 plays twice as fast.",
+	)
+	cmd.Flags().DurationVar(
+		&replayArgs.idleLimit,
+		"idle-limit",
+		0,
+		"Shorten the pauses of the recording to the given duration, eg. 2s.",
+	)
+	return cmd
+}
+
+func runReplay(cmd *cobra.Command, argv []string) error {
+	s, err := session.GetSession(argv[0])
+	if err != nil {
+		return err
+	}
+
+	recordings, err := session.ListRecordings(s)
+	if err != nil {
+		return err
+	}
+	if len(recordings) == 0 {
+		return fmt.Errorf("session %s has no recording, start it with --record to record it", s.Alias)
+	}
+
+	recording := recordings[len(recordings)-1]
+	if replayArgs.recording != "" {
+		recording = ""
+		for _, r := range recordings {
+			if filepath.Base(r) == replayArgs.recording {
+				recording = r
+			}
+		}
+		if recording == "" {
+			return fmt.Errorf("recording %s not found in session %s", replayArgs.recording, s.Alias)
+		}
+	}
+
+	return session.Replay(recording, cmd.OutOrStdout(), replayArgs.speed, replayArgs.idleLimit)
+}
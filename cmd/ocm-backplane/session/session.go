@@ -1,15 +1,13 @@
 package session
 
 import (
-	"os"
-	"path/filepath"
+	"fmt"
 	"strings"
 
 	"github.com/spf13/cobra"
 
 	"github.com/openshift/backplane-cli/pkg/cli/globalflags"
 	"github.com/openshift/backplane-cli/pkg/cli/session"
-	"github.com/openshift/backplane-cli/pkg/info"
 )
 
 var globalOpts = &globalflags.GlobalOptions{}
@@ -21,25 +19,19 @@ func NewCmdSession() *cobra.Command {
 		Options: &options,
 	}
 	sessionCmd := &cobra.Command{
-		Use:               "session [flags] [session-alias]",
-		Short:             "Create an isolated environment to interact with a cluster in its own directory",
-		Args:              cobra.MaximumNArgs(1),
+		Use:   "session [flags] [session-alias] [-- command]",
+		Short: "Create an isolated environment to interact with a cluster in its own directory",
+		Long: `Create an isolated environment to interact with a cluster in its own directory, and start a shell in it.
+With a command after --, the command is run in the session environment instead of the shell,
+and backplane exits with its exit status.
+
+The list, info, prune and replay names are session subcommands, they can't be used as session aliases.
+A session created with one of these aliases by an older version must have its directory renamed to be used.`,
+		Example:           " backplane session my-session -c <cluster-id>\n backplane session my-session -- oc get co",
+		Args:              validateSessionArgs,
 		DisableAutoGenTag: true,
 		RunE:              session.RunCommand,
-		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
-			validEnvs := []string{}
-			files, err := os.ReadDir(filepath.Join(os.Getenv("HOME"), info.BackplaneDefaultSessionDirectory))
-			if err != nil {
-				return validEnvs, cobra.ShellCompDirectiveNoFileComp
-			}
-			for _, f := range files {
-				if f.IsDir() && strings.HasPrefix(f.Name(), toComplete) {
-					validEnvs = append(validEnvs, f.Name())
-				}
-			}
-
-			return validEnvs, cobra.ShellCompDirectiveNoFileComp
-		},
+		ValidArgsFunction: completeSessionAlias,
 	}
 
 	// Initialize global flags
@@ -55,6 +47,20 @@ func NewCmdSession() *cobra.Command {
 		"Delete session",
 	)
 
+	sessionCmd.Flags().BoolVar(
+		&options.Fresh,
+		"fresh",
+		false,
+		"Recreate the session instead of resuming it, deleting its history and files",
+	)
+
+	sessionCmd.Flags().BoolVar(
+		&options.Record,
+		"record",
+		false,
+		"Record the session shell to the session directory, session replay plays it back",
+	)
+
 	sessionCmd.Flags().StringVarP(
 		&options.ClusterID,
 		"cluster-id",
@@ -63,5 +69,58 @@ func NewCmdSession() *cobra.Command {
 		"The cluster to create the session for",
 	)
 
+	for _, cmd := range []*cobra.Command{newListCmd(), newInfoCmd(), newPruneCmd(), newReplayCmd()} {
+		// The session flags given to a subcommand mean its name was meant as a session alias
+		cmd.SetFlagErrorFunc(func(cmd *cobra.Command, err error) error {
+			return fmt.Errorf("%v\n%s is a session subcommand, it can't be used as a session alias", err, cmd.Name())
+		})
+		sessionCmd.AddCommand(cmd)
+	}
+
 	return sessionCmd
 }
+
+// isReservedAlias tells whether the alias is the name of a subcommand of the command,
+// which runs the subcommand rather than the session
+func isReservedAlias(cmd *cobra.Command, alias string) bool {
+	for _, subcommand := range cmd.Commands() {
+		if subcommand.Name() == alias || subcommand.HasAlias(alias) {
+			return true
+		}
+	}
+	return false
+}
+
+// validateSessionArgs accepts the session alias, and the command to run after --
+func validateSessionArgs(cmd *cobra.Command, args []string) error {
+	if dash := cmd.ArgsLenAtDash(); dash >= 0 {
+		if dash == len(args) {
+			return fmt.Errorf("a command is required after --")
+		}
+		args = args[:dash]
+	}
+	if len(args) > 0 && isReservedAlias(cmd, args[0]) {
+		return fmt.Errorf("%s is a session subcommand, it can't be used as a session alias", args[0])
+	}
+	return cobra.MaximumNArgs(1)(cmd, args)
+}
+
+// completeSessionAlias completes the aliases of the existing sessions
+func completeSessionAlias(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	validEnvs := []string{}
+	if len(args) > 0 {
+		return validEnvs, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	sessions, err := session.ListSessions()
+	if err != nil {
+		return validEnvs, cobra.ShellCompDirectiveNoFileComp
+	}
+	for _, s := range sessions {
+		if strings.HasPrefix(s.Alias, toComplete) && !isReservedAlias(cmd, s.Alias) {
+			validEnvs = append(validEnvs, s.Alias)
+		}
+	}
+
+	return validEnvs, cobra.ShellCompDirectiveNoFileComp
+}
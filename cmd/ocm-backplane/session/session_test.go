@@ -0,0 +1,139 @@
+package session
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/openshift/backplane-cli/pkg/cli/session"
+	"github.com/openshift/backplane-cli/pkg/info"
+)
+
+var _ = Describe("session commands", func() {
+
+	var (
+		homeDir     string
+		sessionsDir string
+		envBackup   map[string]string
+	)
+
+	// writeSession creates a session directory last used the given time ago
+	writeSession := func(alias string, lastUsed time.Duration) string {
+		path := filepath.Join(sessionsDir, alias)
+		Expect(os.MkdirAll(path, 0750)).To(Succeed())
+		content, err := json.Marshal(session.Metadata{
+			Alias:      alias,
+			ClusterID:  alias + "-id",
+			CreatedAt:  time.Now().Add(-lastUsed),
+			LastUsedAt: time.Now().Add(-lastUsed),
+		})
+		Expect(err).To(BeNil())
+		Expect(os.WriteFile(filepath.Join(path, session.MetadataFileName), content, 0600)).To(Succeed())
+		return path
+	}
+
+	BeforeEach(func() {
+		var err error
+		homeDir, err = os.MkdirTemp("", "backplane-session")
+		Expect(err).To(BeNil())
+		sessionsDir = filepath.Join(homeDir, info.BackplaneDefaultSessionDirectory)
+
+		envBackup = map[string]string{}
+		for _, name := range []string{"HOME", "HISTFILE", info.BackplaneConfigPathEnvName} {
+			envBackup[name] = os.Getenv(name)
+		}
+		os.Setenv("HOME", homeDir)
+		os.Unsetenv("HISTFILE")
+		os.Setenv(info.BackplaneConfigPathEnvName, filepath.Join(homeDir, "config.json"))
+	})
+
+	AfterEach(func() {
+		for name, value := range envBackup {
+			if value == "" {
+				os.Unsetenv(name)
+			} else {
+				os.Setenv(name, value)
+			}
+		}
+		os.RemoveAll(homeDir)
+	})
+
+	Context("prune the sessions", func() {
+		It("should only list the old sessions with --dry-run", func() {
+			old := writeSession("old", 48*time.Hour)
+			recent := writeSession("recent", time.Hour)
+
+			cmd := NewCmdSession()
+			cmd.SetArgs([]string{"prune", "--older-than", "24h", "--dry-run"})
+			Expect(cmd.Execute()).To(Succeed())
+
+			Expect(old).To(BeADirectory())
+			Expect(recent).To(BeADirectory())
+		})
+
+		It("should remove the old sessions", func() {
+			old := writeSession("old", 48*time.Hour)
+			recent := writeSession("recent", time.Hour)
+
+			cmd := NewCmdSession()
+			cmd.SetArgs([]string{"prune", "--older-than", "24h"})
+			Expect(cmd.Execute()).To(Succeed())
+
+			Expect(old).NotTo(BeADirectory())
+			Expect(recent).To(BeADirectory())
+		})
+
+		It("should keep the session the command runs from", func() {
+			current := writeSession("current", 48*time.Hour)
+			other := writeSession("other", 48*time.Hour)
+			os.Setenv("HISTFILE", filepath.Join(current, ".history"))
+
+			cmd := NewCmdSession()
+			cmd.SetArgs([]string{"prune", "--older-than", "24h"})
+			Expect(cmd.Execute()).To(Succeed())
+
+			Expect(current).To(BeADirectory())
+			Expect(other).NotTo(BeADirectory())
+		})
+
+		It("should require --older-than", func() {
+			cmd := NewCmdSession()
+			cmd.SetArgs([]string{"prune"})
+			Expect(cmd.Execute()).NotTo(Succeed())
+		})
+	})
+
+	Context("reserved session aliases", func() {
+		It("should refuse a subcommand name as session alias", func() {
+			cmd := NewCmdSession()
+			err := validateSessionArgs(cmd, []string{"prune"})
+			Expect(err).NotTo(BeNil())
+			Expect(err.Error()).To(Equal("prune is a session subcommand, it can't be used as a session alias"))
+
+			Expect(validateSessionArgs(cmd, []string{"my-session"})).To(Succeed())
+		})
+
+		It("should explain the session flags given to a subcommand", func() {
+			cmd := NewCmdSession()
+			cmd.SetArgs([]string{"list", "-c", "my-cluster"})
+			cmd.SilenceErrors = true
+
+			err := cmd.Execute()
+			Expect(err).NotTo(BeNil())
+			Expect(err.Error()).To(ContainSubstring("list is a session subcommand, it can't be used as a session alias"))
+		})
+
+		It("should not complete the sessions named after a subcommand", func() {
+			writeSession("list", time.Hour)
+			writeSession("lab", time.Hour)
+
+			cmd := NewCmdSession()
+			aliases, _ := completeSessionAlias(cmd, nil, "l")
+			Expect(aliases).To(Equal([]string{"lab"}))
+		})
+	})
+})
@@ -0,0 +1,117 @@
+package whoami
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/openshift/backplane-cli/pkg/cli/config"
+	"github.com/openshift/backplane-cli/pkg/utils"
+)
+
+// identity is the JSON representation of the current user
+type identity struct {
+	Username     string    `json:"username"`
+	Email        string    `json:"email"`
+	OrgID        string    `json:"org_id"`
+	Issuer       string    `json:"issuer"`
+	IssuedAt     time.Time `json:"issued_at"`
+	ExpiresAt    time.Time `json:"expires_at"`
+	ExpiresIn    int64     `json:"expires_in_seconds"`
+	OCMURL       string    `json:"ocm_url"`
+	BackplaneURL string    `json:"backplane_url"`
+}
+
+// WhoamiCmd represents the whoami command
+var WhoamiCmd = &cobra.Command{
+	Use:   "whoami",
+	Short: "Show the current OCM identity and token lifetime",
+	Long: `Decode the current OCM access token and print the identity it carries,
+the OCM environment, the time left before the token expires and the backplane URL in use.`,
+	Example:      " backplane whoami\n backplane whoami -o json",
+	Args:         cobra.NoArgs,
+	RunE:         runWhoami,
+	SilenceUsage: true,
+}
+
+func init() {
+	WhoamiCmd.Flags().StringP(
+		"output",
+		"o",
+		"text",
+		"Format the output. One of text|json",
+	)
+}
+
+func runWhoami(cmd *cobra.Command, argv []string) error {
+	output, err := cmd.Flags().GetString("output")
+	if err != nil {
+		return err
+	}
+	if output != "text" && output != "json" {
+		return fmt.Errorf("unsupported output format %s, supported formats are text|json", output)
+	}
+
+	accessToken, err := utils.DefaultOCMInterface.GetOCMAccessToken()
+	if err != nil {
+		return err
+	}
+
+	claims, err := utils.ParseOCMClaims(*accessToken)
+	if err != nil {
+		return fmt.Errorf("unable to decode the OCM token: %v", err)
+	}
+
+	ocmURL, err := utils.DefaultOCMInterface.GetOCMEnvironmentURL()
+	if err != nil {
+		return err
+	}
+
+	bpConfig, err := config.GetBackplaneConfiguration()
+	if err != nil {
+		return err
+	}
+
+	id := identity{
+		Username:     claims.Username,
+		Email:        claims.Email,
+		OrgID:        claims.OrgID,
+		Issuer:       claims.Issuer,
+		IssuedAt:     claims.IssuedAt,
+		ExpiresAt:    claims.ExpiresAt,
+		ExpiresIn:    int64(claims.ExpiresIn().Seconds()),
+		OCMURL:       ocmURL,
+		BackplaneURL: bpConfig.URL,
+	}
+
+	if output == "json" {
+		return utils.RenderJSONBytes(id)
+	}
+
+	printIdentity(os.Stdout, id)
+	return nil
+}
+
+// printIdentity prints the identity as text
+func printIdentity(w io.Writer, id identity) {
+	expiry := "never"
+	if !id.ExpiresAt.IsZero() {
+		expiresIn := time.Duration(id.ExpiresIn) * time.Second
+		if expiresIn > 0 {
+			expiry = fmt.Sprintf("%s (in %s)", id.ExpiresAt.Format(time.RFC3339), expiresIn)
+		} else {
+			expiry = fmt.Sprintf("%s (expired)", id.ExpiresAt.Format(time.RFC3339))
+		}
+	}
+
+	fmt.Fprintf(w, "Username:      %s\n", id.Username)
+	fmt.Fprintf(w, "Email:         %s\n", id.Email)
+	fmt.Fprintf(w, "Organization:  %s\n", id.OrgID)
+	fmt.Fprintf(w, "Issuer:        %s\n", id.Issuer)
+	fmt.Fprintf(w, "OCM URL:       %s\n", id.OCMURL)
+	fmt.Fprintf(w, "Backplane URL: %s\n", id.BackplaneURL)
+	fmt.Fprintf(w, "Token expires: %s\n", expiry)
+}
@@ -0,0 +1,13 @@
+package whoami
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+func TestWhoami(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Whoami Test Suite")
+}
@@ -0,0 +1,126 @@
+package whoami
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/spf13/cobra"
+
+	"github.com/openshift/backplane-cli/pkg/info"
+	"github.com/openshift/backplane-cli/pkg/utils"
+	mocks2 "github.com/openshift/backplane-cli/pkg/utils/mocks"
+)
+
+var _ = Describe("whoami command", func() {
+
+	var (
+		configDir string
+
+		mockCtrl         *gomock.Controller
+		mockOcmInterface *mocks2.MockOCMInterface
+
+		testToken string
+
+		sut *cobra.Command
+	)
+
+	BeforeEach(func() {
+		mockCtrl = gomock.NewController(GinkgoT())
+		mockOcmInterface = mocks2.NewMockOCMInterface(mockCtrl)
+		utils.DefaultOCMInterface = mockOcmInterface
+
+		var err error
+		testToken, err = jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+			"username": "foo",
+			"email":    "foo@example.com",
+			"org_id":   "12345",
+			"iss":      "https://sso.example.com/auth/realms/test",
+			"iat":      time.Now().Unix(),
+			"exp":      time.Now().Add(time.Hour).Unix(),
+		}).SignedString([]byte("secret"))
+		Expect(err).To(BeNil())
+
+		configDir, err = os.MkdirTemp("", "backplane")
+		Expect(err).To(BeNil())
+		os.Setenv(info.BackplaneConfigPathEnvName, filepath.Join(configDir, "config.json"))
+		os.Setenv(info.BackplaneURLEnvName, "https://api.backplane.example.com")
+
+		sut = WhoamiCmd
+		sut.SetArgs([]string{})
+		Expect(sut.Flags().Set("output", "text")).To(Succeed())
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(configDir)
+		os.Unsetenv(info.BackplaneConfigPathEnvName)
+		os.Unsetenv(info.BackplaneURLEnvName)
+		mockCtrl.Finish()
+	})
+
+	Context("show the identity", func() {
+		It("should print the identity decoded from the token", func() {
+			mockOcmInterface.EXPECT().GetOCMAccessToken().Return(&testToken, nil)
+			mockOcmInterface.EXPECT().GetOCMEnvironmentURL().Return("https://api.openshift.com", nil)
+
+			err := sut.Execute()
+
+			Expect(err).To(BeNil())
+		})
+
+		It("should print the identity as json", func() {
+			mockOcmInterface.EXPECT().GetOCMAccessToken().Return(&testToken, nil)
+			mockOcmInterface.EXPECT().GetOCMEnvironmentURL().Return("https://api.openshift.com", nil)
+
+			sut.SetArgs([]string{"-o", "json"})
+			err := sut.Execute()
+
+			Expect(err).To(BeNil())
+		})
+
+		It("should fail for an unsupported output format", func() {
+			sut.SetArgs([]string{"-o", "yaml"})
+			err := sut.Execute()
+
+			Expect(err).NotTo(BeNil())
+			Expect(err.Error()).To(Equal("unsupported output format yaml, supported formats are text|json"))
+		})
+
+		It("should fail when the token can't be retrieved", func() {
+			mockOcmInterface.EXPECT().GetOCMAccessToken().Return(nil, errors.New("not logged in"))
+
+			err := sut.Execute()
+
+			Expect(err).NotTo(BeNil())
+			Expect(err.Error()).To(Equal("not logged in"))
+		})
+
+		It("should format the identity and the token lifetime", func() {
+			out := &strings.Builder{}
+			expiresAt := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+
+			printIdentity(out, identity{
+				Username:     "foo",
+				Email:        "foo@example.com",
+				OrgID:        "12345",
+				OCMURL:       "https://api.openshift.com",
+				BackplaneURL: "https://api.backplane.example.com",
+				ExpiresAt:    expiresAt,
+				ExpiresIn:    600,
+			})
+
+			Expect(out.String()).To(ContainSubstring("Username:      foo\n"))
+			Expect(out.String()).To(ContainSubstring("Email:         foo@example.com\n"))
+			Expect(out.String()).To(ContainSubstring("Organization:  12345\n"))
+			Expect(out.String()).To(ContainSubstring("OCM URL:       https://api.openshift.com\n"))
+			Expect(out.String()).To(ContainSubstring("Backplane URL: https://api.backplane.example.com\n"))
+			Expect(out.String()).To(ContainSubstring("Token expires: 2030-01-01T00:00:00Z (in 10m0s)\n"))
+		})
+	})
+})
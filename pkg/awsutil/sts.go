@@ -10,6 +10,7 @@ import (
 	"github.com/aws/aws-sdk-go-v2/credentials"
 	"github.com/aws/aws-sdk-go-v2/credentials/stscreds"
 	"github.com/aws/aws-sdk-go-v2/service/sts"
+	bpconfig "github.com/openshift/backplane-cli/pkg/cli/config"
 	"github.com/openshift/backplane-cli/pkg/utils"
 	"io"
 	"net/http"
@@ -39,20 +40,32 @@ type AWSSigninTokenResponse struct {
 var httpGetFunc = http.Get
 
 func StsClientWithProxy(proxyURL string) (*sts.Client, error) {
+	transport, err := newTransport(proxyURL)
+	if err != nil {
+		return nil, err
+	}
+
 	cfg := aws.Config{
 		Region: "us-east-1", // We don't care about region here, but the API still wants to see one set
 		HTTPClient: &http.Client{
-			Transport: &http.Transport{
-				Proxy: func(*http.Request) (*url.URL, error) {
-					return url.Parse(proxyURL)
-				},
-			},
+			Transport: transport,
 		},
 	}
 
 	return sts.NewFromConfig(cfg), nil
 }
 
+// newTransport returns the http transport of the STS requests through the given proxy,
+// with the TLS settings of the backplane configuration
+func newTransport(proxyURL string) (*http.Transport, error) {
+	bpConfig, err := bpconfig.GetBackplaneConfiguration()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load backplane config file: %w", err)
+	}
+
+	return bpConfig.NewTransport(proxyURL)
+}
+
 // IdentityTokenValue is for retrieving an identity token from the given file name
 type IdentityTokenValue string
 
@@ -147,14 +160,15 @@ func AssumeRoleSequence(roleSessionName string, seedClient stscreds.AssumeRoleAP
 }
 
 func createAssumeRoleSequenceClient(stsClientProviderFunc STSClientProviderFunc, creds aws.Credentials, proxyURL string) (stscreds.AssumeRoleAPIClient, error) {
+	transport, err := newTransport(proxyURL)
+	if err != nil {
+		return nil, err
+	}
+
 	return stsClientProviderFunc(
 		config.WithCredentialsProvider(credentials.NewStaticCredentialsProvider(creds.AccessKeyID, creds.SecretAccessKey, creds.SessionToken)),
 		config.WithHTTPClient(&http.Client{
-			Transport: &http.Transport{
-				Proxy: func(*http.Request) (*url.URL, error) {
-					return url.Parse(proxyURL)
-				},
-			},
+			Transport: transport,
 		}),
 		config.WithRegion("us-east-1"), // We don't care about region here, but the API still wants to see one set
 	)
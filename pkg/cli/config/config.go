@@ -2,7 +2,6 @@ package config
 
 import (
 	"net/http"
-	"net/url"
 	"os"
 	"path/filepath"
 	"time"
@@ -12,11 +11,68 @@ import (
 	"github.com/openshift/backplane-cli/pkg/info"
 )
 
+const (
+	// URLConfigVar is the config key of the backplane API URL
+	URLConfigVar = "url"
+
+	// ProxyURLConfigVar is the config key of the proxy URL to reach the backplane API
+	ProxyURLConfigVar = "proxy-url"
+
+	// SessionConfigVar is the config key of the backplane session directory
+	SessionConfigVar = "session-dir"
+
+	// SessionTemplateConfigVar is the config key of the directory whose files are copied into every session
+	SessionTemplateConfigVar = "session-template-dir"
+
+	// AssumeInitialArnConfigVar is the config key of the initial role assumed by cloud commands
+	AssumeInitialArnConfigVar = "assume-initial-arn"
+
+	// TokenExpiryWarningConfigVar is the config key of the window before the OCM token expiry to warn in
+	TokenExpiryWarningConfigVar = "token-expiry-warning"
+
+	// DefaultTokenExpiryWarning is the default window before the OCM token expiry to warn in
+	DefaultTokenExpiryWarning = 5 * time.Minute
+
+	// MaxRetriesConfigVar is the config key of the number of retries of a failed backplane API request
+	MaxRetriesConfigVar = "max-retries"
+
+	// DefaultMaxRetries is the default number of retries of a failed backplane API request
+	DefaultMaxRetries = 3
+)
+
 type BackplaneConfiguration struct {
-	URL              string
-	ProxyURL         string
-	SessionDirectory string
-	AssumeInitialArn string
+	URL                string
+	ProxyURL           string
+	SessionDirectory   string
+	AssumeInitialArn   string
+	TokenExpiryWarning time.Duration
+	MaxRetries         int
+	ProxyCacheTTL      time.Duration
+
+	// SessionTemplateDirectory holds the scripts and rc fragments copied into every session
+	SessionTemplateDirectory string
+
+	// CAFile is a PEM bundle of CAs trusted on top of the system ones, eg. of a TLS-intercepting proxy
+	CAFile string
+	// InsecureSkipVerifyProxy skips the TLS verification of the connections going through the proxy
+	InsecureSkipVerifyProxy bool
+
+	// ProxyURLs are the candidate proxies and ProxyURL is the first of them,
+	// SelectProxyURL returns the first one reaching the backplane API
+	ProxyURLs []string
+	// PACURL is the proxy auto-config file choosing the proxy per destination when no proxy URL is set
+	PACURL string
+
+	// OCMURL is the OCM API URL the profile in use is selected for
+	OCMURL string
+
+	// Profile is the name of the profile the configuration comes from, empty when no profile applies
+	Profile string
+	// ProfileSource tells why the profile was selected, see the ProfileSource constants
+	ProfileSource string
+	// Sources tells where the value of each variable comes from: the environment, the profile,
+	// the configuration file or the default value
+	Sources map[string]string
 }
 
 // GetConfigFilePath returns the Backplane CLI configuration filepath
@@ -37,12 +93,96 @@ func GetConfigFilePath() (string, error) {
 	return configFilePath, nil
 }
 
+// GetConfigDirFilePath returns the path of the file with the given name, next to the backplane configuration file
+func GetConfigDirFilePath(name string) (string, error) {
+	configFilePath, err := GetConfigFilePath()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(filepath.Dir(configFilePath), name), nil
+}
+
 // GetBackplaneConfiguration parses and returns the given backplane configuration
 func GetBackplaneConfiguration() (bpConfig BackplaneConfiguration, err error) {
-	filePath, err := GetConfigFilePath()
+	if err := loadConfiguration(); err != nil {
+		return bpConfig, err
+	}
+
+	// The values of the selected profile override the top level ones
+	profile, source, err := selectProfile()
 	if err != nil {
 		return bpConfig, err
 	}
+	bpConfig.Profile = profile
+	bpConfig.ProfileSource = source
+
+	bpConfig.Sources = map[string]string{}
+	for _, key := range Keys {
+		bpConfig.Sources[key.Name] = valueSource(profile, key)
+	}
+
+	bpConfig.URL = viper.GetString(envConfigKey(profile, URLConfigVar, info.BackplaneURLEnvName))
+	bpConfig.ProxyURLs = getStringList(envConfigKey(profile, ProxyURLConfigVar, info.BackplaneProxyEnvName))
+	bpConfig.SessionDirectory = viper.GetString(configKey(profile, SessionConfigVar))
+	bpConfig.SessionTemplateDirectory = viper.GetString(configKey(profile, SessionTemplateConfigVar))
+	bpConfig.AssumeInitialArn = viper.GetString(configKey(profile, AssumeInitialArnConfigVar))
+	if profile != "" {
+		bpConfig.OCMURL = viper.GetString(profileConfigKey(profile, OCMURLConfigVar))
+	}
+
+	bpConfig.TokenExpiryWarning = getTokenExpiryWarning(profile)
+
+	bpConfig.MaxRetries = DefaultMaxRetries
+	if key := configKey(profile, MaxRetriesConfigVar); viper.IsSet(key) {
+		bpConfig.MaxRetries = viper.GetInt(key)
+	}
+
+	bpConfig.CAFile = viper.GetString(configKey(profile, CAFileConfigVar))
+	bpConfig.InsecureSkipVerifyProxy = viper.GetBool(configKey(profile, InsecureSkipVerifyProxyConfigVar))
+
+	bpConfig.ProxyCacheTTL = DefaultProxyCacheTTL
+	if key := configKey(profile, ProxyCacheTTLConfigVar); viper.IsSet(key) {
+		bpConfig.ProxyCacheTTL = viper.GetDuration(key)
+	}
+	if len(bpConfig.ProxyURLs) > 0 {
+		bpConfig.ProxyURL = bpConfig.ProxyURLs[0]
+	}
+	bpConfig.PACURL = viper.GetString(configKey(profile, PACURLConfigVar))
+
+	return bpConfig, nil
+}
+
+// GetTokenExpiryWarning returns the configured window before the OCM token expiry to warn in.
+// It only reads the configuration, unlike GetBackplaneConfiguration which also selects the proxy.
+func GetTokenExpiryWarning() (time.Duration, error) {
+	if err := loadConfiguration(); err != nil {
+		return DefaultTokenExpiryWarning, err
+	}
+
+	profile, _, err := selectProfile()
+	if err != nil {
+		return DefaultTokenExpiryWarning, err
+	}
+
+	return getTokenExpiryWarning(profile), nil
+}
+
+// getTokenExpiryWarning returns the token expiry warning of the profile, or the top level one
+func getTokenExpiryWarning(profile string) time.Duration {
+	if key := configKey(profile, TokenExpiryWarningConfigVar); viper.IsSet(key) {
+		return viper.GetDuration(key)
+	}
+	return DefaultTokenExpiryWarning
+}
+
+// loadConfiguration reads the configuration file, if any, and binds the environment variables
+// taking precedence over it
+func loadConfiguration() error {
+	filePath, err := GetConfigFilePath()
+	if err != nil {
+		return err
+	}
 
 	viper.AutomaticEnv()
 
@@ -53,35 +193,30 @@ func GetBackplaneConfiguration() (bpConfig BackplaneConfiguration, err error) {
 		viper.SetConfigType("json")
 
 		if err := viper.ReadInConfig(); err != nil {
-			return bpConfig, err
+			return err
 		}
 	}
 
 	// Check if user has explicitly defined backplane URL; it has higher precedence over the config file
-	err = viper.BindEnv("url", info.BackplaneURLEnvName)
+	err = viper.BindEnv(URLConfigVar, info.BackplaneURLEnvName)
 	if err != nil {
-		return bpConfig, err
+		return err
 	}
 
 	// Check if user has explicitly defined proxy; it has higher precedence over the config file
-	err = viper.BindEnv("proxy-url", info.BackplaneProxyEnvName)
+	err = viper.BindEnv(ProxyURLConfigVar, info.BackplaneProxyEnvName)
 	if err != nil {
-		return bpConfig, err
+		return err
 	}
 
-	bpConfig.URL = viper.GetString("url")
-	bpConfig.ProxyURL = viper.GetString("proxy-url")
-	bpConfig.SessionDirectory = viper.GetString("session-dir")
-	bpConfig.AssumeInitialArn = viper.GetString("assume-initial-arn")
-
-	return bpConfig, nil
+	return nil
 }
 
 // CheckAPIConnection validate API connection via configured proxy and VPN
 func (config BackplaneConfiguration) CheckAPIConnection() error {
 
 	// make test api connection
-	connectionOk, err := config.testHTTPRequestToBackplaneAPI()
+	connectionOk, err := config.testHTTPRequestToBackplaneAPI(config.SelectProxyURL())
 
 	if !connectionOk {
 		return err
@@ -90,28 +225,28 @@ func (config BackplaneConfiguration) CheckAPIConnection() error {
 	return nil
 }
 
-// testHTTPRequestToBackplaneAPI returns status of the API connection
-func (config BackplaneConfiguration) testHTTPRequestToBackplaneAPI() (bool, error) {
-	client := http.Client{
-		Timeout: 5 * time.Second,
+// testHTTPRequestToBackplaneAPI returns status of the API connection through the given proxy
+func (config BackplaneConfiguration) testHTTPRequestToBackplaneAPI(proxyURL string) (bool, error) {
+	// The proxy only applies to this client, the default transport is left as is
+	transport, err := config.NewTransport(proxyURL)
+	if err != nil {
+		return false, err
 	}
 
-	if config.ProxyURL != "" {
-		proxyURL, err := url.Parse(config.ProxyURL)
-		if err != nil {
-			return false, err
-		}
-		http.DefaultTransport = &http.Transport{Proxy: http.ProxyURL(proxyURL)}
+	client := http.Client{
+		Timeout:   5 * time.Second,
+		Transport: transport,
 	}
 
 	req, err := http.NewRequest("HEAD", config.URL, nil)
 	if err != nil {
 		return false, err
 	}
-	_, err = client.Do(req)
+	resp, err := client.Do(req)
 	if err != nil {
 		return false, err
 	}
+	resp.Body.Close()
 
 	return true, nil
 }
@@ -3,7 +3,12 @@ package config
 import (
 	"net/http"
 	"net/http/httptest"
+	"os"
+	"path/filepath"
 	"testing"
+	"time"
+
+	"github.com/openshift/backplane-cli/pkg/info"
 )
 
 func TestGetBackplaneConfig(t *testing.T) {
@@ -34,6 +39,22 @@ func TestGetBackplaneConfig(t *testing.T) {
 	})
 }
 
+func TestGetConfigDirFilePath(t *testing.T) {
+	t.Run("it returns the file next to the user defined configuration file", func(t *testing.T) {
+		configDir := t.TempDir()
+		t.Setenv(info.BackplaneConfigPathEnvName, filepath.Join(configDir, "config.json"))
+
+		path, err := GetConfigDirFilePath("history.json")
+		if err != nil {
+			t.Error(err)
+		}
+
+		if path != filepath.Join(configDir, "history.json") {
+			t.Errorf("expected the file to be in %v, got %v", configDir, path)
+		}
+	})
+}
+
 func TestGetBackplaneConfiguration(t *testing.T) {
 
 	for name, tc := range map[string]struct {
@@ -102,3 +123,70 @@ func TestGetBackplaneConnection(t *testing.T) {
 		}
 	})
 }
+
+func TestGetBackplaneConfigTokenExpiryWarning(t *testing.T) {
+	t.Run("it returns the default token expiry warning when not configured", func(t *testing.T) {
+		t.Setenv(info.BackplaneConfigPathEnvName, filepath.Join(t.TempDir(), "config.json"))
+		config, err := GetBackplaneConfiguration()
+		if err != nil {
+			t.Error(err)
+		}
+
+		if config.TokenExpiryWarning != DefaultTokenExpiryWarning {
+			t.Errorf("expected the default token expiry warning %v, got %v", DefaultTokenExpiryWarning, config.TokenExpiryWarning)
+		}
+	})
+
+	t.Run("it returns the configured token expiry warning", func(t *testing.T) {
+		configPath := filepath.Join(t.TempDir(), "config.json")
+		if err := os.WriteFile(configPath, []byte(`{"token-expiry-warning": "15m"}`), 0600); err != nil {
+			t.Fatal(err)
+		}
+		t.Setenv(info.BackplaneConfigPathEnvName, configPath)
+
+		config, err := GetBackplaneConfiguration()
+		if err != nil {
+			t.Error(err)
+		}
+
+		if config.TokenExpiryWarning != 15*time.Minute {
+			t.Errorf("expected the configured token expiry warning 15m, got %v", config.TokenExpiryWarning)
+		}
+	})
+
+	t.Run("it reads the token expiry warning alone", func(t *testing.T) {
+		configPath := filepath.Join(t.TempDir(), "config.json")
+		if err := os.WriteFile(configPath, []byte(`{"token-expiry-warning": "20m"}`), 0600); err != nil {
+			t.Fatal(err)
+		}
+		t.Setenv(info.BackplaneConfigPathEnvName, configPath)
+
+		expiryWarning, err := GetTokenExpiryWarning()
+		if err != nil {
+			t.Error(err)
+		}
+
+		if expiryWarning != 20*time.Minute {
+			t.Errorf("expected the configured token expiry warning 20m, got %v", expiryWarning)
+		}
+	})
+}
+
+func TestGetBackplaneConfigMaxRetries(t *testing.T) {
+	t.Run("it returns the configured max retries", func(t *testing.T) {
+		configPath := filepath.Join(t.TempDir(), "config.json")
+		if err := os.WriteFile(configPath, []byte(`{"max-retries": 0}`), 0600); err != nil {
+			t.Fatal(err)
+		}
+		t.Setenv(info.BackplaneConfigPathEnvName, configPath)
+
+		config, err := GetBackplaneConfiguration()
+		if err != nil {
+			t.Error(err)
+		}
+
+		if config.MaxRetries != 0 {
+			t.Errorf("expected the configured max retries 0, got %v", config.MaxRetries)
+		}
+	})
+}
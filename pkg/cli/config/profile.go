@@ -0,0 +1,322 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	ocmconfig "github.com/openshift-online/ocm-cli/pkg/config"
+	logger "github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+)
+
+const (
+	// ProfilesConfigVar is the config key of the named profiles
+	ProfilesConfigVar = "profiles"
+
+	// CurrentProfileConfigVar is the config key of the profile pinned by "config use-profile"
+	CurrentProfileConfigVar = "current-profile"
+
+	// OCMURLConfigVar is the profile key of the OCM API URL the profile is selected for
+	OCMURLConfigVar = "ocm-url"
+
+	// ProfileSourceFlag means the profile was selected by the --profile flag
+	ProfileSourceFlag = "flag"
+	// ProfileSourceCurrent means the profile was pinned by "config use-profile"
+	ProfileSourceCurrent = "use-profile"
+	// ProfileSourceOCMURL means the profile was selected as it matches the URL of the ocm session
+	ProfileSourceOCMURL = "ocm-url"
+
+	// ValueSourceEnv means the value comes from an environment variable
+	ValueSourceEnv = "env"
+	// ValueSourceProfile means the value comes from the profile in use
+	ValueSourceProfile = "profile"
+	// ValueSourceFile means the value comes from the top level of the configuration file
+	ValueSourceFile = "file"
+	// ValueSourceDefault means the variable isn't set
+	ValueSourceDefault = "default"
+)
+
+// ocmURLAliases are the OCM environment aliases accepted by ocm login
+var ocmURLAliases = map[string]string{
+	"production":  "https://api.openshift.com",
+	"staging":     "https://api.stage.openshift.com",
+	"integration": "https://api.integration.openshift.com",
+}
+
+// profileNameRegexp matches valid profile names. Viper keys are case insensitive and dot separated,
+// so upper case letters and dots aren't allowed.
+var profileNameRegexp = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]*$`)
+
+var (
+	// selectedProfile is the profile given by the --profile flag
+	selectedProfile string
+
+	// getOCMURL returns the API URL of the active ocm session, or an empty string without session
+	getOCMURL = func() (string, error) {
+		cfg, err := ocmconfig.Load()
+		if err != nil {
+			return "", err
+		}
+		return cfg.URL, nil
+	}
+)
+
+// Profile is a named set of configuration values, overriding the top level ones of the configuration file
+type Profile struct {
+	Name             string `json:"name"`
+	URL              string `json:"url,omitempty"`
+	ProxyURL         string `json:"proxy-url,omitempty"`
+	SessionDirectory string `json:"session-dir,omitempty"`
+	AssumeInitialArn string `json:"assume-initial-arn,omitempty"`
+	OCMURL           string `json:"ocm-url,omitempty"`
+}
+
+// SetSelectedProfile selects the profile to use, taking precedence over the pinned and the automatic ones
+func SetSelectedProfile(name string) {
+	selectedProfile = name
+}
+
+// GetSelectedProfile returns the profile given by the --profile flag, empty when not given
+func GetSelectedProfile() string {
+	return selectedProfile
+}
+
+// ValidateProfileName checks the profile name can be used as a configuration key
+func ValidateProfileName(name string) error {
+	if !profileNameRegexp.MatchString(name) {
+		return fmt.Errorf("invalid profile name %s, it must contain only lower case letters, digits, '-' and '_'", name)
+	}
+	return nil
+}
+
+// GetProfiles returns the profiles of the configuration file sorted by name
+func GetProfiles() ([]Profile, error) {
+	if err := loadConfiguration(); err != nil {
+		return nil, err
+	}
+
+	names := getProfileNames()
+	profiles := make([]Profile, 0, len(names))
+	for _, name := range names {
+		profiles = append(profiles, Profile{
+			Name:             name,
+			URL:              viper.GetString(profileConfigKey(name, URLConfigVar)),
+			ProxyURL:         strings.Join(getStringList(profileConfigKey(name, ProxyURLConfigVar)), ","),
+			SessionDirectory: viper.GetString(profileConfigKey(name, SessionConfigVar)),
+			AssumeInitialArn: viper.GetString(profileConfigKey(name, AssumeInitialArnConfigVar)),
+			OCMURL:           viper.GetString(profileConfigKey(name, OCMURLConfigVar)),
+		})
+	}
+	return profiles, nil
+}
+
+// SetCurrentProfile pins the profile used when no --profile flag is given, an empty name
+// goes back to selecting the profile by the URL of the ocm session
+func SetCurrentProfile(name string) error {
+	if name != "" {
+		if err := loadConfiguration(); err != nil {
+			return err
+		}
+		if !hasProfile(name) {
+			return fmt.Errorf("profile %s not found", name)
+		}
+	}
+
+	return updateConfigFile(func(content map[string]interface{}) {
+		if name == "" {
+			delete(content, CurrentProfileConfigVar)
+			return
+		}
+		content[CurrentProfileConfigVar] = name
+	})
+}
+
+// SetValue sets a configuration variable at the top level of the configuration file, or in the given
+// profile, which is created if needed. The other variables of the file are kept as is.
+func SetValue(profile string, key string, value interface{}) error {
+	if profile != "" {
+		if err := ValidateProfileName(profile); err != nil {
+			return err
+		}
+	}
+
+	return updateConfigFile(func(content map[string]interface{}) {
+		if profile == "" {
+			content[key] = value
+			return
+		}
+
+		profiles, _ := content[ProfilesConfigVar].(map[string]interface{})
+		if profiles == nil {
+			profiles = map[string]interface{}{}
+			content[ProfilesConfigVar] = profiles
+		}
+		profileContent, _ := profiles[profile].(map[string]interface{})
+		if profileContent == nil {
+			profileContent = map[string]interface{}{}
+			profiles[profile] = profileContent
+		}
+		profileContent[key] = value
+	})
+}
+
+// UnsetValue removes a configuration variable from the top level of the configuration file,
+// or from the given profile
+func UnsetValue(profile string, key string) error {
+	return updateConfigFile(func(content map[string]interface{}) {
+		if profile == "" {
+			delete(content, key)
+			return
+		}
+
+		profiles, _ := content[ProfilesConfigVar].(map[string]interface{})
+		if profileContent, ok := profiles[profile].(map[string]interface{}); ok {
+			delete(profileContent, key)
+		}
+	})
+}
+
+// selectProfile returns the profile to use and why it was selected: the --profile flag,
+// then the profile pinned by "config use-profile", then the profile matching the URL of the ocm session.
+// No profile is used when none of them applies.
+func selectProfile() (name string, source string, err error) {
+	if selectedProfile != "" {
+		if !hasProfile(selectedProfile) {
+			return "", "", fmt.Errorf("profile %s not found", selectedProfile)
+		}
+		return selectedProfile, ProfileSourceFlag, nil
+	}
+
+	if current := viper.GetString(CurrentProfileConfigVar); current != "" {
+		if !hasProfile(current) {
+			return "", "", fmt.Errorf("current profile %s not found, run \"ocm backplane config use-profile --clear\" to unset it", current)
+		}
+		return current, ProfileSourceCurrent, nil
+	}
+
+	names := getProfileNames()
+	if len(names) == 0 {
+		return "", "", nil
+	}
+
+	ocmURL, err := getOCMURL()
+	if err != nil {
+		// The top level configuration still applies without the ocm session
+		logger.Debugf("Unable to read the ocm configuration to select a profile: %v", err)
+		return "", "", nil
+	}
+	if ocmURL == "" {
+		return "", "", nil
+	}
+
+	for _, name := range names {
+		profileOCMURL := viper.GetString(profileConfigKey(name, OCMURLConfigVar))
+		if profileOCMURL != "" && normalizeOCMURL(profileOCMURL) == normalizeOCMURL(ocmURL) {
+			return name, ProfileSourceOCMURL, nil
+		}
+	}
+	return "", "", nil
+}
+
+// getProfileNames returns the sorted names of the loaded profiles
+func getProfileNames() []string {
+	names := []string{}
+	for name := range viper.GetStringMap(ProfilesConfigVar) {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func hasProfile(name string) bool {
+	for _, profile := range getProfileNames() {
+		if profile == name {
+			return true
+		}
+	}
+	return false
+}
+
+// profileConfigKey returns the viper key of a configuration variable of the profile
+func profileConfigKey(profile string, key string) string {
+	return ProfilesConfigVar + "." + profile + "." + key
+}
+
+// configKey returns the viper key to read a configuration variable from,
+// the profile one when the profile sets it, the top level one otherwise
+func configKey(profile string, key string) string {
+	if profile != "" && viper.IsSet(profileConfigKey(profile, key)) {
+		return profileConfigKey(profile, key)
+	}
+	return key
+}
+
+// envConfigKey is configKey for a configuration variable bound to an environment variable,
+// which takes precedence over the profile
+func envConfigKey(profile string, key string, envName string) string {
+	if os.Getenv(envName) != "" {
+		return key
+	}
+	return configKey(profile, key)
+}
+
+// valueSource tells where the value of the configuration variable comes from
+func valueSource(profile string, key Key) string {
+	switch {
+	case key.EnvName != "" && os.Getenv(key.EnvName) != "":
+		return ValueSourceEnv
+	case profile != "" && viper.IsSet(profileConfigKey(profile, key.Name)):
+		return ValueSourceProfile
+	case viper.InConfig(key.Name):
+		return ValueSourceFile
+	default:
+		return ValueSourceDefault
+	}
+}
+
+// normalizeOCMURL expands the ocm environment aliases and drops the trailing slash of the URL
+func normalizeOCMURL(ocmURL string) string {
+	ocmURL = strings.ToLower(strings.TrimSpace(ocmURL))
+	if url, ok := ocmURLAliases[ocmURL]; ok {
+		return url
+	}
+	return strings.TrimSuffix(ocmURL, "/")
+}
+
+// updateConfigFile applies the update to the content of the configuration file and writes it back.
+// The file is edited as is, so the values coming from environment variables aren't written.
+func updateConfigFile(update func(content map[string]interface{})) error {
+	path, err := GetConfigFilePath()
+	if err != nil {
+		return err
+	}
+
+	content := map[string]interface{}{}
+	data, err := os.ReadFile(filepath.Clean(path))
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	if len(data) > 0 {
+		if err := json.Unmarshal(data, &content); err != nil {
+			return fmt.Errorf("unable to parse configuration file %s: %v", path, err)
+		}
+	}
+
+	update(content)
+
+	data, err = json.MarshalIndent(content, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0750); err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0600)
+}
@@ -0,0 +1,194 @@
+package config
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/openshift/backplane-cli/pkg/info"
+)
+
+const profilesConfig = `{
+  "url": "https://api.backplane.example.com",
+  "session-dir": "sessions",
+  "profiles": {
+    "prod": {"ocm-url": "production"},
+    "stage": {
+      "url": "https://api.stage.backplane.example.com",
+      "proxy-url": "http://stage.proxy.example.com",
+      "ocm-url": "https://api.stage.openshift.com/"
+    }
+  }
+}`
+
+// setupProfilesConfig points the backplane configuration to a file with the given content,
+// and the ocm session to the given URL
+func setupProfilesConfig(t *testing.T, content string, ocmURL string) string {
+	configPath := filepath.Join(t.TempDir(), "config.json")
+	if err := os.WriteFile(configPath, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv(info.BackplaneConfigPathEnvName, configPath)
+	t.Setenv(info.BackplaneURLEnvName, "")
+	t.Setenv(info.BackplaneProxyEnvName, "")
+
+	originalGetOCMURL := getOCMURL
+	getOCMURL = func() (string, error) { return ocmURL, nil }
+	t.Cleanup(func() {
+		getOCMURL = originalGetOCMURL
+		SetSelectedProfile("")
+	})
+
+	return configPath
+}
+
+func TestGetBackplaneConfigProfiles(t *testing.T) {
+	t.Run("it uses the top level values when no profile matches the ocm session", func(t *testing.T) {
+		setupProfilesConfig(t, profilesConfig, "https://api.example.com")
+
+		config, err := GetBackplaneConfiguration()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if config.Profile != "" || config.URL != "https://api.backplane.example.com" {
+			t.Errorf("expected the top level configuration, got profile %q and url %s", config.Profile, config.URL)
+		}
+	})
+
+	t.Run("it selects the profile matching the ocm session URL", func(t *testing.T) {
+		setupProfilesConfig(t, profilesConfig, "https://api.stage.openshift.com")
+
+		config, err := GetBackplaneConfiguration()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if config.Profile != "stage" || config.ProfileSource != ProfileSourceOCMURL {
+			t.Errorf("expected the stage profile selected by ocm-url, got %q selected by %q", config.Profile, config.ProfileSource)
+		}
+		if config.URL != "https://api.stage.backplane.example.com" || config.ProxyURL != "http://stage.proxy.example.com" {
+			t.Errorf("expected the stage profile values, got url %s and proxy %s", config.URL, config.ProxyURL)
+		}
+		if config.SessionDirectory != "sessions" {
+			t.Errorf("expected the top level session directory when the profile doesn't set it, got %s", config.SessionDirectory)
+		}
+	})
+
+	t.Run("it expands the ocm URL aliases", func(t *testing.T) {
+		setupProfilesConfig(t, profilesConfig, "https://api.openshift.com")
+
+		config, err := GetBackplaneConfiguration()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if config.Profile != "prod" {
+			t.Errorf("expected the prod profile, got %q", config.Profile)
+		}
+		if config.URL != "https://api.backplane.example.com" {
+			t.Errorf("expected the top level url when the profile doesn't set it, got %s", config.URL)
+		}
+	})
+
+	t.Run("it prefers the pinned profile to the ocm session URL", func(t *testing.T) {
+		configPath := setupProfilesConfig(t, profilesConfig, "https://api.openshift.com")
+		if err := SetCurrentProfile("stage"); err != nil {
+			t.Fatal(err)
+		}
+
+		config, err := GetBackplaneConfiguration()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if config.Profile != "stage" || config.ProfileSource != ProfileSourceCurrent {
+			t.Errorf("expected the pinned stage profile, got %q selected by %q", config.Profile, config.ProfileSource)
+		}
+
+		// The rest of the configuration file is kept
+		content := map[string]interface{}{}
+		data, err := os.ReadFile(configPath)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := json.Unmarshal(data, &content); err != nil {
+			t.Fatal(err)
+		}
+		if content["session-dir"] != "sessions" || content[CurrentProfileConfigVar] != "stage" {
+			t.Errorf("unexpected configuration file content %v", content)
+		}
+	})
+
+	t.Run("it prefers the profile flag to the pinned profile", func(t *testing.T) {
+		setupProfilesConfig(t, profilesConfig, "")
+		if err := SetCurrentProfile("stage"); err != nil {
+			t.Fatal(err)
+		}
+		SetSelectedProfile("prod")
+
+		config, err := GetBackplaneConfiguration()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if config.Profile != "prod" || config.ProfileSource != ProfileSourceFlag {
+			t.Errorf("expected the prod profile of the flag, got %q selected by %q", config.Profile, config.ProfileSource)
+		}
+	})
+
+	t.Run("it prefers the environment to the profile", func(t *testing.T) {
+		setupProfilesConfig(t, profilesConfig, "https://api.stage.openshift.com")
+		t.Setenv(info.BackplaneURLEnvName, "https://env.example.com")
+
+		config, err := GetBackplaneConfiguration()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if config.URL != "https://env.example.com" {
+			t.Errorf("expected the url of the environment, got %s", config.URL)
+		}
+	})
+
+	t.Run("it fails for an unknown profile", func(t *testing.T) {
+		setupProfilesConfig(t, profilesConfig, "")
+		SetSelectedProfile("unknown")
+
+		if _, err := GetBackplaneConfiguration(); err == nil {
+			t.Error("expected an error for an unknown profile")
+		}
+	})
+}
+
+func TestSetValue(t *testing.T) {
+	t.Run("it creates the profile", func(t *testing.T) {
+		setupProfilesConfig(t, `{"url": "https://api.backplane.example.com"}`, "integration")
+
+		if err := SetValue("int", OCMURLConfigVar, "integration"); err != nil {
+			t.Fatal(err)
+		}
+		if err := SetValue("int", URLConfigVar, "https://api.int.backplane.example.com"); err != nil {
+			t.Fatal(err)
+		}
+
+		profiles, err := GetProfiles()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(profiles) != 1 || profiles[0].Name != "int" || profiles[0].URL != "https://api.int.backplane.example.com" {
+			t.Errorf("unexpected profiles %v", profiles)
+		}
+
+		config, err := GetBackplaneConfiguration()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if config.Profile != "int" {
+			t.Errorf("expected the int profile to match the integration ocm session, got %q", config.Profile)
+		}
+	})
+
+	t.Run("it rejects invalid profile names", func(t *testing.T) {
+		setupProfilesConfig(t, `{}`, "")
+
+		if err := SetValue("my.profile", URLConfigVar, "https://example.com"); err == nil {
+			t.Error("expected an error for a profile name with a dot")
+		}
+	})
+}
@@ -0,0 +1,278 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+
+	logger "github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+	"golang.org/x/net/http/httpproxy"
+
+	"github.com/openshift/backplane-cli/pkg/info"
+	"github.com/openshift/backplane-cli/pkg/pac"
+)
+
+const (
+	// ProxyCacheTTLConfigVar is the config key of the duration the proxy selected among the candidates is reused
+	ProxyCacheTTLConfigVar = "proxy-cache-ttl"
+
+	// DefaultProxyCacheTTL is the default duration the proxy selected among the candidates is reused
+	DefaultProxyCacheTTL = time.Hour
+
+	// PACURLConfigVar is the config key of the proxy auto-config file, a path or a http(s) URL
+	PACURLConfigVar = "pac-url"
+)
+
+var (
+	// pacScripts caches the parsed proxy auto-config files by location, a command builds several clients
+	pacScripts      = map[string]*pac.Script{}
+	pacScriptsMutex sync.Mutex
+)
+
+// proxyCache is the proxy selected among the candidates for a backplane API, reused until it expires
+type proxyCache struct {
+	URL        string    `json:"url"`
+	Candidates []string  `json:"candidates"`
+	ProxyURL   string    `json:"proxy_url"`
+	Expires    time.Time `json:"expires"`
+}
+
+// probeProxy checks the backplane API of the configuration is reachable through the proxy,
+// with the TLS settings of the configuration
+var probeProxy = func(config BackplaneConfiguration, proxyURL string) error {
+	_, err := config.testHTTPRequestToBackplaneAPI(proxyURL)
+	return err
+}
+
+// ProxyFunc returns the function choosing the proxy of each request, to be used as the Proxy of
+// a http transport: the given proxy if any, else the one chosen by the proxy auto-config file if
+// configured, else the proxy of the environment. The NO_PROXY rules of the environment always apply.
+func (config BackplaneConfiguration) ProxyFunc(proxyURL string) (func(*http.Request) (*url.URL, error), error) {
+	noProxy := httpproxy.FromEnvironment().NoProxy
+
+	switch {
+	case proxyURL != "":
+		proxyURL = normalizeProxyURL(proxyURL)
+		if _, err := url.Parse(proxyURL); err != nil {
+			return nil, fmt.Errorf("invalid proxy URL %s: %v", proxyURL, err)
+		}
+		proxyFunc := (&httpproxy.Config{HTTPProxy: proxyURL, HTTPSProxy: proxyURL, NoProxy: noProxy}).ProxyFunc()
+		return func(req *http.Request) (*url.URL, error) {
+			return proxyFunc(req.URL)
+		}, nil
+	case config.PACURL != "":
+		script, err := loadPAC(config.PACURL)
+		if err != nil {
+			return nil, err
+		}
+		// The NO_PROXY rules are checked with a placeholder proxy, the script chooses the actual one
+		noProxyFunc := (&httpproxy.Config{HTTPProxy: "pac", HTTPSProxy: "pac", NoProxy: noProxy}).ProxyFunc()
+		return func(req *http.Request) (*url.URL, error) {
+			if proxy, err := noProxyFunc(req.URL); err != nil || proxy == nil {
+				return nil, err
+			}
+			return script.Proxy(req)
+		}, nil
+	default:
+		return http.ProxyFromEnvironment, nil
+	}
+}
+
+// ResolveProxyURL returns the proxy reaching the target URL, for the clients taking a single proxy
+// like the kubeconfig, or an empty string to connect directly. The proxy of the environment isn't
+// returned, those clients read it themselves.
+func (config BackplaneConfiguration) ResolveProxyURL(proxyURL string, target string) (string, error) {
+	if proxyURL == "" && config.PACURL == "" {
+		return "", nil
+	}
+
+	proxyFunc, err := config.ProxyFunc(proxyURL)
+	if err != nil {
+		return "", err
+	}
+	req, err := http.NewRequest(http.MethodGet, target, nil)
+	if err != nil {
+		return "", err
+	}
+	proxy, err := proxyFunc(req)
+	if err != nil || proxy == nil {
+		return "", err
+	}
+	return proxy.String(), nil
+}
+
+// normalizeProxyURL converts a socks5h proxy to socks5, the scheme supported by the http transport
+// and the kubeconfig. Both resolve the destination host on the SOCKS proxy anyway.
+func normalizeProxyURL(proxyURL string) string {
+	if rest, ok := strings.CutPrefix(proxyURL, "socks5h://"); ok {
+		return "socks5://" + rest
+	}
+	return proxyURL
+}
+
+// loadPAC reads and parses the proxy auto-config file at the path or http(s) URL.
+// A remote file is downloaded without proxy.
+func loadPAC(location string) (*pac.Script, error) {
+	pacScriptsMutex.Lock()
+	defer pacScriptsMutex.Unlock()
+
+	if script, ok := pacScripts[location]; ok {
+		return script, nil
+	}
+
+	source, err := readPAC(location)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read %s %s: %v", PACURLConfigVar, location, err)
+	}
+	script, err := pac.Parse(string(source))
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s %s: %v", PACURLConfigVar, location, err)
+	}
+
+	pacScripts[location] = script
+	return script, nil
+}
+
+// readPAC returns the content of the proxy auto-config file at the path or http(s) URL
+func readPAC(location string) ([]byte, error) {
+	if !strings.HasPrefix(location, "http://") && !strings.HasPrefix(location, "https://") {
+		return os.ReadFile(filepath.Clean(location))
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	client := &http.Client{Transport: transport, Timeout: 10 * time.Second}
+
+	resp, err := client.Get(location)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %s", resp.Status)
+	}
+	return io.ReadAll(resp.Body)
+}
+
+// getStringList returns the value of a configuration variable holding a string or a list of strings
+func getStringList(key string) []string {
+	switch value := viper.Get(key).(type) {
+	case string:
+		if value == "" {
+			return nil
+		}
+		return []string{value}
+	case []interface{}:
+		list := make([]string, 0, len(value))
+		for _, item := range value {
+			if s, ok := item.(string); ok && s != "" {
+				list = append(list, s)
+			}
+		}
+		return list
+	case []string:
+		return value
+	default:
+		return nil
+	}
+}
+
+// SelectProxyURL returns the first candidate proxy reaching the backplane API. The candidates are
+// only probed when there are several of them, and the choice is cached for the proxy cache TTL.
+// When no candidate works, the first one is used so the error is reported by the command itself.
+func (config BackplaneConfiguration) SelectProxyURL() string {
+	if len(config.ProxyURLs) <= 1 || config.URL == "" {
+		return config.ProxyURL
+	}
+
+	if proxyURL, ok := readCachedProxyURL(config.URL, config.ProxyURLs); ok {
+		logger.Debugf("Using cached proxy %s", proxyURL)
+		return proxyURL
+	}
+
+	// The candidates are probed concurrently, but the first one working in the configured order wins
+	errs := make([]error, len(config.ProxyURLs))
+	var wg sync.WaitGroup
+	for i, proxyURL := range config.ProxyURLs {
+		wg.Add(1)
+		go func(i int, proxyURL string) {
+			defer wg.Done()
+			errs[i] = probeProxy(config, proxyURL)
+		}(i, proxyURL)
+	}
+	wg.Wait()
+
+	for i, proxyURL := range config.ProxyURLs {
+		if errs[i] != nil {
+			logger.Debugf("Proxy %s can't reach %s: %v", proxyURL, config.URL, errs[i])
+			continue
+		}
+		logger.Debugf("Selected proxy %s", proxyURL)
+		if config.ProxyCacheTTL > 0 {
+			writeCachedProxyURL(proxyCache{
+				URL:        config.URL,
+				Candidates: config.ProxyURLs,
+				ProxyURL:   proxyURL,
+				Expires:    time.Now().Add(config.ProxyCacheTTL),
+			})
+		}
+		return proxyURL
+	}
+
+	logger.Warnf("None of the proxies %v can reach the backplane API %s, check your VPN connection", config.ProxyURLs, config.URL)
+	return config.ProxyURLs[0]
+}
+
+// readCachedProxyURL returns the cached proxy if it was selected among the same candidates and hasn't expired
+func readCachedProxyURL(backplaneURL string, candidates []string) (string, bool) {
+	path, err := GetConfigDirFilePath(info.BackplaneProxyCacheFileName)
+	if err != nil {
+		return "", false
+	}
+
+	content, err := os.ReadFile(filepath.Clean(path))
+	if err != nil {
+		return "", false
+	}
+
+	cache := proxyCache{}
+	if err := json.Unmarshal(content, &cache); err != nil {
+		return "", false
+	}
+
+	if cache.URL != backplaneURL || !reflect.DeepEqual(cache.Candidates, candidates) || time.Now().After(cache.Expires) {
+		return "", false
+	}
+	return cache.ProxyURL, true
+}
+
+// writeCachedProxyURL saves the selected proxy. Failing to save it only makes the next command probe again.
+func writeCachedProxyURL(cache proxyCache) {
+	path, err := GetConfigDirFilePath(info.BackplaneProxyCacheFileName)
+	if err != nil {
+		return
+	}
+
+	content, err := json.Marshal(cache)
+	if err != nil {
+		return
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0750); err != nil {
+		logger.Debugf("Unable to save the proxy cache: %v", err)
+		return
+	}
+	if err := os.WriteFile(path, content, 0600); err != nil {
+		logger.Debugf("Unable to save the proxy cache: %v", err)
+	}
+}
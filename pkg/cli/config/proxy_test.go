@@ -0,0 +1,343 @@
+package config
+
+import (
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/openshift/backplane-cli/pkg/info"
+)
+
+// stubProbeProxy makes the given proxies reachable, and records the probed ones
+func stubProbeProxy(t *testing.T, reachable ...string) *[]string {
+	probed := []string{}
+	var mu sync.Mutex
+
+	originalProbeProxy := probeProxy
+	probeProxy = func(config BackplaneConfiguration, proxyURL string) error {
+		mu.Lock()
+		defer mu.Unlock()
+		probed = append(probed, proxyURL)
+		for _, r := range reachable {
+			if r == proxyURL {
+				return nil
+			}
+		}
+		return errors.New("connection refused")
+	}
+	t.Cleanup(func() { probeProxy = originalProbeProxy })
+
+	return &probed
+}
+
+const proxiesConfig = `{
+  "url": "https://api.backplane.example.com",
+  "proxy-url": ["http://proxy-1.example.com", "http://proxy-2.example.com", "http://proxy-3.example.com"]
+}`
+
+func TestProxyURLValue(t *testing.T) {
+	t.Run("it returns the configured candidates, not the selected proxy", func(t *testing.T) {
+		setupProfilesConfig(t, proxiesConfig, "")
+		stubProbeProxy(t, "http://proxy-2.example.com")
+
+		config, err := GetBackplaneConfiguration()
+		if err != nil {
+			t.Fatal(err)
+		}
+		value, err := config.Value(ProxyURLConfigVar)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(value, config.ProxyURLs) || len(config.ProxyURLs) != 3 {
+			t.Errorf("expected the 3 candidates, got %v", value)
+		}
+	})
+}
+
+func TestSelectProxyURL(t *testing.T) {
+	t.Run("it doesn't probe the candidates when loading the configuration", func(t *testing.T) {
+		setupProfilesConfig(t, proxiesConfig, "")
+		probed := stubProbeProxy(t, "http://proxy-2.example.com")
+
+		config, err := GetBackplaneConfiguration()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if config.ProxyURL != "http://proxy-1.example.com" || len(*probed) != 0 {
+			t.Errorf("expected the first proxy without probing, got %s after probing %v", config.ProxyURL, *probed)
+		}
+	})
+
+	t.Run("it picks the first candidate reaching the backplane API", func(t *testing.T) {
+		setupProfilesConfig(t, proxiesConfig, "")
+		stubProbeProxy(t, "http://proxy-2.example.com", "http://proxy-3.example.com")
+
+		config, err := GetBackplaneConfiguration()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if proxyURL := config.SelectProxyURL(); proxyURL != "http://proxy-2.example.com" {
+			t.Errorf("expected the second proxy, got %s", proxyURL)
+		}
+		if len(config.ProxyURLs) != 3 {
+			t.Errorf("expected the 3 candidates, got %v", config.ProxyURLs)
+		}
+	})
+
+	t.Run("it reuses the selected proxy until the cache expires", func(t *testing.T) {
+		configPath := setupProfilesConfig(t, proxiesConfig, "")
+		stubProbeProxy(t, "http://proxy-3.example.com")
+		config, err := GetBackplaneConfiguration()
+		if err != nil {
+			t.Fatal(err)
+		}
+		config.SelectProxyURL()
+
+		probed := stubProbeProxy(t, "http://proxy-1.example.com")
+		if proxyURL := config.SelectProxyURL(); proxyURL != "http://proxy-3.example.com" || len(*probed) != 0 {
+			t.Errorf("expected the cached proxy without probing, got %s after probing %v", proxyURL, *probed)
+		}
+
+		// Changing the candidates invalidates the cache
+		content := `{"url": "https://api.backplane.example.com", "proxy-url": ["http://proxy-1.example.com", "http://proxy-3.example.com"]}`
+		if err := os.WriteFile(configPath, []byte(content), 0600); err != nil {
+			t.Fatal(err)
+		}
+		config, err = GetBackplaneConfiguration()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if proxyURL := config.SelectProxyURL(); proxyURL != "http://proxy-1.example.com" {
+			t.Errorf("expected the cache to be invalidated, got %s", proxyURL)
+		}
+	})
+
+	t.Run("it doesn't cache the proxy when the TTL is 0", func(t *testing.T) {
+		configPath := setupProfilesConfig(t, `{
+  "url": "https://api.backplane.example.com",
+  "proxy-url": ["http://proxy-1.example.com", "http://proxy-2.example.com"],
+  "proxy-cache-ttl": "0s"
+}`, "")
+		stubProbeProxy(t, "http://proxy-1.example.com")
+		config, err := GetBackplaneConfiguration()
+		if err != nil {
+			t.Fatal(err)
+		}
+		config.SelectProxyURL()
+
+		if _, err := os.Stat(filepath.Join(filepath.Dir(configPath), info.BackplaneProxyCacheFileName)); !os.IsNotExist(err) {
+			t.Errorf("expected no proxy cache, got %v", err)
+		}
+	})
+
+	t.Run("it falls back to the first candidate when none works", func(t *testing.T) {
+		setupProfilesConfig(t, proxiesConfig, "")
+		stubProbeProxy(t)
+
+		config, err := GetBackplaneConfiguration()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if proxyURL := config.SelectProxyURL(); proxyURL != "http://proxy-1.example.com" {
+			t.Errorf("expected the first proxy, got %s", proxyURL)
+		}
+	})
+
+	t.Run("it doesn't probe a single proxy", func(t *testing.T) {
+		setupProfilesConfig(t, `{"url": "https://api.backplane.example.com", "proxy-url": "http://proxy-1.example.com"}`, "")
+		probed := stubProbeProxy(t)
+
+		config, err := GetBackplaneConfiguration()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if proxyURL := config.SelectProxyURL(); proxyURL != "http://proxy-1.example.com" || len(*probed) != 0 {
+			t.Errorf("expected the proxy without probing, got %s after probing %v", proxyURL, *probed)
+		}
+	})
+}
+
+// newTunnelProxy starts a proxy tunneling every CONNECT request to the target address
+func newTunnelProxy(t *testing.T, target string) *httptest.Server {
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodConnect {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		upstream, err := net.Dial("tcp", target)
+		if err != nil {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		defer upstream.Close()
+		w.WriteHeader(http.StatusOK)
+		conn, _, err := w.(http.Hijacker).Hijack()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		go func() {
+			_, _ = io.Copy(upstream, conn)
+		}()
+		_, _ = io.Copy(conn, upstream)
+	}))
+	t.Cleanup(proxy.Close)
+	return proxy
+}
+
+func TestSelectProxyURLTLS(t *testing.T) {
+	t.Run("it probes the candidates trusting the CA file", func(t *testing.T) {
+		svr := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+		defer svr.Close()
+		caFile := writeServerCA(t, svr)
+		proxy := newTunnelProxy(t, svr.Listener.Addr().String())
+		t.Setenv("NO_PROXY", "")
+		t.Setenv("no_proxy", "")
+
+		// The certificate of the test server is valid for example.com, which the proxy reaches
+		setupProfilesConfig(t, `{
+  "url": "https://example.com",
+  "proxy-url": ["http://127.0.0.1:1", "`+proxy.URL+`"],
+  "ca-file": "`+caFile+`"
+}`, "")
+
+		config, err := GetBackplaneConfiguration()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if proxyURL := config.SelectProxyURL(); proxyURL != proxy.URL {
+			t.Errorf("expected the proxy reaching the API, got %s", proxyURL)
+		}
+	})
+}
+
+func TestParseProxyURLList(t *testing.T) {
+	key, err := LookupKey(ProxyURLConfigVar)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	value, err := key.Parse("http://proxy-1.example.com, http://proxy-2.example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(value, []string{"http://proxy-1.example.com", "http://proxy-2.example.com"}) {
+		t.Errorf("unexpected proxy list %v", value)
+	}
+
+	if _, err := key.Parse("http://proxy-1.example.com,proxy-2"); err == nil {
+		t.Error("expected an error for an invalid proxy in the list")
+	}
+}
+
+func TestCheckAPIConnectionKeepsDefaultTransport(t *testing.T) {
+	svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer svr.Close()
+
+	defaultTransport := http.DefaultTransport
+	config := BackplaneConfiguration{URL: "http://api.backplane.example.com", ProxyURL: svr.URL}
+	if err := config.CheckAPIConnection(); err != nil {
+		t.Fatal(err)
+	}
+	if http.DefaultTransport != defaultTransport {
+		t.Error("expected the default transport to be left as is")
+	}
+}
+
+func TestProxyFunc(t *testing.T) {
+	t.Setenv("NO_PROXY", ".internal.example.com")
+	t.Setenv("no_proxy", "")
+
+	pacFile := filepath.Join(t.TempDir(), "proxy.pac")
+	err := os.WriteFile(pacFile, []byte(`function FindProxyForURL(url, host) {
+	if (dnsDomainIs(host, ".stage.backplane.example.com"))
+		return "SOCKS5 localhost:1080";
+	return "PROXY squid.example.com:3128";
+}`), 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		config   BackplaneConfiguration
+		proxyURL string
+		target   string
+		expected string
+	}{
+		{"it uses the given proxy", BackplaneConfiguration{}, "http://squid.example.com:3128", "https://api.backplane.example.com", "http://squid.example.com:3128"},
+		{"it converts socks5h to socks5", BackplaneConfiguration{}, "socks5h://localhost:1080", "https://api.backplane.example.com", "socks5://localhost:1080"},
+		{"it applies NO_PROXY to the given proxy", BackplaneConfiguration{}, "socks5://localhost:1080", "https://console.internal.example.com", ""},
+		{"it prefers the given proxy to the PAC file", BackplaneConfiguration{PACURL: pacFile}, "http://other.example.com:3128", "https://api.stage.backplane.example.com", "http://other.example.com:3128"},
+		{"it uses the proxy chosen by the PAC file", BackplaneConfiguration{PACURL: pacFile}, "", "https://api.stage.backplane.example.com", "socks5://localhost:1080"},
+		{"it uses the default proxy of the PAC file", BackplaneConfiguration{PACURL: pacFile}, "", "https://api.backplane.example.com", "http://squid.example.com:3128"},
+		{"it applies NO_PROXY before the PAC file", BackplaneConfiguration{PACURL: pacFile}, "", "https://console.internal.example.com", ""},
+		{"it leaves the environment proxy to the client", BackplaneConfiguration{}, "", "https://api.backplane.example.com", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			proxyURL, err := tt.config.ResolveProxyURL(tt.proxyURL, tt.target)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if proxyURL != tt.expected {
+				t.Errorf("expected proxy %q, got %q", tt.expected, proxyURL)
+			}
+		})
+	}
+
+	t.Run("it fails for an invalid PAC file", func(t *testing.T) {
+		invalid := filepath.Join(t.TempDir(), "proxy.pac")
+		if err := os.WriteFile(invalid, []byte(`function FindProxy(url, host) { return "PROXY " + host; }`), 0600); err != nil {
+			t.Fatal(err)
+		}
+
+		if _, err := (BackplaneConfiguration{PACURL: invalid}).NewTransport(""); err == nil {
+			t.Error("expected an error for an invalid PAC file")
+		}
+		key, _ := LookupKey(PACURLConfigVar)
+		if _, err := key.Parse(invalid); err == nil {
+			t.Error("expected config set to refuse an invalid PAC file")
+		}
+	})
+
+	t.Run("it downloads a remote PAC file", func(t *testing.T) {
+		svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write([]byte(`function FindProxyForURL(url, host) { return "DIRECT"; }`))
+		}))
+		defer svr.Close()
+
+		proxyURL, err := BackplaneConfiguration{PACURL: svr.URL + "/proxy.pac"}.ResolveProxyURL("", "https://api.backplane.example.com")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if proxyURL != "" {
+			t.Errorf("expected a direct connection, got %s", proxyURL)
+		}
+	})
+}
+
+func TestParseProxyURL(t *testing.T) {
+	key, err := LookupKey(ProxyURLConfigVar)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, value := range []string{"http://squid.example.com:3128", "socks5://localhost:1080", "socks5h://localhost:1080"} {
+		if _, err := key.Parse(value); err != nil {
+			t.Errorf("expected %s to be valid: %v", value, err)
+		}
+	}
+	for _, value := range []string{"socks4://localhost:1080", "localhost:1080", "socks5://"} {
+		if _, err := key.Parse(value); err == nil {
+			t.Errorf("expected %s to be invalid", value)
+		}
+	}
+}
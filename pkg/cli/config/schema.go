@@ -0,0 +1,310 @@
+package config
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/openshift/backplane-cli/pkg/info"
+	"github.com/openshift/backplane-cli/pkg/pac"
+)
+
+// ValueType is the type of the value of a configuration variable
+type ValueType string
+
+const (
+	StringType   ValueType = "string"
+	URLType      ValueType = "url"
+	DurationType ValueType = "duration"
+	IntType      ValueType = "int"
+	ARNType      ValueType = "arn"
+	OCMURLType   ValueType = "ocm-url"
+	BoolType     ValueType = "bool"
+	PEMFileType  ValueType = "pem-file"
+	ProxyURLType ValueType = "proxy-url"
+	PACType      ValueType = "pac"
+)
+
+// Key describes a variable of the configuration file
+type Key struct {
+	Name        string
+	Type        ValueType
+	Description string
+	// Required variables must be set for the backplane commands to work
+	Required bool
+	// ProfileOnly variables can only be set in a profile
+	ProfileOnly bool
+	// List variables accept a comma separated list of values
+	List bool
+	// EnvName is the environment variable taking precedence over the variable, if any
+	EnvName string
+
+	// value returns the value of the variable in the configuration
+	value func(BackplaneConfiguration) interface{}
+}
+
+// Keys is the schema of the configuration file, with a variable per field of BackplaneConfiguration
+var Keys = []Key{
+	{
+		Name:        URLConfigVar,
+		Type:        URLType,
+		Description: "Backplane API URL",
+		Required:    true,
+		EnvName:     info.BackplaneURLEnvName,
+		value:       func(c BackplaneConfiguration) interface{} { return c.URL },
+	},
+	{
+		Name:        ProxyURLConfigVar,
+		Type:        ProxyURLType,
+		Description: "Squid or SOCKS5 proxy URL, or comma separated candidate proxies, the first reaching the backplane API is used",
+		List:        true,
+		EnvName:     info.BackplaneProxyEnvName,
+		value:       func(c BackplaneConfiguration) interface{} { return c.ProxyURLs },
+	},
+	{
+		Name:        PACURLConfigVar,
+		Type:        PACType,
+		Description: "Proxy auto-config file path or URL choosing the proxy per destination when no proxy-url is set",
+		value:       func(c BackplaneConfiguration) interface{} { return c.PACURL },
+	},
+	{
+		Name:        SessionConfigVar,
+		Type:        StringType,
+		Description: "Backplane CLI session directory",
+		value:       func(c BackplaneConfiguration) interface{} { return c.SessionDirectory },
+	},
+	{
+		Name:        SessionTemplateConfigVar,
+		Type:        StringType,
+		Description: "Directory of the scripts and rc fragments copied into every session, relative to the home directory. Defaults to " + info.BackplaneDefaultSessionTemplateDirectory,
+		value:       func(c BackplaneConfiguration) interface{} { return c.SessionTemplateDirectory },
+	},
+	{
+		Name:        AssumeInitialArnConfigVar,
+		Type:        ARNType,
+		Description: "ARN of the initial role assumed by the cloud commands",
+		value:       func(c BackplaneConfiguration) interface{} { return c.AssumeInitialArn },
+	},
+	{
+		Name:        TokenExpiryWarningConfigVar,
+		Type:        DurationType,
+		Description: "Warn when the OCM token expires within this duration, eg. 5m. 0 disables the warning",
+		value:       func(c BackplaneConfiguration) interface{} { return c.TokenExpiryWarning.String() },
+	},
+	{
+		Name:        MaxRetriesConfigVar,
+		Type:        IntType,
+		Description: "Number of retries of a backplane API request failing with a transient error. 0 disables the retries",
+		value:       func(c BackplaneConfiguration) interface{} { return c.MaxRetries },
+	},
+	{
+		Name:        ProxyCacheTTLConfigVar,
+		Type:        DurationType,
+		Description: "Duration the proxy selected among the candidate proxies is reused before probing them again. 0 disables the cache",
+		value:       func(c BackplaneConfiguration) interface{} { return c.ProxyCacheTTL.String() },
+	},
+	{
+		Name:        CAFileConfigVar,
+		Type:        PEMFileType,
+		Description: "PEM bundle of CAs trusted on top of the system ones for the backplane, STS and monitoring connections, eg. of a TLS-intercepting proxy. It is written to the kubeconfig as the cluster CA",
+		value:       func(c BackplaneConfiguration) interface{} { return c.CAFile },
+	},
+	{
+		Name:        InsecureSkipVerifyProxyConfigVar,
+		Type:        BoolType,
+		Description: "Skip the TLS verification of the connections going through proxy-url, not the proxies chosen by pac-url. Prefer ca-file",
+		value:       func(c BackplaneConfiguration) interface{} { return c.InsecureSkipVerifyProxy },
+	},
+	{
+		Name:        OCMURLConfigVar,
+		Type:        OCMURLType,
+		Description: "OCM API URL or alias (production, staging, integration) the profile is selected for",
+		ProfileOnly: true,
+		value:       func(c BackplaneConfiguration) interface{} { return c.OCMURL },
+	},
+}
+
+// LookupKey returns the schema of the configuration variable
+func LookupKey(name string) (Key, error) {
+	for _, key := range Keys {
+		if key.Name == name {
+			return key, nil
+		}
+	}
+	return Key{}, fmt.Errorf("unknown config variable %s, supported variables are %s", name, strings.Join(KeyNames(), ", "))
+}
+
+// KeyNames returns the names of the configuration variables
+func KeyNames() []string {
+	names := make([]string, 0, len(Keys))
+	for _, key := range Keys {
+		names = append(names, key.Name)
+	}
+	return names
+}
+
+// Parse validates the value of the variable given as a string, and converts it to the type stored
+// in the configuration file
+func (k Key) Parse(value string) (interface{}, error) {
+	if k.List && strings.Contains(value, ",") {
+		values := []string{}
+		for _, item := range strings.Split(value, ",") {
+			item = strings.TrimSpace(item)
+			if item == "" {
+				continue
+			}
+			if _, err := k.parseValue(item); err != nil {
+				return nil, err
+			}
+			values = append(values, item)
+		}
+		return values, nil
+	}
+	return k.parseValue(value)
+}
+
+// parseValue validates and converts a single value of the variable
+func (k Key) parseValue(value string) (interface{}, error) {
+	switch k.Type {
+	case URLType:
+		if err := validateURL(value); err != nil {
+			return nil, fmt.Errorf("invalid URL %s for %s: %v", value, k.Name, err)
+		}
+	case ProxyURLType:
+		if err := validateProxyURL(value); err != nil {
+			return nil, fmt.Errorf("invalid proxy URL %s for %s: %v", value, k.Name, err)
+		}
+	case PACType:
+		if validateURL(value) != nil {
+			source, err := readPAC(value)
+			if err != nil {
+				return nil, fmt.Errorf("unable to read %s %s: %v", k.Name, value, err)
+			}
+			if _, err := pac.Parse(string(source)); err != nil {
+				return nil, fmt.Errorf("invalid %s %s: %v", k.Name, value, err)
+			}
+		}
+	case OCMURLType:
+		if _, ok := ocmURLAliases[strings.ToLower(value)]; !ok {
+			if err := validateURL(value); err != nil {
+				return nil, fmt.Errorf("invalid URL %s for %s, it must be a URL or one of production, staging and integration: %v", value, k.Name, err)
+			}
+		}
+	case DurationType:
+		if _, err := time.ParseDuration(value); err != nil {
+			return nil, fmt.Errorf("invalid duration %s for %s: %v", value, k.Name, err)
+		}
+	case IntType:
+		number, err := strconv.Atoi(value)
+		if err != nil || number < 0 {
+			return nil, fmt.Errorf("invalid number %s for %s, it must be a positive integer", value, k.Name)
+		}
+		return number, nil
+	case BoolType:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid boolean %s for %s, it must be true or false", value, k.Name)
+		}
+		return b, nil
+	case PEMFileType:
+		if _, err := (BackplaneConfiguration{CAFile: value}).ReadCAFile(); err != nil {
+			return nil, err
+		}
+	case ARNType:
+		if !strings.HasPrefix(value, "arn:") || len(strings.Split(value, ":")) < 6 {
+			return nil, fmt.Errorf("invalid ARN %s for %s, it must look like arn:aws:iam::<account>:role/<name>", value, k.Name)
+		}
+	}
+	return value, nil
+}
+
+// Value returns the value of the variable in the configuration
+func (c BackplaneConfiguration) Value(name string) (interface{}, error) {
+	key, err := LookupKey(name)
+	if err != nil {
+		return nil, err
+	}
+	return key.value(c), nil
+}
+
+// validateValue validates a value read from the configuration file
+func (k Key) validateValue(value interface{}) error {
+	switch v := value.(type) {
+	case string:
+		// Unset variables used to be written as empty strings
+		if v == "" {
+			return nil
+		}
+		if k.Type == IntType {
+			return fmt.Errorf("invalid value %q for %s, it must be a number", v, k.Name)
+		}
+		if k.Type == BoolType {
+			return fmt.Errorf("invalid value %q for %s, it must be true or false", v, k.Name)
+		}
+		_, err := k.Parse(v)
+		return err
+	case []interface{}:
+		if !k.List {
+			return fmt.Errorf("invalid value %v for %s, it must be a single %s", v, k.Name, k.Type)
+		}
+		for _, item := range v {
+			s, ok := item.(string)
+			if !ok {
+				return fmt.Errorf("invalid value %v for %s, it must be a list of strings", v, k.Name)
+			}
+			if _, err := k.parseValue(s); err != nil {
+				return err
+			}
+		}
+		return nil
+	case bool:
+		if k.Type != BoolType {
+			return fmt.Errorf("invalid value %v for %s, it must be a %s", v, k.Name, k.Type)
+		}
+		return nil
+	case float64:
+		if k.Type != IntType {
+			return fmt.Errorf("invalid value %v for %s, it must be a string", v, k.Name)
+		}
+		if v != float64(int(v)) || v < 0 {
+			return fmt.Errorf("invalid number %v for %s, it must be a positive integer", v, k.Name)
+		}
+		return nil
+	default:
+		return fmt.Errorf("invalid value %v for %s, it must be a %s", v, k.Name, k.Type)
+	}
+}
+
+// validateProxyURL checks the value is an absolute http(s) or SOCKS5 proxy URL
+func validateProxyURL(value string) error {
+	u, err := url.Parse(value)
+	if err != nil {
+		return err
+	}
+	switch u.Scheme {
+	case "http", "https", "socks5", "socks5h":
+	default:
+		return fmt.Errorf("the scheme must be http, https, socks5 or socks5h")
+	}
+	if u.Host == "" {
+		return fmt.Errorf("the host is missing")
+	}
+	return nil
+}
+
+// validateURL checks the value is an absolute http(s) URL
+func validateURL(value string) error {
+	u, err := url.Parse(value)
+	if err != nil {
+		return err
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return fmt.Errorf("the scheme must be http or https")
+	}
+	if u.Host == "" {
+		return fmt.Errorf("the host is missing")
+	}
+	return nil
+}
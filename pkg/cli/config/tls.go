@@ -0,0 +1,84 @@
+package config
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+)
+
+const (
+	// CAFileConfigVar is the config key of the PEM bundle of extra CAs trusted for the backplane connections
+	CAFileConfigVar = "ca-file"
+
+	// InsecureSkipVerifyProxyConfigVar is the config key skipping the TLS verification of the connections
+	// going through the proxy
+	InsecureSkipVerifyProxyConfigVar = "insecure-skip-verify-proxy"
+)
+
+// ReadCAFile returns the PEM content of the configured CA file, nil when no CA file is configured
+func (config BackplaneConfiguration) ReadCAFile() ([]byte, error) {
+	if config.CAFile == "" {
+		return nil, nil
+	}
+
+	caData, err := os.ReadFile(filepath.Clean(config.CAFile))
+	if err != nil {
+		return nil, fmt.Errorf("unable to read %s %s: %v", CAFileConfigVar, config.CAFile, err)
+	}
+	if !x509.NewCertPool().AppendCertsFromPEM(caData) {
+		return nil, fmt.Errorf("no PEM certificate found in %s %s", CAFileConfigVar, config.CAFile)
+	}
+	return caData, nil
+}
+
+// SkipTLSVerify tells whether the TLS verification is skipped for connections through the given proxy
+func (config BackplaneConfiguration) SkipTLSVerify(proxyURL string) bool {
+	return config.InsecureSkipVerifyProxy && proxyURL != ""
+}
+
+// TLSConfig returns the TLS client configuration of the connections through the given proxy, if any.
+// The CA file is trusted on top of the system CAs. It returns nil when the default configuration applies.
+func (config BackplaneConfiguration) TLSConfig(proxyURL string) (*tls.Config, error) {
+	if config.SkipTLSVerify(proxyURL) {
+		// #nosec G402 -- explicitly configured for TLS-intercepting proxies
+		return &tls.Config{InsecureSkipVerify: true}, nil
+	}
+
+	caData, err := config.ReadCAFile()
+	if err != nil || caData == nil {
+		return nil, err
+	}
+
+	pool, err := x509.SystemCertPool()
+	if err != nil || pool == nil {
+		pool = x509.NewCertPool()
+	}
+	pool.AppendCertsFromPEM(caData)
+
+	return &tls.Config{RootCAs: pool, MinVersion: tls.VersionTLS12}, nil
+}
+
+// NewTransport returns a http transport going through the given proxy, or the one chosen by ProxyFunc,
+// with the configured TLS settings. It starts from a copy of the default transport, which is left as is.
+func (config BackplaneConfiguration) NewTransport(proxyURL string) (*http.Transport, error) {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+
+	proxyFunc, err := config.ProxyFunc(proxyURL)
+	if err != nil {
+		return nil, err
+	}
+	transport.Proxy = proxyFunc
+
+	tlsConfig, err := config.TLSConfig(proxyURL)
+	if err != nil {
+		return nil, err
+	}
+	if tlsConfig != nil {
+		transport.TLSClientConfig = tlsConfig
+	}
+
+	return transport, nil
+}
@@ -0,0 +1,94 @@
+package config
+
+import (
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// writeServerCA writes the certificate of the test server as a PEM CA file
+func writeServerCA(t *testing.T, svr *httptest.Server) string {
+	caFile := filepath.Join(t.TempDir(), "ca.pem")
+	caData := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: svr.Certificate().Raw})
+	if err := os.WriteFile(caFile, caData, 0600); err != nil {
+		t.Fatal(err)
+	}
+	return caFile
+}
+
+func TestNewTransport(t *testing.T) {
+	svr := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer svr.Close()
+
+	get := func(config BackplaneConfiguration, proxyURL string) error {
+		transport, err := config.NewTransport(proxyURL)
+		if err != nil {
+			return err
+		}
+		resp, err := (&http.Client{Transport: transport}).Get(svr.URL)
+		if err != nil {
+			return err
+		}
+		return resp.Body.Close()
+	}
+
+	t.Run("it doesn't trust an unknown CA by default", func(t *testing.T) {
+		if err := get(BackplaneConfiguration{}, ""); err == nil {
+			t.Error("expected a TLS verification error")
+		}
+	})
+
+	t.Run("it trusts the CA file", func(t *testing.T) {
+		if err := get(BackplaneConfiguration{CAFile: writeServerCA(t, svr)}, ""); err != nil {
+			t.Error(err)
+		}
+	})
+
+	t.Run("it skips the TLS verification only through the proxy", func(t *testing.T) {
+		config := BackplaneConfiguration{InsecureSkipVerifyProxy: true}
+		if err := get(config, ""); err == nil {
+			t.Error("expected a TLS verification error without proxy")
+		}
+
+		tlsConfig, err := config.TLSConfig("http://proxy.example.com")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if tlsConfig == nil || !tlsConfig.InsecureSkipVerify {
+			t.Errorf("expected the TLS verification to be skipped through the proxy, got %v", tlsConfig)
+		}
+	})
+
+	t.Run("it fails for a CA file without certificate", func(t *testing.T) {
+		caFile := filepath.Join(t.TempDir(), "ca.pem")
+		if err := os.WriteFile(caFile, []byte("not a certificate"), 0600); err != nil {
+			t.Fatal(err)
+		}
+
+		if _, err := (BackplaneConfiguration{CAFile: caFile}).NewTransport(""); err == nil {
+			t.Error("expected an error for an invalid CA file")
+		}
+	})
+}
+
+func TestGetBackplaneConfigTLS(t *testing.T) {
+	svr := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer svr.Close()
+	caFile := writeServerCA(t, svr)
+
+	setupProfilesConfig(t, `{"url": "`+svr.URL+`", "ca-file": "`+caFile+`", "insecure-skip-verify-proxy": true}`, "")
+
+	config, err := GetBackplaneConfiguration()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if config.CAFile != caFile || !config.InsecureSkipVerifyProxy {
+		t.Errorf("unexpected TLS settings %s and %v", config.CAFile, config.InsecureSkipVerifyProxy)
+	}
+	if err := config.CheckAPIConnection(); err != nil {
+		t.Errorf("expected the API connection check to trust the CA file: %v", err)
+	}
+}
@@ -0,0 +1,190 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// ValidateConfigFile checks the configuration file against the schema, and returns the problems found:
+// unknown variables, invalid values and missing required values. A missing file is validated as empty.
+func ValidateConfigFile() ([]string, error) {
+	path, err := GetConfigFilePath()
+	if err != nil {
+		return nil, err
+	}
+
+	content := map[string]interface{}{}
+	data, err := os.ReadFile(filepath.Clean(path))
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+	if len(data) > 0 {
+		if err := json.Unmarshal(data, &content); err != nil {
+			return []string{fmt.Sprintf("invalid JSON: %v", err)}, nil
+		}
+	}
+
+	return validateConfigContent(content), nil
+}
+
+// validateConfigContent returns the problems of the content of a configuration file
+func validateConfigContent(content map[string]interface{}) []string {
+	problems := []string{}
+
+	profiles := map[string]interface{}{}
+	if value, ok := content[ProfilesConfigVar]; ok {
+		if profiles, ok = value.(map[string]interface{}); !ok {
+			problems = append(problems, fmt.Sprintf("%s: must be an object of profiles", ProfilesConfigVar))
+		}
+	}
+
+	if value, ok := content[CurrentProfileConfigVar]; ok {
+		current, _ := value.(string)
+		if _, found := profiles[current]; !found {
+			problems = append(problems, fmt.Sprintf("%s: profile %v not found", CurrentProfileConfigVar, value))
+		}
+	}
+
+	topLevel := map[string]interface{}{}
+	for name, value := range content {
+		if name != ProfilesConfigVar && name != CurrentProfileConfigVar {
+			topLevel[name] = value
+		}
+	}
+	problems = append(problems, validateVariables("", topLevel, false)...)
+
+	for _, name := range sortedKeys(profiles) {
+		prefix := fmt.Sprintf("%s.%s.", ProfilesConfigVar, name)
+		if err := ValidateProfileName(name); err != nil {
+			problems = append(problems, fmt.Sprintf("%s: %v", prefix[:len(prefix)-1], err))
+			continue
+		}
+		profile, ok := profiles[name].(map[string]interface{})
+		if !ok {
+			problems = append(problems, fmt.Sprintf("%s: must be an object of variables", prefix[:len(prefix)-1]))
+			continue
+		}
+		problems = append(problems, validateVariables(prefix, profile, true)...)
+	}
+
+	// The profiles fall back to the top level values
+	problems = append(problems, validateInsecurePAC("", topLevel)...)
+	for _, name := range sortedKeys(profiles) {
+		profile, ok := profiles[name].(map[string]interface{})
+		if !ok || ValidateProfileName(name) != nil {
+			continue
+		}
+		effective := map[string]interface{}{}
+		for key, value := range topLevel {
+			effective[key] = value
+		}
+		for key, value := range profile {
+			effective[key] = value
+		}
+		problems = append(problems, validateInsecurePAC(fmt.Sprintf("%s.%s.", ProfilesConfigVar, name), effective)...)
+	}
+
+	// Required variables are needed at the top level, used when no profile applies, unless the selected
+	// profile or every profile sets them, or their environment variable is set
+	selected := selectedProfile
+	if selected == "" {
+		selected, _ = content[CurrentProfileConfigVar].(string)
+	}
+	for _, key := range Keys {
+		if !key.Required || key.ProfileOnly {
+			continue
+		}
+		if isRequiredValueSet(key, topLevel, profiles, selected) {
+			continue
+		}
+		if key.EnvName != "" && os.Getenv(key.EnvName) != "" {
+			continue
+		}
+		problems = append(problems, fmt.Sprintf("%s: missing required value", key.Name))
+	}
+
+	return problems
+}
+
+// isRequiredValueSet tells whether a required variable has a valid value at the top level, in the
+// selected profile or in every profile
+func isRequiredValueSet(key Key, topLevel map[string]interface{}, profiles map[string]interface{}, selected string) bool {
+	isSet := func(variables map[string]interface{}) bool {
+		value, _ := variables[key.Name].(string)
+		return value != "" && key.validateValue(value) == nil
+	}
+
+	if isSet(topLevel) {
+		return true
+	}
+	if profile, ok := profiles[selected].(map[string]interface{}); ok && isSet(profile) {
+		return true
+	}
+	if len(profiles) == 0 {
+		return false
+	}
+	for _, value := range profiles {
+		if profile, ok := value.(map[string]interface{}); !ok || !isSet(profile) {
+			return false
+		}
+	}
+	return true
+}
+
+// validateInsecurePAC refuses skipping the TLS verification through the proxies chosen by the proxy
+// auto-config file: the clients only know the proxy of each request when connecting, so they can't
+// skip it for those connections only. The CA of the proxy can be trusted with the CA file instead.
+func validateInsecurePAC(prefix string, variables map[string]interface{}) []string {
+	insecure, _ := variables[InsecureSkipVerifyProxyConfigVar].(bool)
+	pacURL, _ := variables[PACURLConfigVar].(string)
+	if !insecure || pacURL == "" || hasValue(variables[ProxyURLConfigVar]) {
+		return nil
+	}
+	return []string{fmt.Sprintf("%s%s: doesn't apply to the proxies chosen by %s, trust the proxy CA with %s instead",
+		prefix, InsecureSkipVerifyProxyConfigVar, PACURLConfigVar, CAFileConfigVar)}
+}
+
+// hasValue tells whether the value of a variable holding a string or a list of strings is set
+func hasValue(value interface{}) bool {
+	switch v := value.(type) {
+	case string:
+		return v != ""
+	case []interface{}:
+		return len(v) > 0
+	default:
+		return false
+	}
+}
+
+// validateVariables returns the problems of the variables of the top level or of a profile
+func validateVariables(prefix string, variables map[string]interface{}, inProfile bool) []string {
+	problems := []string{}
+	for _, name := range sortedKeys(variables) {
+		key, err := LookupKey(name)
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("%s%s: unknown variable", prefix, name))
+			continue
+		}
+		if key.ProfileOnly && !inProfile {
+			problems = append(problems, fmt.Sprintf("%s%s: can only be set in a profile", prefix, name))
+			continue
+		}
+		if err := key.validateValue(variables[name]); err != nil {
+			problems = append(problems, fmt.Sprintf("%s%s: %v", prefix, name, err))
+		}
+	}
+	return problems
+}
+
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,193 @@
+package config
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/openshift/backplane-cli/pkg/info"
+)
+
+func TestKeyParse(t *testing.T) {
+	tests := []struct {
+		key     string
+		value   string
+		want    interface{}
+		wantErr bool
+	}{
+		{key: URLConfigVar, value: "https://api.backplane.example.com", want: "https://api.backplane.example.com"},
+		{key: URLConfigVar, value: "api.backplane.example.com", wantErr: true},
+		{key: ProxyURLConfigVar, value: "ftp://proxy.example.com", wantErr: true},
+		{key: OCMURLConfigVar, value: "staging", want: "staging"},
+		{key: OCMURLConfigVar, value: "stage", wantErr: true},
+		{key: TokenExpiryWarningConfigVar, value: "15m", want: "15m"},
+		{key: TokenExpiryWarningConfigVar, value: "15", wantErr: true},
+		{key: MaxRetriesConfigVar, value: "5", want: 5},
+		{key: MaxRetriesConfigVar, value: "-1", wantErr: true},
+		{key: AssumeInitialArnConfigVar, value: "arn:aws:iam::123456789012:role/backplane", want: "arn:aws:iam::123456789012:role/backplane"},
+		{key: AssumeInitialArnConfigVar, value: "backplane", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		key, err := LookupKey(tt.key)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		got, err := key.Parse(tt.value)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("Parse(%s, %s) error = %v, wantErr %v", tt.key, tt.value, err, tt.wantErr)
+			continue
+		}
+		if !tt.wantErr && got != tt.want {
+			t.Errorf("Parse(%s, %s) = %v, want %v", tt.key, tt.value, got, tt.want)
+		}
+	}
+
+	if _, err := LookupKey("unknown"); err == nil {
+		t.Error("expected an error for an unknown variable")
+	}
+}
+
+func TestValidateConfigContent(t *testing.T) {
+	t.Setenv("BACKPLANE_URL", "")
+
+	t.Run("it accepts a valid configuration", func(t *testing.T) {
+		content := map[string]interface{}{
+			"url":                  "https://api.backplane.example.com",
+			"proxy-url":            "",
+			"max-retries":          float64(3),
+			"token-expiry-warning": "10m",
+			"current-profile":      "stage",
+			"profiles": map[string]interface{}{
+				"stage": map[string]interface{}{"ocm-url": "staging", "url": "https://api.stage.backplane.example.com"},
+			},
+		}
+
+		if problems := validateConfigContent(content); len(problems) != 0 {
+			t.Errorf("expected no problem, got %v", problems)
+		}
+	})
+
+	t.Run("it reports unknown variables, malformed values and missing required values", func(t *testing.T) {
+		content := map[string]interface{}{
+			"proxy-url":       "squid:3128",
+			"max-retries":     "3",
+			"unknown":         true,
+			"ocm-url":         "production",
+			"current-profile": "prod",
+			"profiles": map[string]interface{}{
+				"stage": map[string]interface{}{"url": "not a url", "session": "sessions"},
+			},
+		}
+
+		want := []string{
+			"current-profile: profile prod not found",
+			"max-retries: invalid value \"3\" for max-retries, it must be a number",
+			"ocm-url: can only be set in a profile",
+			"proxy-url: invalid proxy URL squid:3128 for proxy-url: the scheme must be http, https, socks5 or socks5h",
+			"unknown: unknown variable",
+			"profiles.stage.session: unknown variable",
+			"profiles.stage.url: invalid URL not a url for url: the scheme must be http or https",
+			"url: missing required value",
+		}
+		if problems := validateConfigContent(content); !reflect.DeepEqual(problems, want) {
+			t.Errorf("got problems %q, want %q", problems, want)
+		}
+	})
+
+	t.Run("it refuses skipping the TLS verification of the proxies chosen by the PAC file", func(t *testing.T) {
+		content := map[string]interface{}{
+			"url":                        "https://api.backplane.example.com",
+			"pac-url":                    "https://pac.example.com/proxy.pac",
+			"insecure-skip-verify-proxy": true,
+			"profiles": map[string]interface{}{
+				"proxied": map[string]interface{}{"proxy-url": "http://squid.example.com:3128"},
+				"secure":  map[string]interface{}{"insecure-skip-verify-proxy": false},
+				"stage":   map[string]interface{}{"url": "https://api.stage.backplane.example.com"},
+			},
+		}
+
+		want := []string{
+			"insecure-skip-verify-proxy: doesn't apply to the proxies chosen by pac-url, trust the proxy CA with ca-file instead",
+			"profiles.stage.insecure-skip-verify-proxy: doesn't apply to the proxies chosen by pac-url, trust the proxy CA with ca-file instead",
+		}
+		if problems := validateConfigContent(content); !reflect.DeepEqual(problems, want) {
+			t.Errorf("got problems %q, want %q", problems, want)
+		}
+	})
+
+	t.Run("it accepts a url set by every profile or by the selected profile", func(t *testing.T) {
+		everyProfile := map[string]interface{}{
+			"profiles": map[string]interface{}{
+				"prod":  map[string]interface{}{"url": "https://api.backplane.example.com"},
+				"stage": map[string]interface{}{"url": "https://api.stage.backplane.example.com"},
+			},
+		}
+		if problems := validateConfigContent(everyProfile); len(problems) != 0 {
+			t.Errorf("expected no problem, got %v", problems)
+		}
+
+		currentProfile := map[string]interface{}{
+			"current-profile": "prod",
+			"profiles": map[string]interface{}{
+				"prod":  map[string]interface{}{"url": "https://api.backplane.example.com"},
+				"local": map[string]interface{}{"proxy-url": "http://squid.example.com:3128"},
+			},
+		}
+		if problems := validateConfigContent(currentProfile); len(problems) != 0 {
+			t.Errorf("expected no problem, got %v", problems)
+		}
+
+		SetSelectedProfile("prod")
+		t.Cleanup(func() { SetSelectedProfile("") })
+		delete(currentProfile, "current-profile")
+		if problems := validateConfigContent(currentProfile); len(problems) != 0 {
+			t.Errorf("expected no problem, got %v", problems)
+		}
+	})
+
+	t.Run("it reports a url missing from a profile", func(t *testing.T) {
+		content := map[string]interface{}{
+			"profiles": map[string]interface{}{
+				"prod":  map[string]interface{}{"url": "https://api.backplane.example.com"},
+				"local": map[string]interface{}{"proxy-url": "http://squid.example.com:3128"},
+			},
+		}
+
+		want := []string{"url: missing required value"}
+		if problems := validateConfigContent(content); !reflect.DeepEqual(problems, want) {
+			t.Errorf("got problems %q, want %q", problems, want)
+		}
+	})
+
+	t.Run("it accepts a missing url set by the environment", func(t *testing.T) {
+		t.Setenv("BACKPLANE_URL", "https://api.backplane.example.com")
+
+		if problems := validateConfigContent(map[string]interface{}{}); len(problems) != 0 {
+			t.Errorf("expected no problem, got %v", problems)
+		}
+	})
+}
+
+func TestSetValueKeepsOtherVariables(t *testing.T) {
+	configPath := setupProfilesConfig(t, `{"url": "https://api.backplane.example.com", "assume-initial-arn": "arn:aws:iam::123456789012:role/backplane"}`, "")
+
+	if err := SetValue("", ProxyURLConfigVar, "http://proxy.example.com"); err != nil {
+		t.Fatal(err)
+	}
+	if err := UnsetValue("", URLConfigVar); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv(info.BackplaneURLEnvName, "https://env.example.com")
+
+	config, err := GetBackplaneConfiguration()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if config.AssumeInitialArn != "arn:aws:iam::123456789012:role/backplane" || config.ProxyURL != "http://proxy.example.com" {
+		t.Errorf("expected the other variables of %s to be kept, got %+v", configPath, config)
+	}
+	if config.Sources[URLConfigVar] != ValueSourceEnv || config.Sources[ProxyURLConfigVar] != ValueSourceFile || config.Sources[SessionConfigVar] != ValueSourceDefault {
+		t.Errorf("unexpected value sources %v", config.Sources)
+	}
+}
@@ -10,6 +10,9 @@ type GlobalOptions struct {
 	ProxyURL     string
 	Manager      bool
 	Service      bool
+
+	// NonInteractive disables the prompts choosing a cluster
+	NonInteractive bool
 }
 
 func AddGlobalFlags(cmd *cobra.Command, opts *GlobalOptions) {
@@ -0,0 +1,16 @@
+package globalflags
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// AddNonInteractiveFlag add Persistent non-interactive flag. The root command applies it
+// to the cluster lookup once the flags are parsed.
+func AddNonInteractiveFlag(cmd *cobra.Command, opts *GlobalOptions) {
+	cmd.PersistentFlags().BoolVar(
+		&opts.NonInteractive,
+		"non-interactive",
+		false,
+		"Never prompt to choose a cluster. When the cluster key matches several clusters, fail and list them instead.",
+	)
+}
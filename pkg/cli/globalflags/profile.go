@@ -0,0 +1,40 @@
+package globalflags
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/openshift/backplane-cli/pkg/cli/config"
+)
+
+type profileFlag string
+
+var profile profileFlag
+
+// String returns the selected configuration profile
+func (p *profileFlag) String() string {
+	return string(*p)
+}
+
+// Set selects the configuration profile
+func (p *profileFlag) Set(value string) error {
+	if err := config.ValidateProfileName(value); err != nil {
+		return err
+	}
+	*p = profileFlag(value)
+	config.SetSelectedProfile(value)
+	return nil
+}
+
+// Type defines the configuration profile type
+func (p *profileFlag) Type() string {
+	return "string"
+}
+
+// AddProfileFlag add Persistent profile flag
+func AddProfileFlag(cmd *cobra.Command) {
+	cmd.PersistentFlags().Var(
+		&profile,
+		"profile",
+		"Name of the configuration profile to use, instead of the one set by \"config use-profile\" or matching the URL of the ocm session.",
+	)
+}
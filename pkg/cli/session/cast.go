@@ -0,0 +1,169 @@
+package session
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	// recordingsDirName is the directory of the session recordings, in the session directory
+	recordingsDirName = "recordings"
+
+	// recordingSuffix is the extension of the asciinema v2 recordings
+	recordingSuffix = ".cast"
+)
+
+// sleep waits between the replayed events, for mocking
+var sleep = time.Sleep
+
+// castHeader is the first line of an asciinema v2 recording
+type castHeader struct {
+	Version   int               `json:"version"`
+	Width     int               `json:"width"`
+	Height    int               `json:"height"`
+	Timestamp int64             `json:"timestamp"`
+	Title     string            `json:"title,omitempty"`
+	Env       map[string]string `json:"env,omitempty"`
+}
+
+// castWriter records what is written to it as the output events of an asciinema v2 recording
+type castWriter struct {
+	mu    sync.Mutex
+	w     io.Writer
+	start time.Time
+	// pending is an incomplete UTF-8 sequence at the end of the last write, kept for the next one
+	pending []byte
+}
+
+// newCastWriter writes the header of the recording and returns the writer of its events
+func newCastWriter(w io.Writer, header castHeader) (*castWriter, error) {
+	header.Version = 2
+	content, err := json.Marshal(header)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := fmt.Fprintf(w, "%s\n", content); err != nil {
+		return nil, err
+	}
+	return &castWriter{w: w, start: time.Now()}, nil
+}
+
+// Write records the output with the time elapsed since the recording started
+func (c *castWriter) Write(p []byte) (int, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	data, pending := splitIncompleteRune(append(c.pending, p...))
+	c.pending = append([]byte{}, pending...)
+	if len(data) == 0 {
+		return len(p), nil
+	}
+
+	text, err := json.Marshal(string(data))
+	if err != nil {
+		return 0, err
+	}
+	if _, err := fmt.Fprintf(c.w, "[%.6f, \"o\", %s]\n", time.Since(c.start).Seconds(), text); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// splitIncompleteRune splits the data before the UTF-8 sequence cut at its end, if any
+func splitIncompleteRune(data []byte) ([]byte, []byte) {
+	for i := len(data) - 1; i >= 0 && i >= len(data)-utf8.UTFMax; i-- {
+		if utf8.RuneStart(data[i]) {
+			if !utf8.FullRune(data[i:]) {
+				return data[:i], data[i:]
+			}
+			break
+		}
+	}
+	return data, nil
+}
+
+// recordingsPath returns the directory of the session recordings
+func recordingsPath(sessionPath string) string {
+	return filepath.Join(sessionPath, recordingsDirName)
+}
+
+// ListRecordings returns the paths of the recordings of the session, the oldest first
+func ListRecordings(session Info) ([]string, error) {
+	recordings, err := filepath.Glob(filepath.Join(recordingsPath(session.Path), "*"+recordingSuffix))
+	if err != nil {
+		return nil, err
+	}
+	// The recordings are named after the time they started
+	sort.Strings(recordings)
+	return recordings, nil
+}
+
+// Replay plays the asciinema v2 recording back to the writer, faster with a speed above 1.
+// The pauses are capped to the idle limit when it is positive.
+func Replay(path string, w io.Writer, speed float64, idleLimit time.Duration) error {
+	if speed <= 0 {
+		return fmt.Errorf("invalid speed %v, it must be positive", speed)
+	}
+
+	file, err := os.Open(filepath.Clean(path))
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	// An event holds up to a read of the terminal output
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+
+	if !scanner.Scan() {
+		return fmt.Errorf("empty recording %s", path)
+	}
+	header := castHeader{}
+	if err := json.Unmarshal(scanner.Bytes(), &header); err != nil || header.Version != 2 {
+		return fmt.Errorf("%s isn't an asciinema v2 recording", path)
+	}
+
+	last := 0.0
+	for line := 1; scanner.Scan(); line++ {
+		if strings.TrimSpace(scanner.Text()) == "" {
+			continue
+		}
+
+		var event []interface{}
+		if err := json.Unmarshal(scanner.Bytes(), &event); err != nil || len(event) != 3 {
+			return fmt.Errorf("invalid event at line %d of %s", line+1, path)
+		}
+		at, okTime := event[0].(float64)
+		kind, okKind := event[1].(string)
+		data, okData := event[2].(string)
+		if !okTime || !okKind || !okData {
+			return fmt.Errorf("invalid event at line %d of %s", line+1, path)
+		}
+		if kind != "o" {
+			continue
+		}
+
+		pause := time.Duration((at - last) / speed * float64(time.Second))
+		if idleLimit > 0 && pause > idleLimit {
+			pause = idleLimit
+		}
+		if pause > 0 {
+			sleep(pause)
+		}
+		last = at
+
+		if _, err := io.WriteString(w, data); err != nil {
+			return err
+		}
+	}
+	return scanner.Err()
+}
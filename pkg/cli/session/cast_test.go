@@ -0,0 +1,127 @@
+package session
+
+import (
+	"bytes"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/openshift/backplane-cli/pkg/cli/globalflags"
+)
+
+var _ = Describe("Backplane Session recording", func() {
+	var (
+		sessionDir string
+		bpSession  BackplaneSession
+		pauses     []time.Duration
+	)
+
+	BeforeEach(func() {
+		var err error
+		sessionDir, err = os.MkdirTemp("", "bp-session")
+		Expect(err).To(BeNil())
+
+		bpSession = BackplaneSession{
+			Path: sessionDir,
+			Options: &Options{
+				Alias:      "my-session",
+				ClusterID:  "cluster-id",
+				GlobalOpts: &globalflags.GlobalOptions{},
+			},
+		}
+
+		pauses = nil
+		sleep = func(d time.Duration) {
+			pauses = append(pauses, d)
+		}
+	})
+
+	AfterEach(func() {
+		sleep = time.Sleep
+		os.RemoveAll(sessionDir)
+	})
+
+	writeRecording := func(content string) string {
+		dir := recordingsPath(sessionDir)
+		Expect(os.MkdirAll(dir, 0700)).To(Succeed())
+		path := filepath.Join(dir, "20261017T100000Z.cast")
+		Expect(os.WriteFile(path, []byte(content), 0600)).To(Succeed())
+		return path
+	}
+
+	It("should record the output as asciinema v2 events", func() {
+		recording := &bytes.Buffer{}
+		cast, err := newCastWriter(recording, castHeader{Width: 120, Height: 40, Timestamp: 1})
+		Expect(err).To(BeNil())
+
+		// A multi-byte character split between two writes is kept whole
+		euro := []byte("€")
+		_, err = cast.Write(append([]byte("price: "), euro[:1]...))
+		Expect(err).To(BeNil())
+		_, err = cast.Write(append(euro[1:], []byte("\r\n")...))
+		Expect(err).To(BeNil())
+
+		lines := strings.Split(strings.TrimSpace(recording.String()), "\n")
+		Expect(lines).To(HaveLen(3))
+		Expect(lines[0]).To(Equal(`{"version":2,"width":120,"height":40,"timestamp":1}`))
+		Expect(lines[1]).To(MatchRegexp(`^\[[0-9.]+, "o", "price: "\]$`))
+		Expect(lines[2]).To(MatchRegexp(`^\[[0-9.]+, "o", "€\\r\\n"\]$`))
+
+		output := &bytes.Buffer{}
+		Expect(Replay(writeRecording(recording.String()), output, 1, 0)).To(Succeed())
+		Expect(output.String()).To(Equal("price: €\r\n"))
+	})
+
+	It("should replay the events with their pauses, at the given speed and idle limit", func() {
+		path := writeRecording(`{"version": 2, "width": 80, "height": 24}
+[0.5, "o", "$ "]
+[1.5, "i", "l"]
+[2.5, "o", "ls\r\n"]
+[62.5, "o", "bin\r\n"]
+`)
+
+		output := &bytes.Buffer{}
+		Expect(Replay(path, output, 2, 5*time.Second)).To(Succeed())
+		Expect(output.String()).To(Equal("$ ls\r\nbin\r\n"))
+		Expect(pauses).To(Equal([]time.Duration{250 * time.Millisecond, time.Second, 5 * time.Second}))
+	})
+
+	It("should reject the files which aren't asciinema v2 recordings", func() {
+		output := &bytes.Buffer{}
+		Expect(Replay(writeRecording("Script started on 2026-10-17\n"), output, 1, 0)).NotTo(Succeed())
+		Expect(Replay(writeRecording(`{"version": 1}`), output, 1, 0)).NotTo(Succeed())
+		Expect(Replay(writeRecording("{\"version\": 2}\n[\"o\"]\n"), output, 1, 0)).NotTo(Succeed())
+	})
+
+	It("should list the recordings of the session, the oldest first", func() {
+		dir := recordingsPath(sessionDir)
+		Expect(os.MkdirAll(dir, 0700)).To(Succeed())
+		for _, name := range []string{"20261017T100000Z.cast", "20261016T100000Z.cast", "notes.txt"} {
+			Expect(os.WriteFile(filepath.Join(dir, name), nil, 0600)).To(Succeed())
+		}
+
+		recordings, err := ListRecordings(Info{Path: sessionDir})
+		Expect(err).To(BeNil())
+		Expect(recordings).To(Equal([]string{
+			filepath.Join(dir, "20261016T100000Z.cast"),
+			filepath.Join(dir, "20261017T100000Z.cast"),
+		}))
+	})
+
+	It("should record a shell run under a pseudo-terminal", func() {
+		cmd := exec.Command("/bin/sh", "-c", "tty >/dev/null && echo recorded")
+
+		recording, err := bpSession.runRecorded(cmd)
+		Expect(err).To(BeNil())
+		Expect(filepath.Dir(recording)).To(Equal(recordingsPath(sessionDir)))
+
+		output := &bytes.Buffer{}
+		Expect(Replay(recording, output, 1, 0)).To(Succeed())
+		Expect(output.String()).To(ContainSubstring("recorded"))
+	})
+})
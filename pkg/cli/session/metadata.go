@@ -0,0 +1,223 @@
+package session
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	logger "github.com/sirupsen/logrus"
+
+	"github.com/openshift/backplane-cli/pkg/cli/config"
+	"github.com/openshift/backplane-cli/pkg/info"
+)
+
+// MetadataFileName is the file describing the session, in the session directory
+const MetadataFileName = ".session.json"
+
+// Metadata describes a backplane session, it is written to the session directory by Setup
+type Metadata struct {
+	Alias       string    `json:"alias"`
+	ClusterID   string    `json:"cluster_id,omitempty"`
+	ClusterName string    `json:"cluster_name,omitempty"`
+	CreatedAt   time.Time `json:"created_at"`
+	LastUsedAt  time.Time `json:"last_used_at"`
+}
+
+// Info is a backplane session found in the session directory
+type Info struct {
+	Metadata
+	Path string `json:"path"`
+	// HistorySize is the number of commands in the shell history of the session
+	HistorySize int `json:"history_size"`
+}
+
+// GetSessionsDirectory returns the directory holding the backplane sessions, one per alias
+func GetSessionsDirectory() (string, error) {
+	bpConfig, err := config.GetBackplaneConfiguration()
+	if err != nil {
+		return "", err
+	}
+	sessionDir := info.BackplaneDefaultSessionDirectory
+
+	// Get the session directory name via config
+	if bpConfig.SessionDirectory != "" {
+		sessionDir = bpConfig.SessionDirectory
+	}
+
+	userHomeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(userHomeDir, sessionDir), nil
+}
+
+// ListSessions returns the sessions of the session directory, the most recently used first
+func ListSessions() ([]Info, error) {
+	sessionsDir, err := GetSessionsDirectory()
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := os.ReadDir(sessionsDir)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	sessions := []Info{}
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		session, err := readSession(filepath.Join(sessionsDir, entry.Name()))
+		if err != nil {
+			logger.Debugf("Skipping %s: %v", entry.Name(), err)
+			continue
+		}
+		sessions = append(sessions, session)
+	}
+
+	sort.SliceStable(sessions, func(i, j int) bool {
+		return sessions[i].LastUsedAt.After(sessions[j].LastUsedAt)
+	})
+	return sessions, nil
+}
+
+// GetSession returns the session of the alias
+func GetSession(alias string) (Info, error) {
+	if alias == "" || filepath.Base(alias) != alias || alias == ".." {
+		return Info{}, fmt.Errorf("invalid session alias %s", alias)
+	}
+
+	sessionsDir, err := GetSessionsDirectory()
+	if err != nil {
+		return Info{}, err
+	}
+
+	session, err := readSession(filepath.Join(sessionsDir, alias))
+	if err != nil {
+		return Info{}, fmt.Errorf("session %s not found", alias)
+	}
+	return session, nil
+}
+
+// RemoveSession deletes the session directory
+func RemoveSession(session Info) error {
+	return os.RemoveAll(session.Path)
+}
+
+// writeMetadata saves the metadata of the session in its directory, as used now
+func (e *BackplaneSession) writeMetadata(createdAt time.Time) error {
+	metadata := Metadata{
+		Alias:       e.Options.Alias,
+		ClusterID:   e.Options.ClusterID,
+		ClusterName: e.Options.ClusterName,
+		CreatedAt:   createdAt,
+		LastUsedAt:  time.Now(),
+	}
+
+	content, err := json.MarshalIndent(metadata, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(e.Path, MetadataFileName), append(content, '\n'), 0600)
+}
+
+// readSession returns the session of the directory. The sessions created before the metadata file
+// was introduced are described from their environment file and its modification time.
+func readSession(path string) (Info, error) {
+	session := Info{Path: path}
+
+	content, err := os.ReadFile(filepath.Clean(filepath.Join(path, MetadataFileName)))
+	switch {
+	case err == nil:
+		if err := json.Unmarshal(content, &session.Metadata); err != nil {
+			return Info{}, fmt.Errorf("invalid session metadata: %v", err)
+		}
+	case errors.Is(err, os.ErrNotExist):
+		if err := readLegacySession(path, &session.Metadata); err != nil {
+			return Info{}, err
+		}
+	default:
+		return Info{}, err
+	}
+
+	if session.Alias == "" {
+		session.Alias = filepath.Base(path)
+	}
+
+	// The shell writes the history when the session exits, which tells when it was last used
+	history, err := os.Stat(filepath.Join(path, ".history"))
+	if err == nil {
+		if history.ModTime().After(session.LastUsedAt) {
+			session.LastUsedAt = history.ModTime()
+		}
+		session.HistorySize = countCommands(filepath.Join(path, ".history"))
+	}
+
+	return session, nil
+}
+
+// readLegacySession fills the metadata of a session without metadata file
+func readLegacySession(path string, metadata *Metadata) error {
+	envFile := filepath.Join(path, ".ocenv")
+	stat, err := os.Stat(envFile)
+	if err != nil {
+		return fmt.Errorf("not a backplane session")
+	}
+
+	metadata.CreatedAt = stat.ModTime()
+	metadata.LastUsedAt = stat.ModTime()
+
+	content, err := os.ReadFile(filepath.Clean(envFile))
+	if err != nil {
+		return err
+	}
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for scanner.Scan() {
+		name, value, found := strings.Cut(scanner.Text(), "=")
+		if !found {
+			continue
+		}
+		switch name {
+		case "CLUSTERID":
+			metadata.ClusterID = value
+		case "CLUSTERNAME":
+			metadata.ClusterName = value
+		}
+	}
+	return nil
+}
+
+// bashTimestampRegexp matches the timestamp lines bash writes before the commands with HISTTIMEFORMAT
+var bashTimestampRegexp = regexp.MustCompile(`^#[0-9]+$`)
+
+// countCommands returns the number of commands of the history file, 0 when it can't be read
+func countCommands(path string) int {
+	file, err := os.Open(filepath.Clean(path))
+	if err != nil {
+		return 0
+	}
+	defer file.Close()
+
+	commands := 0
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line != "" && !bashTimestampRegexp.MatchString(line) {
+			commands++
+		}
+	}
+	return commands
+}
@@ -0,0 +1,119 @@
+package session
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/openshift/backplane-cli/pkg/cli/globalflags"
+	"github.com/openshift/backplane-cli/pkg/info"
+)
+
+var _ = Describe("Backplane Session metadata", func() {
+	var (
+		homeDir      string
+		sessionsDir  string
+		originalHome string
+	)
+
+	BeforeEach(func() {
+		var err error
+		homeDir, err = os.MkdirTemp("", "bp-home")
+		Expect(err).To(BeNil())
+		originalHome = os.Getenv("HOME")
+		os.Setenv("HOME", homeDir)
+		os.Setenv(info.BackplaneConfigPathEnvName, filepath.Join(homeDir, "config.json"))
+		sessionsDir = filepath.Join(homeDir, info.BackplaneDefaultSessionDirectory)
+	})
+
+	AfterEach(func() {
+		os.Setenv("HOME", originalHome)
+		os.Unsetenv(info.BackplaneConfigPathEnvName)
+		os.RemoveAll(homeDir)
+	})
+
+	writeSession := func(alias string, metadata *Metadata, history string) string {
+		path := filepath.Join(sessionsDir, alias)
+		Expect(os.MkdirAll(path, 0750)).To(Succeed())
+		Expect(os.WriteFile(filepath.Join(path, ".ocenv"), []byte("CLUSTERID=legacy-id\nCLUSTERNAME=legacy-name\n"), 0600)).To(Succeed())
+		if metadata != nil {
+			content, err := json.Marshal(metadata)
+			Expect(err).To(BeNil())
+			Expect(os.WriteFile(filepath.Join(path, MetadataFileName), content, 0600)).To(Succeed())
+		}
+		if history != "" {
+			Expect(os.WriteFile(filepath.Join(path, ".history"), []byte(history), 0600)).To(Succeed())
+		}
+		return path
+	}
+
+	It("should write the session metadata on setup", func() {
+		bpSession := BackplaneSession{
+			Options: &Options{
+				Alias:       "my-session",
+				ClusterID:   "cluster-id",
+				ClusterName: "cluster-name",
+				GlobalOpts:  &globalflags.GlobalOptions{},
+			},
+		}
+		Expect(bpSession.initSessionPath()).To(Succeed())
+		Expect(bpSession.Path).To(Equal(filepath.Join(sessionsDir, "my-session")))
+		Expect(bpSession.Setup()).To(Succeed())
+
+		session, err := GetSession("my-session")
+		Expect(err).To(BeNil())
+		Expect(session.Alias).To(Equal("my-session"))
+		Expect(session.ClusterID).To(Equal("cluster-id"))
+		Expect(session.ClusterName).To(Equal("cluster-name"))
+		Expect(session.CreatedAt).To(BeTemporally("~", time.Now(), time.Minute))
+		Expect(session.HistorySize).To(Equal(0))
+	})
+
+	It("should list the sessions the most recently used first", func() {
+		old := time.Now().Add(-48 * time.Hour).UTC()
+		writeSession("old", &Metadata{Alias: "old", ClusterID: "old-id", CreatedAt: old, LastUsedAt: old}, "")
+		recentPath := writeSession("recent", &Metadata{Alias: "recent", ClusterID: "recent-id", CreatedAt: old, LastUsedAt: old}, "#1700000000\noc get pods\n\noc get nodes\n")
+		legacyPath := writeSession("legacy", nil, "")
+		Expect(os.Chtimes(filepath.Join(legacyPath, ".ocenv"), old.Add(-time.Hour), old.Add(-time.Hour))).To(Succeed())
+		Expect(os.MkdirAll(filepath.Join(sessionsDir, "not-a-session"), 0750)).To(Succeed())
+
+		sessions, err := ListSessions()
+		Expect(err).To(BeNil())
+		Expect(sessions).To(HaveLen(3))
+
+		// The history written when the session exits tells it was used recently
+		Expect(sessions[0].Alias).To(Equal("recent"))
+		Expect(sessions[0].Path).To(Equal(recentPath))
+		Expect(sessions[0].HistorySize).To(Equal(2))
+		Expect(sessions[0].LastUsedAt).To(BeTemporally("~", time.Now(), time.Minute))
+
+		Expect(sessions[1].Alias).To(Equal("old"))
+		Expect(sessions[1].LastUsedAt).To(BeTemporally("==", old))
+
+		// Sessions without metadata are described from their environment file
+		Expect(sessions[2].Alias).To(Equal("legacy"))
+		Expect(sessions[2].ClusterID).To(Equal("legacy-id"))
+		Expect(sessions[2].ClusterName).To(Equal("legacy-name"))
+	})
+
+	It("should remove a session", func() {
+		writeSession("my-session", nil, "")
+
+		session, err := GetSession("my-session")
+		Expect(err).To(BeNil())
+		Expect(RemoveSession(session)).To(Succeed())
+
+		_, err = GetSession("my-session")
+		Expect(err).NotTo(BeNil())
+	})
+
+	It("should refuse an alias outside the session directory", func() {
+		_, err := GetSession("../my-session")
+		Expect(err).NotTo(BeNil())
+		Expect(err.Error()).To(ContainSubstring("invalid session alias"))
+	})
+})
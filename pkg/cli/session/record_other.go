@@ -0,0 +1,14 @@
+//go:build !linux && !darwin
+
+package session
+
+import (
+	"fmt"
+	"os/exec"
+	"runtime"
+)
+
+// runRecorded isn't supported without pseudo-terminals
+func (e *BackplaneSession) runRecorded(cmd *exec.Cmd) (string, error) {
+	return "", fmt.Errorf("session recording isn't supported on %s", runtime.GOOS)
+}
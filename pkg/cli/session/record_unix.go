@@ -0,0 +1,209 @@
+//go:build linux || darwin
+
+package session
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"os/signal"
+	"path/filepath"
+	"syscall"
+	"time"
+
+	"golang.org/x/sys/unix"
+	"golang.org/x/term"
+)
+
+// endOfTransmission is ^D, the end of file character of a pseudo-terminal
+const endOfTransmission = 0x04
+
+// runRecorded runs the session shell under a pseudo-terminal, copying its output to the terminal
+// and to a new recording in the session directory. It returns the path of the recording.
+func (e *BackplaneSession) runRecorded(cmd *exec.Cmd) (string, error) {
+	recordingsDir := recordingsPath(e.Path)
+	if err := os.MkdirAll(recordingsDir, 0700); err != nil {
+		return "", err
+	}
+	// The recordings are named after the time they started, which sorts them
+	recording := filepath.Join(recordingsDir, time.Now().UTC().Format("20060102T150405Z")+recordingSuffix)
+	castFile, err := os.OpenFile(recording, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+	if err != nil {
+		return "", err
+	}
+	defer castFile.Close()
+
+	master, slave, err := openPTY()
+	if err != nil {
+		return "", fmt.Errorf("error opening a pseudo-terminal: %v", err)
+	}
+	defer master.Close()
+
+	stdin := int(os.Stdin.Fd())
+	width, height := 80, 24
+	if term.IsTerminal(stdin) {
+		if size, err := unix.IoctlGetWinsize(stdin, unix.TIOCGWINSZ); err == nil {
+			width, height = int(size.Col), int(size.Row)
+		}
+		resizePTY(master)
+
+		// Follow the size of the terminal
+		resized := make(chan os.Signal, 1)
+		signal.Notify(resized, syscall.SIGWINCH)
+		defer func() {
+			signal.Stop(resized)
+			close(resized)
+		}()
+		go func() {
+			for range resized {
+				resizePTY(master)
+			}
+		}()
+	}
+
+	cast, err := newCastWriter(castFile, castHeader{
+		Width:     width,
+		Height:    height,
+		Timestamp: time.Now().Unix(),
+		Title:     fmt.Sprintf("Backplane session %s (%s)", e.Options.Alias, e.Options.ClusterID),
+		Env: map[string]string{
+			"SHELL": os.Getenv("SHELL"),
+			"TERM":  os.Getenv("TERM"),
+		},
+	})
+	if err != nil {
+		return "", err
+	}
+
+	// The shell leads a new session whose controlling terminal is the pseudo-terminal
+	cmd.Stdin = slave
+	cmd.Stdout = slave
+	cmd.Stderr = slave
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setsid: true, Setctty: true}
+	err = cmd.Start()
+	slave.Close()
+	if err != nil {
+		return "", err
+	}
+
+	// The keys are sent to the shell as they are typed, it echoes them itself
+	if term.IsTerminal(stdin) {
+		state, err := term.MakeRaw(stdin)
+		if err == nil {
+			defer func() {
+				_ = term.Restore(stdin, state)
+			}()
+		}
+	}
+
+	// The input is forwarded until the shell exits, leaving the next keys to the terminal
+	stop, stopInput, err := os.Pipe()
+	if err != nil {
+		return "", err
+	}
+	defer stop.Close()
+	forwarded := make(chan struct{})
+	go func() {
+		forwardInput(stdin, master, stop)
+		close(forwarded)
+	}()
+	copied := make(chan struct{})
+	go func() {
+		// The read fails once the shell and its children closed the pseudo-terminal
+		_, _ = io.Copy(io.MultiWriter(os.Stdout, cast), master)
+		close(copied)
+	}()
+
+	err = cmd.Wait()
+
+	// Background jobs left by the shell can keep the pseudo-terminal open
+	select {
+	case <-copied:
+	case <-time.After(time.Second):
+		_ = master.SetReadDeadline(time.Now())
+		<-copied
+	}
+
+	stopInput.Close()
+	_ = master.SetWriteDeadline(time.Now())
+	<-forwarded
+
+	return recording, err
+}
+
+// forwardInput copies the input to the pseudo-terminal until stop is readable, which closing its
+// write end does. The input is only read once select reports it readable, so no key typed after
+// the shell exited is swallowed. The end of a piped input is sent as ^D, the end of file character
+// of the pseudo-terminal.
+func forwardInput(input int, master io.Writer, stop *os.File) {
+	stopFd := int(stop.Fd())
+	nfd := max(input, stopFd) + 1
+	buf := make([]byte, 4096)
+	lastByte := byte('\n')
+	for {
+		fds := &unix.FdSet{}
+		fds.Set(input)
+		fds.Set(stopFd)
+		if _, err := unix.Select(nfd, fds, nil, nil, nil); err != nil {
+			if err == unix.EINTR {
+				continue
+			}
+			return
+		}
+		if fds.IsSet(stopFd) {
+			return
+		}
+
+		n, err := unix.Read(input, buf)
+		if err == unix.EINTR || err == unix.EAGAIN {
+			continue
+		}
+		if err != nil {
+			return
+		}
+		if n > 0 {
+			if _, err := master.Write(buf[:n]); err != nil {
+				return
+			}
+			lastByte = buf[n-1]
+			continue
+		}
+
+		// ^D only sends a line in progress, a second one ends the input
+		eof := []byte{endOfTransmission}
+		if lastByte != '\n' {
+			eof = append(eof, endOfTransmission)
+		}
+		_, _ = master.Write(eof)
+		return
+	}
+}
+
+// resizePTY gives the pseudo-terminal the size of the terminal
+func resizePTY(master *os.File) {
+	size, err := unix.IoctlGetWinsize(int(os.Stdin.Fd()), unix.TIOCGWINSZ)
+	if err != nil {
+		return
+	}
+	_ = ptyControl(master, func(fd int) error {
+		return unix.IoctlSetWinsize(fd, unix.TIOCSWINSZ, size)
+	})
+}
+
+// ptyControl runs fn with the descriptor of the pseudo-terminal master. Unlike Fd, it leaves the
+// descriptor non-blocking, which the read deadline ending the copy of the output relies on.
+func ptyControl(master *os.File, fn func(fd int) error) error {
+	conn, err := master.SyscallConn()
+	if err != nil {
+		return err
+	}
+	var fnErr error
+	err = conn.Control(func(fd uintptr) {
+		fnErr = fn(int(fd))
+	})
+	if err != nil {
+		return err
+	}
+	return fnErr
+}
@@ -0,0 +1,100 @@
+//go:build linux || darwin
+
+package session
+
+import (
+	"bytes"
+	"os"
+	"os/exec"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/openshift/backplane-cli/pkg/cli/globalflags"
+)
+
+var _ = Describe("Backplane Session recording input", func() {
+	var (
+		sessionDir string
+		bpSession  BackplaneSession
+	)
+
+	BeforeEach(func() {
+		var err error
+		sessionDir, err = os.MkdirTemp("", "bp-session")
+		Expect(err).To(BeNil())
+
+		bpSession = BackplaneSession{
+			Path: sessionDir,
+			Options: &Options{
+				Alias:      "my-session",
+				ClusterID:  "cluster-id",
+				GlobalOpts: &globalflags.GlobalOptions{},
+			},
+		}
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(sessionDir)
+	})
+
+	It("should stop recording a shell leaving a background job behind", func() {
+		// The job ignores the hangup of the terminal and keeps the pseudo-terminal open
+		cmd := exec.Command("/bin/sh", "-c", `(trap "" HUP; sleep 10) & echo recorded`)
+
+		done := make(chan error, 1)
+		go func() {
+			_, err := bpSession.runRecorded(cmd)
+			done <- err
+		}()
+		Eventually(done, 5*time.Second).Should(Receive(BeNil()))
+	})
+
+	It("should end the input of the shell at the end of a piped input", func() {
+		input, inputWriter, err := os.Pipe()
+		Expect(err).To(BeNil())
+		defer input.Close()
+		_, err = inputWriter.WriteString("piped")
+		Expect(err).To(BeNil())
+		inputWriter.Close()
+
+		stdin := os.Stdin
+		os.Stdin = input
+		defer func() { os.Stdin = stdin }()
+
+		done := make(chan error, 1)
+		go func() {
+			_, err := bpSession.runRecorded(exec.Command("cat"))
+			done <- err
+		}()
+		Eventually(done, 5*time.Second).Should(Receive(BeNil()))
+	})
+
+	It("should leave the input unread once stopped", func() {
+		input, inputWriter, err := os.Pipe()
+		Expect(err).To(BeNil())
+		defer input.Close()
+		defer inputWriter.Close()
+		stop, stopWriter, err := os.Pipe()
+		Expect(err).To(BeNil())
+		defer stop.Close()
+
+		forwarded := &bytes.Buffer{}
+		done := make(chan struct{})
+		go func() {
+			forwardInput(int(input.Fd()), forwarded, stop)
+			close(done)
+		}()
+		stopWriter.Close()
+		Eventually(done, 5*time.Second).Should(BeClosed())
+
+		_, err = inputWriter.WriteString("next")
+		Expect(err).To(BeNil())
+		buf := make([]byte, 4)
+		_, err = input.Read(buf)
+		Expect(err).To(BeNil())
+		Expect(string(buf)).To(Equal("next"))
+		Expect(forwarded.Len()).To(Equal(0))
+	})
+})
@@ -4,18 +4,19 @@ import (
 	"bufio"
 	"errors"
 	"fmt"
+	"io"
 	"log"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"strings"
+	"time"
 
+	logger "github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 
 	"github.com/openshift/backplane-cli/cmd/ocm-backplane/login"
-	"github.com/openshift/backplane-cli/pkg/cli/config"
 	"github.com/openshift/backplane-cli/pkg/cli/globalflags"
-	"github.com/openshift/backplane-cli/pkg/info"
 	"github.com/openshift/backplane-cli/pkg/utils"
 )
 
@@ -38,6 +39,15 @@ type BackplaneSession struct {
 type Options struct {
 	DeleteSession bool
 
+	// Fresh recreates the session instead of resuming it
+	Fresh bool
+
+	// Command is run in the session environment instead of the interactive shell
+	Command []string
+
+	// Record runs the shell under a pseudo-terminal and records its output in the session directory
+	Record bool
+
 	Alias string
 
 	ClusterID   string
@@ -50,8 +60,24 @@ var (
 	DefaultBackplaneSession BackplaneSessionInterface = &BackplaneSession{}
 )
 
+// CommandExitError is returned when the command run in the session exits with a non zero status,
+// which backplane exits with
+type CommandExitError struct {
+	Command string
+	Code    int
+}
+
+func (e *CommandExitError) Error() string {
+	return fmt.Sprintf("%s exited with status %d", e.Command, e.Code)
+}
+
 // RunCommand setup session and allows to execute commands
 func (e *BackplaneSession) RunCommand(cmd *cobra.Command, args []string) error {
+	// The arguments after -- are the command to run in the session
+	if dash := cmd.ArgsLenAtDash(); dash >= 0 {
+		e.Options.Command = args[dash:]
+		args = args[:dash]
+	}
 	if len(args) > 0 {
 		e.Options.Alias = args[0]
 	}
@@ -83,7 +109,7 @@ func (e *BackplaneSession) RunCommand(cmd *cobra.Command, args []string) error {
 			return err
 		}
 
-		fmt.Printf("Switching to management cluster ID: %v, Name: %v\n", clusterID, clusterName)
+		fmt.Fprintf(e.messages(), "Switching to management cluster ID: %v, Name: %v\n", clusterID, clusterName)
 	}
 
 	if e.Options.GlobalOpts.Service {
@@ -93,7 +119,7 @@ func (e *BackplaneSession) RunCommand(cmd *cobra.Command, args []string) error {
 			return err
 		}
 
-		fmt.Printf("Switching to service cluster ID: %v, Name: %v\n", clusterID, clusterName)
+		fmt.Fprintf(e.messages(), "Switching to service cluster ID: %v, Name: %v\n", clusterID, clusterName)
 	}
 
 	// set cluster options
@@ -106,7 +132,7 @@ func (e *BackplaneSession) RunCommand(cmd *cobra.Command, args []string) error {
 	}
 
 	if e.Options.DeleteSession {
-		fmt.Printf("Cleaning up Backplane session %s\n", e.Options.Alias)
+		fmt.Fprintf(e.messages(), "Cleaning up Backplane session %s\n", e.Options.Alias)
 		err = e.Delete()
 		if err != nil {
 			return fmt.Errorf("could not delete the session. error: %v", err)
@@ -132,12 +158,29 @@ func (e *BackplaneSession) RunCommand(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
-// Setup initialize the session environment
+// Setup initialize the session environment. An existing session is resumed unless the fresh option is set,
+// keeping its history and files: its environment file, metadata and bins are refreshed.
 func (e *BackplaneSession) Setup() error {
-	// Delete session if exist
-	err := e.Delete()
-	if err != nil {
-		return fmt.Errorf("error deleting session. error: %v", err)
+	_, err := os.Stat(e.Path)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("error reading session %s. error: %v", e.Options.Alias, err)
+	}
+	resume := err == nil && !e.Options.Fresh
+
+	previous := Info{}
+	if resume {
+		// A session whose metadata can't be read keeps its files, only its metadata is recreated
+		previous, err = readSession(e.Path)
+		if err != nil {
+			logger.Warnf("Unable to read the metadata of session %s, recreating it: %v", e.Options.Alias, err)
+			previous = Info{}
+		}
+	} else {
+		// Delete session if exist
+		err = e.Delete()
+		if err != nil {
+			return fmt.Errorf("error deleting session. error: %v", err)
+		}
 	}
 
 	err = e.ensureEnvDir()
@@ -145,9 +188,29 @@ func (e *BackplaneSession) Setup() error {
 		return fmt.Errorf("error validating env directory. error: %v", err)
 	}
 
-	e.printSessionHeader()
+	createdAt := time.Now()
+	if !previous.CreatedAt.IsZero() {
+		createdAt = previous.CreatedAt
+	}
+	err = e.writeMetadata(createdAt)
+	if err != nil {
+		return fmt.Errorf("error writing session metadata. error: %v", err)
+	}
+
+	// The output of a command run in the session is kept clean for scripts
+	if len(e.Options.Command) == 0 {
+		e.printSessionHeader()
+	}
+	if resume {
+		if previous.LastUsedAt.IsZero() {
+			fmt.Fprintf(e.messages(), "Resuming Backplane session %s\n", e.Options.Alias)
+		} else {
+			fmt.Fprintf(e.messages(), "Resuming Backplane session %s, last used %s\n", e.Options.Alias, previous.LastUsedAt.Local().Format(time.RFC3339))
+		}
+	}
 
-	// Create session Bins
+	// Create session Bins, rewritten on every setup as they target the session cluster and follow the
+	// template. The other files of the bin directory are the user's own and are left as they are.
 	err = e.createBins()
 	if err != nil {
 		return fmt.Errorf("error creating bins. error: %v", err)
@@ -168,44 +231,144 @@ func (e *BackplaneSession) Setup() error {
 	return nil
 }
 
-// Start trigger the session start
+// Start trigger the session start. The command of the options is run in the session environment
+// instead of the shell when set, its exit status is returned as a CommandExitError.
 func (e *BackplaneSession) Start() error {
+	if len(e.Options.Command) > 0 {
+		return e.runCommand()
+	}
+
 	shell := os.Getenv("SHELL")
 
 	if shell != "" {
 		fmt.Print("Switching to Backplane session " + e.Options.Alias + "\n")
-		cmd := exec.Command(shell)
+		cmd, shellEnv := e.shellCommand(shell)
 
-		path := filepath.Clean(e.Path + "/.ocenv")
-		file, err := os.Open(path)
+		env, err := e.environment()
 		if err != nil {
 			return err
 		}
-		defer func() {
-			if err := file.Close(); err != nil {
-				fmt.Println("Error closing file: ", path)
-				return
+		cmd.Env = append(env, shellEnv...)
+		cmd.Dir = e.Path
+		if e.Options.Record {
+			recording, err := e.runRecorded(cmd)
+			if recording != "" {
+				fmt.Printf("Session recorded to %s\n", recording)
+			}
+			if err != nil {
+				return fmt.Errorf("error while recording cmd. %v", err)
+			}
+		} else {
+			cmd.Stdin = os.Stdin
+			cmd.Stdout = os.Stdout
+			cmd.Stderr = os.Stderr
+			err = cmd.Run()
+			if err != nil {
+				return fmt.Errorf("error while running cmd. %v", err)
 			}
-		}()
-		scanner := bufio.NewScanner(file)
-		cmd.Env = os.Environ()
-		for scanner.Scan() {
-			line := scanner.Text()
-			cmd.Env = append(cmd.Env, line)
 		}
+
+		fmt.Printf("Exited Backplane session \n")
+	}
+
+	return nil
+}
+
+// runCommand runs the command of the options in the session environment, from the current directory
+func (e *BackplaneSession) runCommand() error {
+	env, err := e.environment()
+	if err != nil {
+		return err
+	}
+
+	// The session bin directory comes first in the PATH of the session
+	name, err := lookPath(e.Options.Command[0], envValue(env, "PATH"))
+	if err != nil {
+		return err
+	}
+	cmd := exec.Command(name, e.Options.Command[1:]...)
+	cmd.Env = env
+
+	if e.Options.Record {
+		var recording string
+		recording, err = e.runRecorded(cmd)
+		if recording != "" {
+			fmt.Fprintf(os.Stderr, "Command recorded to %s\n", recording)
+		}
+	} else {
 		cmd.Stdin = os.Stdin
 		cmd.Stdout = os.Stdout
 		cmd.Stderr = os.Stderr
-		cmd.Dir = e.Path
 		err = cmd.Run()
-		if err != nil {
-			return fmt.Errorf("error while running cmd. %v", err)
+	}
+
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		code := exitErr.ExitCode()
+		if code < 0 {
+			// Killed by a signal
+			code = 1
 		}
+		return &CommandExitError{Command: e.Options.Command[0], Code: code}
+	}
+	if err != nil {
+		return fmt.Errorf("error while running %s. %v", e.Options.Command[0], err)
+	}
+	return nil
+}
 
-		fmt.Printf("Exited Backplane session \n")
+// environment returns the environment of the session processes, the current one with the variables of .ocenv
+func (e *BackplaneSession) environment() ([]string, error) {
+	path := filepath.Clean(e.Path + "/.ocenv")
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err := file.Close(); err != nil {
+			fmt.Println("Error closing file: ", path)
+			return
+		}
+	}()
+
+	env := os.Environ()
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line != "" {
+			env = append(env, line)
+		}
 	}
+	return env, scanner.Err()
+}
 
-	return nil
+// envValue returns the value of the variable in the environment, the last one wins like for exec.Cmd
+func envValue(env []string, name string) string {
+	value := ""
+	for _, variable := range env {
+		if k, v, found := strings.Cut(variable, "="); found && k == name {
+			value = v
+		}
+	}
+	return value
+}
+
+// lookPath finds the executable of the command in the directories of the given PATH,
+// as exec.LookPath only searches the PATH of the current process
+func lookPath(name string, path string) (string, error) {
+	if strings.Contains(name, "/") {
+		return name, nil
+	}
+	for _, dir := range filepath.SplitList(path) {
+		if dir == "" {
+			dir = "."
+		}
+		candidate := filepath.Join(dir, name)
+		if stat, err := os.Stat(candidate); err == nil && !stat.IsDir() && stat.Mode().Perm()&0111 != 0 {
+			return candidate, nil
+		}
+	}
+	return "", fmt.Errorf("command %s not found in the session PATH", name)
 }
 
 // Delete cleanup the backplane session
@@ -243,34 +406,14 @@ PATH=` + e.Path + `/bin:` + os.Getenv("PATH") + `
 		clusterEnvContent = clusterEnvContent + "CLUSTERNAME=" + e.Options.ClusterName + "\n"
 		envContent = envContent + clusterEnvContent
 	}
-	dirEnvFile, err := e.ensureFile(e.Path + "/.ocenv")
+	// The environment is refreshed when the session is resumed
+	envFile := filepath.Join(e.Path, ".ocenv")
+	err := os.WriteFile(envFile, []byte(envContent), 0600)
 	if err != nil {
-		return err
-	}
-	_, err = dirEnvFile.WriteString(envContent)
-	if err != nil {
-		log.Fatal(err)
+		return fmt.Errorf("error writing to file %s: %v", envFile, err)
 	}
-	defer func(dirEnvFile *os.File) {
-		dirEnvFile.Close()
-	}(dirEnvFile)
 
-	zshEnvFile, err := e.ensureFile(e.Path + "/.zshenv")
-	if err != nil {
-		return err
-	}
-	_, err = zshEnvFile.WriteString("source .ocenv")
-	if err != nil {
-		log.Fatal(err)
-	}
-	defer func(dirEnvFile *os.File) {
-		err := dirEnvFile.Close()
-		if err != nil {
-			fmt.Println("Error while calling dirEnvFile.Close(): ", err.Error())
-			return
-		}
-	}(dirEnvFile)
-	return nil
+	return e.writeShellConfig()
 }
 
 // createHistoryFile create .history file inside the session folder
@@ -280,6 +423,10 @@ func (e *BackplaneSession) createHistoryFile() error {
 	if err != nil {
 		return err
 	}
+	// The history of a resumed session is kept, no file is created then
+	if scriptFile == nil {
+		return nil
+	}
 	defer func(scriptFile *os.File) {
 		err := scriptFile.Close()
 		if err != nil {
@@ -290,7 +437,7 @@ func (e *BackplaneSession) createHistoryFile() error {
 	return nil
 }
 
-// createBins create bins inside the session folder bin dir
+// createBins create bins inside the session folder bin dir, from the built-in helpers and the session template
 func (e *BackplaneSession) createBins() error {
 	if _, err := os.Stat(e.binPath()); errors.Is(err, os.ErrNotExist) {
 		err := os.Mkdir(e.binPath(), os.ModePerm)
@@ -298,38 +445,25 @@ func (e *BackplaneSession) createBins() error {
 			log.Fatal(err)
 		}
 	}
-	err := e.createBin("ocd", "ocm describe cluster "+e.Options.ClusterID)
-	if err != nil {
-		return err
-	}
-	ocb := `
-#!/bin/bash
-
-set -euo pipefail
 
-`
-	err = e.createBin("ocb", ocb)
+	template, err := readTemplate()
 	if err != nil {
 		return err
 	}
+	for _, file := range template {
+		err = e.createTemplateFile(file)
+		if err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
 // createBin create bin file with given content
 func (e *BackplaneSession) createBin(cmd string, content string) error {
 	path := filepath.Join(e.binPath(), cmd)
-	scriptFile, err := e.ensureFile(path)
-	if err != nil {
-		return err
-	}
-	defer func(scriptFile *os.File) {
-		err := scriptFile.Close()
-		if err != nil {
-			fmt.Println("Error closing file: ", path)
-			return
-		}
-	}(scriptFile)
-	_, err = scriptFile.WriteString(content)
+	// The bins are replaced when a session is resumed
+	err := os.WriteFile(path, []byte(content), 0600)
 	if err != nil {
 		return fmt.Errorf("error writing to file %s: %v", path, err)
 	}
@@ -361,23 +495,12 @@ func (e *BackplaneSession) binPath() string {
 func (e *BackplaneSession) initSessionPath() error {
 
 	if e.Path == "" {
-		bpConfig, err := config.GetBackplaneConfiguration()
-		if err != nil {
-			return err
-		}
-		sessionDir := info.BackplaneDefaultSessionDirectory
-
-		// Get the session directory name via config
-		if bpConfig.SessionDirectory != "" {
-			sessionDir = bpConfig.SessionDirectory
-		}
-
-		userHomeDir, err := os.UserHomeDir()
+		sessionsDir, err := GetSessionsDirectory()
 		if err != nil {
 			return err
 		}
 
-		e.Path = filepath.Join(userHomeDir, sessionDir, e.Options.Alias)
+		e.Path = filepath.Join(sessionsDir, e.Options.Alias)
 	}
 
 	// Add Alias to the path
@@ -413,6 +536,15 @@ func (e *BackplaneSession) initClusterLogin(cmd *cobra.Command) error {
 	return nil
 }
 
+// messages returns where the session messages are printed: stderr when a command is run in the session,
+// its output is left to the command for scripts, else stdout
+func (e *BackplaneSession) messages() io.Writer {
+	if len(e.Options.Command) > 0 {
+		return os.Stderr
+	}
+	return os.Stdout
+}
+
 // printSessionHeader prints backplane session title and help
 func (e *BackplaneSession) printSessionHeader() {
 	fmt.Println("========================================================================")
@@ -422,7 +554,7 @@ func (e *BackplaneSession) printSessionHeader() {
 	fmt.Println("* type \"exit\" to terminate the current session                         *")
 	fmt.Println("* You can use oc commands to interact with cluster                     *")
 	fmt.Println("*                                                                      *")
-	fmt.Println("* If the session is not initialized in the cluster env automatically   *")
-	fmt.Println("* then executes \"source .ocenv\" enable it manually                     *")
+	fmt.Println("* bash, zsh and fish load the session environment, with other shells   *")
+	fmt.Println("* execute \"source .ocenv\" to enable it manually                        *")
 	fmt.Println("========================================================================")
 }
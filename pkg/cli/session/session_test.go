@@ -3,6 +3,7 @@ package session
 import (
 	"bufio"
 	"errors"
+	"io"
 	"net/http"
 	"os"
 	"path/filepath"
@@ -11,7 +12,10 @@ import (
 	"github.com/golang/mock/gomock"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
 	"github.com/spf13/cobra"
+	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/client-go/tools/clientcmd/api"
 
 	"github.com/openshift/backplane-cli/pkg/cli/globalflags"
 	"github.com/openshift/backplane-cli/pkg/client/mocks"
@@ -22,6 +26,9 @@ import (
 
 var _ = Describe("Backplane Session Unit test", func() {
 	var (
+		configDir    string
+		originalHome string
+
 		mockCtrl           *gomock.Controller
 		mockClient         *mocks.MockClientInterface
 		mockClientWithResp *mocks.MockClientWithResponsesInterface
@@ -37,7 +44,8 @@ var _ = Describe("Backplane Session Unit test", func() {
 		trueClusterID   string
 		backplaneAPIUri string
 
-		fakeResp *http.Response
+		fakeResp    *http.Response
+		clusterInfo *cmv1.Cluster
 	)
 
 	BeforeEach(func() {
@@ -80,10 +88,22 @@ var _ = Describe("Backplane Session Unit test", func() {
 		}
 		fakeResp.Header.Add("Content-Type", "json")
 
+		clusterInfo, _ = cmv1.NewCluster().ID(trueClusterID).Name(testClusterID).State(cmv1.ClusterStateReady).Build()
+
 		os.Setenv(info.BackplaneURLEnvName, backplaneAPIUri)
+		configDir, err = os.MkdirTemp("", "backplane")
+		Expect(err).To(BeNil())
+		os.Setenv(info.BackplaneConfigPathEnvName, filepath.Join(configDir, "config.json"))
+
+		// The session shell doesn't load the configuration of the user
+		originalHome = os.Getenv("HOME")
+		os.Setenv("HOME", configDir)
 	})
 
 	AfterEach(func() {
+		os.Setenv("HOME", originalHome)
+		os.RemoveAll(configDir)
+		os.Unsetenv(info.BackplaneConfigPathEnvName)
 		bpSession = BackplaneSession{}
 	})
 
@@ -156,7 +176,7 @@ var _ = Describe("Backplane Session Unit test", func() {
 			mockClientWithResp.EXPECT().LoginClusterWithResponse(gomock.Any(), gomock.Any()).Return(nil, nil).AnyTimes()
 			mockOcmInterface.EXPECT().GetTargetCluster(options.ClusterID).Return(trueClusterID, testClusterID, nil).AnyTimes()
 			mockOcmInterface.EXPECT().GetTargetCluster(trueClusterID).Return(trueClusterID, testClusterID, nil).AnyTimes()
-			mockOcmInterface.EXPECT().IsClusterHibernating(gomock.Eq(trueClusterID)).Return(false, nil).AnyTimes()
+			mockOcmInterface.EXPECT().GetClusterInfoByID(gomock.Eq(trueClusterID)).Return(clusterInfo, nil).AnyTimes()
 			mockOcmInterface.EXPECT().GetOCMAccessToken().Return(&testToken, nil).AnyTimes()
 			mockClientUtil.EXPECT().MakeRawBackplaneAPIClientWithAccessToken(backplaneAPIUri, testToken).Return(mockClient, nil).AnyTimes()
 			mockClient.EXPECT().LoginCluster(gomock.Any(), gomock.Eq(trueClusterID)).Return(fakeResp, nil).AnyTimes()
@@ -173,7 +193,7 @@ var _ = Describe("Backplane Session Unit test", func() {
 			mockClientWithResp.EXPECT().LoginClusterWithResponse(gomock.Any(), gomock.Any()).Return(nil, nil).AnyTimes()
 			mockOcmInterface.EXPECT().GetTargetCluster(options.ClusterID).Return(trueClusterID, testClusterID, nil).AnyTimes()
 			mockOcmInterface.EXPECT().GetTargetCluster(trueClusterID).Return(trueClusterID, testClusterID, nil).AnyTimes()
-			mockOcmInterface.EXPECT().IsClusterHibernating(gomock.Eq(trueClusterID)).Return(false, nil).AnyTimes()
+			mockOcmInterface.EXPECT().GetClusterInfoByID(gomock.Eq(trueClusterID)).Return(clusterInfo, nil).AnyTimes()
 			mockOcmInterface.EXPECT().GetOCMAccessToken().Return(&testToken, nil).AnyTimes()
 			mockClientUtil.EXPECT().MakeRawBackplaneAPIClientWithAccessToken(backplaneAPIUri, testToken).Return(mockClient, nil).AnyTimes()
 			mockClient.EXPECT().LoginCluster(gomock.Any(), gomock.Eq(trueClusterID)).Return(fakeResp, nil).AnyTimes()
@@ -204,6 +224,206 @@ var _ = Describe("Backplane Session Unit test", func() {
 		})
 	})
 
+	Context("test Backplane Run Command with a command", func() {
+		It("should leave stdout to the command", func() {
+			options.Alias = "my-session"
+			options.ClusterID = testClusterID
+			options.GlobalOpts.Manager = true
+
+			mockClientWithResp.EXPECT().LoginClusterWithResponse(gomock.Any(), gomock.Any()).Return(nil, nil).AnyTimes()
+			mockOcmInterface.EXPECT().GetTargetCluster(testClusterID).Return(trueClusterID, testClusterID, nil).AnyTimes()
+			mockOcmInterface.EXPECT().GetTargetCluster(trueClusterID).Return(trueClusterID, testClusterID, nil).AnyTimes()
+			mockOcmInterface.EXPECT().GetManagingCluster(trueClusterID).Return(trueClusterID, testClusterID, nil).AnyTimes()
+			mockOcmInterface.EXPECT().GetClusterInfoByID(gomock.Eq(trueClusterID)).Return(clusterInfo, nil).AnyTimes()
+			mockOcmInterface.EXPECT().GetOCMAccessToken().Return(&testToken, nil).AnyTimes()
+			mockClientUtil.EXPECT().MakeRawBackplaneAPIClientWithAccessToken(backplaneAPIUri, testToken).Return(mockClient, nil).AnyTimes()
+			mockClient.EXPECT().LoginCluster(gomock.Any(), gomock.Eq(trueClusterID)).Return(fakeResp, nil).AnyTimes()
+
+			// The session of the management cluster is resumed, which prints its last use too
+			bpSession.Path = filepath.Join(bpSession.Path, trueClusterID)
+			Expect(bpSession.Setup()).To(Succeed())
+			options.Command = []string{"/bin/sh", "-c", "echo output"}
+
+			stdout := os.Stdout
+			reader, writer, err := os.Pipe()
+			Expect(err).To(BeNil())
+			os.Stdout = writer
+			err = bpSession.RunCommand(cmd, []string{})
+			os.Stdout = stdout
+			writer.Close()
+			Expect(err).To(BeNil())
+
+			output, err := io.ReadAll(reader)
+			Expect(err).To(BeNil())
+			Expect(string(output)).To(Equal("output\n"))
+		})
+	})
+
+	Context("test Backplane Run Command resume", func() {
+		It("should refresh the kubeconfig of a resumed session", func() {
+			options.Alias = "my-session"
+			options.ClusterID = testClusterID
+			options.Command = []string{"/bin/sh", "-c", "true"}
+
+			mockClientWithResp.EXPECT().LoginClusterWithResponse(gomock.Any(), gomock.Any()).Return(nil, nil).AnyTimes()
+			mockOcmInterface.EXPECT().GetTargetCluster(testClusterID).Return(trueClusterID, testClusterID, nil).AnyTimes()
+			mockOcmInterface.EXPECT().GetTargetCluster(trueClusterID).Return(trueClusterID, testClusterID, nil).AnyTimes()
+			mockOcmInterface.EXPECT().GetClusterInfoByID(gomock.Eq(trueClusterID)).Return(clusterInfo, nil).AnyTimes()
+			mockOcmInterface.EXPECT().GetOCMAccessToken().Return(&testToken, nil).AnyTimes()
+			mockClientUtil.EXPECT().MakeRawBackplaneAPIClientWithAccessToken(backplaneAPIUri, testToken).Return(mockClient, nil).AnyTimes()
+			mockClient.EXPECT().LoginCluster(gomock.Any(), gomock.Eq(trueClusterID)).Return(fakeResp, nil).AnyTimes()
+
+			// A session left with the kubeconfig of an older login
+			sessionPath := filepath.Join(bpSession.Path, options.Alias)
+			bpSession.Path = sessionPath
+			options.ClusterID = trueClusterID
+			Expect(bpSession.Setup()).To(Succeed())
+			options.ClusterID = testClusterID
+			stale := api.NewConfig()
+			stale.Clusters["stale"] = &api.Cluster{Server: "https://api.stale.example.com:6443"}
+			stale.AuthInfos["stale"] = &api.AuthInfo{Token: "stale-token"}
+			stale.Contexts["stale"] = &api.Context{Cluster: "stale", AuthInfo: "stale"}
+			stale.CurrentContext = "stale"
+			kubeConfigPath := filepath.Join(sessionPath, trueClusterID, "config")
+			Expect(os.MkdirAll(filepath.Dir(kubeConfigPath), 0700)).To(Succeed())
+			Expect(clientcmd.WriteToFile(*stale, kubeConfigPath)).To(Succeed())
+
+			Expect(bpSession.RunCommand(cmd, []string{})).To(Succeed())
+
+			kubeConfig, err := clientcmd.LoadFromFile(kubeConfigPath)
+			Expect(err).To(BeNil())
+			Expect(kubeConfig.Clusters).NotTo(HaveKey("stale"))
+			Expect(kubeConfig.AuthInfos).NotTo(HaveKey("stale"))
+			Expect(kubeConfig.CurrentContext).NotTo(Equal("stale"))
+		})
+	})
+
+	Context("check Backplane session resume", func() {
+		BeforeEach(func() {
+			options.Alias = "my-session"
+			options.ClusterID = trueClusterID
+			options.ClusterName = testClusterID
+			Expect(bpSession.Setup()).To(Succeed())
+
+			// Leave notes and history in the session
+			Expect(os.WriteFile(filepath.Join(bpSession.Path, "notes.txt"), []byte("notes"), 0600)).To(Succeed())
+			Expect(os.WriteFile(filepath.Join(bpSession.Path, ".history"), []byte("oc get nodes\n"), 0600)).To(Succeed())
+		})
+
+		It("should resume the session with its history and files", func() {
+			created, err := readSession(bpSession.Path)
+			Expect(err).To(BeNil())
+
+			options.ClusterName = "renamed-cluster"
+			Expect(bpSession.Setup()).To(Succeed())
+
+			notes, err := os.ReadFile(filepath.Join(bpSession.Path, "notes.txt"))
+			Expect(err).To(BeNil())
+			Expect(string(notes)).To(Equal("notes"))
+
+			resumed, err := readSession(bpSession.Path)
+			Expect(err).To(BeNil())
+			Expect(resumed.HistorySize).To(Equal(1))
+			Expect(resumed.CreatedAt.Equal(created.CreatedAt)).To(BeTrue())
+			Expect(resumed.ClusterName).To(Equal("renamed-cluster"))
+
+			// The environment is refreshed
+			env, err := os.ReadFile(filepath.Join(bpSession.Path, ".ocenv"))
+			Expect(err).To(BeNil())
+			Expect(string(env)).To(ContainSubstring("CLUSTERNAME=renamed-cluster\n"))
+		})
+
+		It("should recreate the bins of a session moved to another cluster", func() {
+			options.ClusterID = "other-cluster"
+			Expect(bpSession.Setup()).To(Succeed())
+
+			content, err := os.ReadFile(filepath.Join(bpSession.Path, "bin", "login-cluster"))
+			Expect(err).To(BeNil())
+			Expect(string(content)).To(ContainSubstring(`login "other-cluster"`))
+			Expect(filepath.Join(bpSession.Path, "notes.txt")).To(BeAnExistingFile())
+		})
+
+		It("should rewrite the bins and keep the user's own ones on resume", func() {
+			loginCluster := filepath.Join(bpSession.Path, "bin", "login-cluster")
+			myScript := filepath.Join(bpSession.Path, "bin", "my-script")
+			Expect(os.WriteFile(loginCluster, []byte("outdated"), 0600)).To(Succeed())
+			Expect(os.WriteFile(myScript, []byte("mine"), 0600)).To(Succeed())
+
+			Expect(bpSession.Setup()).To(Succeed())
+
+			content, err := os.ReadFile(loginCluster)
+			Expect(err).To(BeNil())
+			Expect(string(content)).To(ContainSubstring(`login "` + trueClusterID + `"`))
+			content, err = os.ReadFile(myScript)
+			Expect(err).To(BeNil())
+			Expect(string(content)).To(Equal("mine"))
+		})
+
+		It("should keep the files of a session whose metadata can't be read", func() {
+			metadataFile := filepath.Join(bpSession.Path, MetadataFileName)
+			Expect(os.WriteFile(metadataFile, []byte("{invalid"), 0600)).To(Succeed())
+
+			Expect(bpSession.Setup()).To(Succeed())
+
+			Expect(filepath.Join(bpSession.Path, "notes.txt")).To(BeAnExistingFile())
+			resumed, err := readSession(bpSession.Path)
+			Expect(err).To(BeNil())
+			Expect(resumed.HistorySize).To(Equal(1))
+			Expect(resumed.ClusterID).To(Equal(trueClusterID))
+		})
+
+		It("should recreate the session with the fresh option", func() {
+			options.Fresh = true
+			Expect(bpSession.Setup()).To(Succeed())
+
+			Expect(filepath.Join(bpSession.Path, "notes.txt")).NotTo(BeAnExistingFile())
+			history, err := os.ReadFile(filepath.Join(bpSession.Path, ".history"))
+			Expect(err).To(BeNil())
+			Expect(history).To(BeEmpty())
+		})
+	})
+
+	Context("check Backplane session command", func() {
+		BeforeEach(func() {
+			options.Alias = "my-session"
+			options.ClusterID = trueClusterID
+			options.ClusterName = testClusterID
+			Expect(bpSession.Setup()).To(Succeed())
+		})
+
+		It("should run the command in the session environment", func() {
+			output := filepath.Join(bpSession.Path, "output")
+			options.Command = []string{"/bin/sh", "-c", `echo "$CLUSTERID $KUBECONFIG" > ` + output}
+			Expect(bpSession.Start()).To(Succeed())
+
+			content, err := os.ReadFile(output)
+			Expect(err).To(BeNil())
+			Expect(string(content)).To(Equal(trueClusterID + " " + filepath.Join(bpSession.Path, trueClusterID, "config") + "\n"))
+		})
+
+		It("should find the commands of the session bin directory", func() {
+			output := filepath.Join(bpSession.Path, "output")
+			Expect(os.WriteFile(filepath.Join(bpSession.Path, "bin", "hello"), []byte("#!/bin/sh\necho hello > "+output+"\n"), 0700)).To(Succeed())
+			options.Command = []string{"hello"}
+			Expect(bpSession.Start()).To(Succeed())
+			Expect(output).To(BeAnExistingFile())
+
+			options.Command = []string{"no-such-command"}
+			err := bpSession.Start()
+			Expect(err).NotTo(BeNil())
+			Expect(err.Error()).To(ContainSubstring("command no-such-command not found"))
+		})
+
+		It("should return the exit status of the command", func() {
+			options.Command = []string{"/bin/sh", "-c", "exit 3"}
+			err := bpSession.Start()
+
+			var exitErr *CommandExitError
+			Expect(errors.As(err, &exitErr)).To(BeTrue())
+			Expect(exitErr.Code).To(Equal(3))
+		})
+	})
+
 	Context("check Backplane session delete", func() {
 		It("Session should delete ", func() {
 			options.Alias = "my-session"
@@ -212,7 +432,7 @@ var _ = Describe("Backplane Session Unit test", func() {
 			mockClientWithResp.EXPECT().LoginClusterWithResponse(gomock.Any(), gomock.Any()).Return(nil, nil).AnyTimes()
 			mockOcmInterface.EXPECT().GetTargetCluster(options.ClusterID).Return(trueClusterID, testClusterID, nil).AnyTimes()
 			mockOcmInterface.EXPECT().GetTargetCluster(trueClusterID).Return(trueClusterID, testClusterID, nil).AnyTimes()
-			mockOcmInterface.EXPECT().IsClusterHibernating(gomock.Eq(trueClusterID)).Return(false, nil).AnyTimes()
+			mockOcmInterface.EXPECT().GetClusterInfoByID(gomock.Eq(trueClusterID)).Return(clusterInfo, nil).AnyTimes()
 			mockOcmInterface.EXPECT().GetOCMAccessToken().Return(&testToken, nil).AnyTimes()
 			mockClientUtil.EXPECT().MakeRawBackplaneAPIClientWithAccessToken(backplaneAPIUri, testToken).Return(mockClient, nil).AnyTimes()
 			mockClient.EXPECT().LoginCluster(gomock.Any(), gomock.Eq(trueClusterID)).Return(fakeResp, nil).AnyTimes()
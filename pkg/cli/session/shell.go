@@ -0,0 +1,113 @@
+package session
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// The shell configuration files generated in the session directory. They load the user
+// configuration first, then the session environment, rc fragments and prompt on top of it.
+const (
+	bashrcFileName     = ".bashrc"
+	zshenvFileName     = ".zshenv"
+	zshrcFileName      = ".zshrc"
+	fishConfigFileName = ".config.fish"
+
+	// userZdotdirEnvName keeps the zsh configuration directory of the user, as ZDOTDIR points
+	// to the session directory for zsh to read the session configuration
+	userZdotdirEnvName = "BACKPLANE_USER_ZDOTDIR"
+)
+
+// shellCommand returns the command starting the shell with the session configuration, and the
+// variables it needs on top of the session environment. Shells other than bash, zsh and fish
+// only get the session environment.
+func (e *BackplaneSession) shellCommand(shell string) (*exec.Cmd, []string) {
+	switch filepath.Base(shell) {
+	case "bash":
+		return exec.Command(shell, "--rcfile", filepath.Join(e.Path, bashrcFileName), "-i"), nil
+	case "zsh":
+		userZdotdir := os.Getenv("ZDOTDIR")
+		if userZdotdir == "" {
+			userZdotdir = os.Getenv("HOME")
+		}
+		return exec.Command(shell), []string{"ZDOTDIR=" + e.Path, userZdotdirEnvName + "=" + userZdotdir}
+	case "fish":
+		// The init command runs after the configuration of the user
+		return exec.Command(shell, "--init-command", "source "+fishQuote(filepath.Join(e.Path, fishConfigFileName))), nil
+	default:
+		return exec.Command(shell), nil
+	}
+}
+
+// writeShellConfig writes the shell configuration files of the session, again on every setup
+func (e *BackplaneSession) writeShellConfig() error {
+	envFile := shQuote(filepath.Join(e.Path, ".ocenv"))
+	prompt := shQuote("[" + e.Options.Alias + "] ")
+
+	// bash and zsh export the session environment the same way, the values aren't quoted in .ocenv
+	session := fmt.Sprintf(`while IFS= read -r line; do
+	[ -n "$line" ] && export "$line"
+done < %s
+`, envFile)
+	for _, fragment := range e.rcFragments() {
+		session += "source " + shQuote(filepath.Join(e.Path, fragment)) + "\n"
+	}
+
+	files := map[string]string{
+		bashrcFileName: `# Generated by backplane session, bash reads it with --rcfile
+[ -f ~/.bashrc ] && source ~/.bashrc
+` + session + `PS1=` + prompt + `"$PS1"
+`,
+		// zsh reads .zshenv and .zshrc from ZDOTDIR, they load the files of the user directory
+		zshenvFileName: `# Generated by backplane session, zsh reads it as ZDOTDIR is the session directory
+ZDOTDIR="$` + userZdotdirEnvName + `"
+[ -f "$ZDOTDIR/.zshenv" ] && source "$ZDOTDIR/.zshenv"
+` + userZdotdirEnvName + `="$ZDOTDIR"
+ZDOTDIR=` + shQuote(e.Path) + `
+`,
+		zshrcFileName: `# Generated by backplane session, zsh reads it as ZDOTDIR is the session directory
+ZDOTDIR="$` + userZdotdirEnvName + `"
+unset ` + userZdotdirEnvName + `
+[ -f "$ZDOTDIR/.zshrc" ] && source "$ZDOTDIR/.zshrc"
+` + session + `PROMPT=` + prompt + `"$PROMPT"
+`,
+		// fish can't source .ocenv, the rc fragments are bash and zsh only
+		fishConfigFileName: `# Generated by backplane session, fish runs it with --init-command
+while read -l line
+    set -l variable (string split -m 1 = -- $line)
+    test (count $variable) -eq 2; or continue
+    if string match -q -- '*PATH' $variable[1]
+        set -gx $variable[1] (string split : -- $variable[2])
+    else
+        set -gx $variable[1] $variable[2]
+    end
+end < ` + fishQuote(filepath.Join(e.Path, ".ocenv")) + `
+functions -q __backplane_user_fish_prompt; or functions -c fish_prompt __backplane_user_fish_prompt
+function fish_prompt
+    printf '%s' ` + fishQuote("["+e.Options.Alias+"] ") + `
+    __backplane_user_fish_prompt
+end
+`,
+	}
+
+	for name, content := range files {
+		path := filepath.Join(e.Path, name)
+		if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+			return fmt.Errorf("error writing to file %s: %v", path, err)
+		}
+	}
+	return nil
+}
+
+// shQuote quotes the string for bash and zsh
+func shQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// fishQuote quotes the string for fish
+func fishQuote(s string) string {
+	return "'" + strings.NewReplacer(`\`, `\\`, `'`, `\'`).Replace(s) + "'"
+}
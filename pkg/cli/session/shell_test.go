@@ -0,0 +1,102 @@
+package session
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/openshift/backplane-cli/pkg/cli/globalflags"
+	"github.com/openshift/backplane-cli/pkg/info"
+)
+
+var _ = Describe("Backplane Session shell integration", func() {
+	var (
+		homeDir      string
+		originalHome string
+		bpSession    BackplaneSession
+	)
+
+	BeforeEach(func() {
+		var err error
+		homeDir, err = os.MkdirTemp("", "bp-home")
+		Expect(err).To(BeNil())
+		originalHome = os.Getenv("HOME")
+		os.Setenv("HOME", homeDir)
+		os.Setenv(info.BackplaneConfigPathEnvName, filepath.Join(homeDir, "config.json"))
+
+		templateDir := filepath.Join(homeDir, info.BackplaneDefaultSessionTemplateDirectory)
+		Expect(os.MkdirAll(templateDir, 0750)).To(Succeed())
+		Expect(os.WriteFile(filepath.Join(templateDir, "aliases.rc"), []byte("alias pods='oc get pods'\n"), 0600)).To(Succeed())
+
+		bpSession = BackplaneSession{
+			// A space checks the paths are quoted
+			Path: filepath.Join(homeDir, "my session"),
+			Options: &Options{
+				Alias:       "my-session",
+				ClusterID:   "cluster-id",
+				ClusterName: "cluster-name",
+				GlobalOpts:  &globalflags.GlobalOptions{},
+			},
+		}
+		Expect(bpSession.Setup()).To(Succeed())
+	})
+
+	AfterEach(func() {
+		os.Setenv("HOME", originalHome)
+		os.Unsetenv(info.BackplaneConfigPathEnvName)
+		os.RemoveAll(homeDir)
+	})
+
+	It("should start the shells with the session configuration", func() {
+		cmd, env := bpSession.shellCommand("/bin/bash")
+		Expect(cmd.Args).To(Equal([]string{"/bin/bash", "--rcfile", filepath.Join(bpSession.Path, ".bashrc"), "-i"}))
+		Expect(env).To(BeEmpty())
+
+		os.Unsetenv("ZDOTDIR")
+		cmd, env = bpSession.shellCommand("/usr/bin/zsh")
+		Expect(cmd.Args).To(Equal([]string{"/usr/bin/zsh"}))
+		Expect(env).To(Equal([]string{"ZDOTDIR=" + bpSession.Path, "BACKPLANE_USER_ZDOTDIR=" + homeDir}))
+
+		cmd, env = bpSession.shellCommand("/usr/local/bin/fish")
+		Expect(cmd.Args).To(Equal([]string{"/usr/local/bin/fish", "--init-command", "source '" + filepath.Join(bpSession.Path, ".config.fish") + "'"}))
+		Expect(env).To(BeEmpty())
+
+		cmd, env = bpSession.shellCommand("/bin/sh")
+		Expect(cmd.Args).To(Equal([]string{"/bin/sh"}))
+		Expect(env).To(BeEmpty())
+	})
+
+	It("should write the shell configuration files", func() {
+		for _, name := range []string{".bashrc", ".zshenv", ".zshrc", ".config.fish"} {
+			Expect(filepath.Join(bpSession.Path, name)).To(BeAnExistingFile())
+		}
+
+		zshrc, err := os.ReadFile(filepath.Join(bpSession.Path, ".zshrc"))
+		Expect(err).To(BeNil())
+		Expect(string(zshrc)).To(ContainSubstring(`[ -f "$ZDOTDIR/.zshrc" ] && source "$ZDOTDIR/.zshrc"`))
+		Expect(string(zshrc)).To(ContainSubstring("source '" + filepath.Join(bpSession.Path, "bin", "aliases.rc") + "'"))
+		Expect(string(zshrc)).To(ContainSubstring(`PROMPT='[my-session] '"$PROMPT"`))
+
+		fishConfig, err := os.ReadFile(filepath.Join(bpSession.Path, ".config.fish"))
+		Expect(err).To(BeNil())
+		Expect(string(fishConfig)).To(ContainSubstring("end < '" + filepath.Join(bpSession.Path, ".ocenv") + "'"))
+		Expect(string(fishConfig)).To(ContainSubstring(`printf '%s' '[my-session] '`))
+	})
+
+	It("should load the session environment after the bash configuration of the user", func() {
+		bash, err := exec.LookPath("bash")
+		if err != nil {
+			Skip("bash isn't installed")
+		}
+		Expect(os.WriteFile(filepath.Join(homeDir, ".bashrc"), []byte("KUBECONFIG=/home/config\nPS1='$ '\n"), 0600)).To(Succeed())
+
+		cmd, _ := bpSession.shellCommand(bash)
+		cmd.Args = append(cmd.Args, "-c", `printf '%s|%s|%s' "$KUBECONFIG" "$PS1" "$(alias pods)"`)
+		output, err := cmd.Output()
+		Expect(err).To(BeNil())
+		Expect(string(output)).To(Equal(filepath.Join(bpSession.Path, "cluster-id", "config") + "|[my-session] $ |alias pods='oc get pods'"))
+	})
+})
@@ -0,0 +1,173 @@
+package session
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	logger "github.com/sirupsen/logrus"
+
+	"github.com/openshift/backplane-cli/pkg/cli/config"
+	"github.com/openshift/backplane-cli/pkg/info"
+)
+
+// rcFragmentSuffix marks the template files sourced by the session shell, the other files are run
+const rcFragmentSuffix = ".rc"
+
+// builtinTemplate are the helpers of every session. A file of the session template directory
+// with the same name replaces them.
+var builtinTemplate = map[string]string{
+	"ocd": `#!/bin/bash
+# Describe the session cluster
+exec ocm describe cluster "${CLUSTERID}" "$@"
+`,
+	"ocb": `#!/bin/bash
+# Run a backplane command, eg. ocb console
+exec ocm backplane "$@"
+`,
+	"login-cluster": `#!/bin/bash
+# Log back into the session cluster
+exec ocm backplane login "${CLUSTERID}" "$@"
+`,
+	"login-mc": `#!/bin/bash
+# Log into the management cluster of the session cluster, login-cluster goes back to it
+exec ocm backplane login "${CLUSTERID}" --manager "$@"
+`,
+	"login-sc": `#!/bin/bash
+# Log into the service cluster of the session cluster, login-cluster goes back to it
+exec ocm backplane login "${CLUSTERID}" --service "$@"
+`,
+	"cloud-creds": `#!/bin/bash
+# Print the cloud credentials of the session cluster, eval "$(cloud-creds -o env)" exports them
+exec ocm backplane cloud credentials "${CLUSTERID}" "$@"
+`,
+	"cloud-console": `#!/bin/bash
+# Open the cloud console of the session cluster
+exec ocm backplane cloud console "${CLUSTERID}" "$@"
+`,
+}
+
+// templateFile is a file copied into the session bin directory
+type templateFile struct {
+	name    string
+	content string
+	// rc fragments are sourced by the session shell instead of being run
+	rc bool
+}
+
+// getTemplateDirectory returns the session template directory, relative paths are relative to the home directory
+func getTemplateDirectory() (string, bool, error) {
+	bpConfig, err := config.GetBackplaneConfiguration()
+	if err != nil {
+		return "", false, err
+	}
+
+	templateDir := info.BackplaneDefaultSessionTemplateDirectory
+	configured := bpConfig.SessionTemplateDirectory != ""
+	if configured {
+		templateDir = bpConfig.SessionTemplateDirectory
+	}
+
+	if !filepath.IsAbs(templateDir) {
+		userHomeDir, err := os.UserHomeDir()
+		if err != nil {
+			return "", false, err
+		}
+		templateDir = filepath.Join(userHomeDir, templateDir)
+	}
+	return templateDir, configured, nil
+}
+
+// readTemplate returns the built-in helpers and the files of the session template directory, sorted by name.
+// Only the executable files and the rc fragments of the directory are used.
+func readTemplate() ([]templateFile, error) {
+	files := map[string]templateFile{}
+	for name, content := range builtinTemplate {
+		files[name] = templateFile{name: name, content: content}
+	}
+
+	templateDir, configured, err := getTemplateDirectory()
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := os.ReadDir(templateDir)
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+		if configured {
+			logger.Warnf("Session template directory %s not found, using the built-in helpers only", templateDir)
+		}
+	case err != nil:
+		return nil, err
+	}
+
+	for _, entry := range entries {
+		path := filepath.Join(templateDir, entry.Name())
+		// Follow the symlinks, eg. to scripts kept in a dotfiles repository
+		stat, err := os.Stat(path)
+		if err != nil || !stat.Mode().IsRegular() || strings.HasPrefix(entry.Name(), ".") {
+			continue
+		}
+
+		rc := strings.HasSuffix(entry.Name(), rcFragmentSuffix)
+		if !rc && stat.Mode().Perm()&0111 == 0 {
+			logger.Debugf("Skipping session template file %s, it is neither executable nor a %s fragment", path, rcFragmentSuffix)
+			continue
+		}
+
+		content, err := os.ReadFile(filepath.Clean(path))
+		if err != nil {
+			return nil, err
+		}
+		files[entry.Name()] = templateFile{name: entry.Name(), content: string(content), rc: rc}
+	}
+
+	template := make([]templateFile, 0, len(files))
+	for _, file := range files {
+		template = append(template, file)
+	}
+	sort.Slice(template, func(i, j int) bool {
+		return template[i].name < template[j].name
+	})
+	return template, nil
+}
+
+// expandTemplate replaces the cluster variables of the template file content by the session cluster
+func (e *BackplaneSession) expandTemplate(content string) string {
+	return strings.NewReplacer(
+		"${CLUSTERID}", e.Options.ClusterID,
+		"${CLUSTERNAME}", e.Options.ClusterName,
+	).Replace(content)
+}
+
+// rcFragments returns the paths of the rc fragments of the session, relative to the session directory
+func (e *BackplaneSession) rcFragments() []string {
+	matches, err := filepath.Glob(filepath.Join(e.binPath(), "*"+rcFragmentSuffix))
+	if err != nil {
+		return nil
+	}
+
+	fragments := make([]string, 0, len(matches))
+	for _, match := range matches {
+		fragments = append(fragments, filepath.Join("bin", filepath.Base(match)))
+	}
+	sort.Strings(fragments)
+	return fragments
+}
+
+// createTemplateFile writes the template file into the session bin directory
+func (e *BackplaneSession) createTemplateFile(file templateFile) error {
+	content := e.expandTemplate(file.content)
+	if !file.rc {
+		return e.createBin(file.name, content)
+	}
+
+	path := filepath.Join(e.binPath(), file.name)
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		return fmt.Errorf("error writing to file %s: %v", path, err)
+	}
+	return nil
+}
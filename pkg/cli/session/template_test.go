@@ -0,0 +1,100 @@
+package session
+
+import (
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/spf13/viper"
+
+	"github.com/openshift/backplane-cli/pkg/cli/globalflags"
+	"github.com/openshift/backplane-cli/pkg/info"
+)
+
+var _ = Describe("Backplane Session template", func() {
+	var (
+		homeDir      string
+		originalHome string
+		templateDir  string
+		bpSession    BackplaneSession
+	)
+
+	BeforeEach(func() {
+		var err error
+		homeDir, err = os.MkdirTemp("", "bp-home")
+		Expect(err).To(BeNil())
+		originalHome = os.Getenv("HOME")
+		os.Setenv("HOME", homeDir)
+		os.Setenv(info.BackplaneConfigPathEnvName, filepath.Join(homeDir, "config.json"))
+
+		templateDir = filepath.Join(homeDir, info.BackplaneDefaultSessionTemplateDirectory)
+		Expect(os.MkdirAll(templateDir, 0750)).To(Succeed())
+
+		bpSession = BackplaneSession{
+			Path: filepath.Join(homeDir, "session"),
+			Options: &Options{
+				Alias:       "my-session",
+				ClusterID:   "cluster-id",
+				ClusterName: "cluster-name",
+				GlobalOpts:  &globalflags.GlobalOptions{},
+			},
+		}
+	})
+
+	AfterEach(func() {
+		os.Setenv("HOME", originalHome)
+		os.Unsetenv(info.BackplaneConfigPathEnvName)
+		os.RemoveAll(homeDir)
+		// The configuration read by a test stays in viper otherwise
+		viper.Reset()
+	})
+
+	readBin := func(name string) string {
+		content, err := os.ReadFile(filepath.Join(bpSession.Path, "bin", name))
+		Expect(err).To(BeNil())
+		return string(content)
+	}
+
+	It("should create the built-in helpers for the session cluster", func() {
+		Expect(bpSession.Setup()).To(Succeed())
+
+		for _, name := range []string{"ocd", "ocb", "login-cluster", "login-mc", "login-sc", "cloud-creds", "cloud-console"} {
+			stat, err := os.Stat(filepath.Join(bpSession.Path, "bin", name))
+			Expect(err).To(BeNil())
+			Expect(stat.Mode().Perm()).To(Equal(os.FileMode(0700)))
+		}
+		Expect(readBin("login-mc")).To(ContainSubstring(`ocm backplane login "cluster-id" --manager`))
+		Expect(readBin("ocd")).NotTo(ContainSubstring("${CLUSTERID}"))
+	})
+
+	It("should copy the template scripts and rc fragments with the cluster expanded", func() {
+		Expect(os.WriteFile(filepath.Join(templateDir, "pods"), []byte("#!/bin/bash\noc get pods # ${CLUSTERNAME} ${CLUSTERID} $HOME\n"), 0700)).To(Succeed())
+		Expect(os.WriteFile(filepath.Join(templateDir, "ocd"), []byte("#!/bin/bash\necho custom ${CLUSTERID}\n"), 0700)).To(Succeed())
+		Expect(os.WriteFile(filepath.Join(templateDir, "aliases.rc"), []byte("alias k=oc\nexport CLUSTER=${CLUSTERNAME}\n"), 0600)).To(Succeed())
+		Expect(os.WriteFile(filepath.Join(templateDir, "notes.txt"), []byte("not copied"), 0600)).To(Succeed())
+
+		Expect(bpSession.Setup()).To(Succeed())
+
+		Expect(readBin("pods")).To(Equal("#!/bin/bash\noc get pods # cluster-name cluster-id $HOME\n"))
+		Expect(readBin("ocd")).To(Equal("#!/bin/bash\necho custom cluster-id\n"))
+		Expect(readBin("aliases.rc")).To(Equal("alias k=oc\nexport CLUSTER=cluster-name\n"))
+		_, err := os.Stat(filepath.Join(bpSession.Path, "bin", "notes.txt"))
+		Expect(os.IsNotExist(err)).To(BeTrue())
+
+		zshrc, err := os.ReadFile(filepath.Join(bpSession.Path, ".zshrc"))
+		Expect(err).To(BeNil())
+		Expect(string(zshrc)).To(ContainSubstring("source '" + filepath.Join(bpSession.Path, "bin", "aliases.rc") + "'\n"))
+	})
+
+	It("should use the configured template directory", func() {
+		customDir := filepath.Join(homeDir, "my-template")
+		Expect(os.MkdirAll(customDir, 0750)).To(Succeed())
+		Expect(os.WriteFile(filepath.Join(customDir, "custom"), []byte("#!/bin/bash\n"), 0700)).To(Succeed())
+		Expect(os.WriteFile(filepath.Join(homeDir, "config.json"), []byte(`{"session-template-dir": "my-template"}`), 0600)).To(Succeed())
+
+		Expect(bpSession.Setup()).To(Succeed())
+
+		Expect(readBin("custom")).To(Equal("#!/bin/bash\n"))
+	})
+})
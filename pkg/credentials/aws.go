@@ -4,7 +4,6 @@ import (
 	"context"
 	"fmt"
 	"net/http"
-	"net/url"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	awshttp "github.com/aws/aws-sdk-go-v2/aws/transport/http"
@@ -50,15 +49,24 @@ func (r *AWSCredentialsResponse) AWSV2Config() (aws.Config, error) {
 		return aws.Config{}, fmt.Errorf("failed to load backplane config file: %w", err)
 	}
 
-	proxyURL, err := url.Parse(bpConfig.ProxyURL)
+	proxyURL := bpConfig.SelectProxyURL()
+	proxyFunc, err := bpConfig.ProxyFunc(proxyURL)
 	if err != nil {
 		return aws.Config{}, fmt.Errorf("failed to parse proxy_url from backplane config file: %w", err)
 	}
 
-	// Configure aws-sdk-go-v2 to use backplane's specified proxy
+	tlsConfig, err := bpConfig.TLSConfig(proxyURL)
+	if err != nil {
+		return aws.Config{}, err
+	}
+
+	// Configure aws-sdk-go-v2 to use backplane's specified proxy and TLS settings
 	// https://aws.github.io/aws-sdk-go-v2/docs/configuring-sdk/custom-http/#configuring-a-proxy
 	httpClient := awshttp.NewBuildableClient().WithTransportOptions(func(tr *http.Transport) {
-		tr.Proxy = http.ProxyURL(proxyURL)
+		tr.Proxy = proxyFunc
+		if tlsConfig != nil {
+			tr.TLSClientConfig = tlsConfig
+		}
 	})
 
 	return config.LoadDefaultConfig(context.Background(),
@@ -17,9 +17,21 @@ const (
 	BackplaneConfigDefaultFilePath = ".config/backplane"
 	BackplaneConfigDefaultFileName = "config.json"
 
+	// Login history, stored next to the configuration file
+	BackplaneHistoryFileName = "history.json"
+
+	// Prompt segment cache, stored next to the configuration file
+	BackplanePS1CacheFileName = "ps1-cache.json"
+
+	// Proxy selected among the configured candidates, stored next to the configuration file
+	BackplaneProxyCacheFileName = "proxy-cache.json"
+
 	// Session
 	BackplaneDefaultSessionDirectory = "backplane"
 
+	// Session template, whose files are copied into every session
+	BackplaneDefaultSessionTemplateDirectory = ".config/backplane/session-template"
+
 	// GitHub API get fetch the latest tag
 	UpstreamReleaseAPI = "https://api.github.com/repos/openshift/backplane-cli/releases/latest"
 
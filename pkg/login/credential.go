@@ -0,0 +1,56 @@
+package login
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clientauthv1 "k8s.io/client-go/pkg/apis/clientauthentication/v1"
+	"k8s.io/client-go/tools/clientcmd/api"
+
+	"github.com/openshift/backplane-cli/pkg/utils"
+)
+
+const (
+	// ExecCredentialAPIVersion is the client authentication API version used by the exec plugin
+	ExecCredentialAPIVersion = "client.authentication.k8s.io/v1"
+
+	// ExecCredentialKind is the kind of the object returned by the exec plugin
+	ExecCredentialKind = "ExecCredential"
+
+	// credentialCommand is invoked by oc/kubectl to get a fresh OCM token
+	credentialCommand = "ocm"
+)
+
+var (
+	credentialCommandArgs = []string{"backplane", "credential"}
+)
+
+// NewExecConfig returns the kubeconfig exec entry which calls the backplane credential plugin
+func NewExecConfig() *api.ExecConfig {
+	return &api.ExecConfig{
+		Command:         credentialCommand,
+		Args:            append([]string{}, credentialCommandArgs...),
+		APIVersion:      ExecCredentialAPIVersion,
+		InstallHint:     "The backplane credential plugin requires ocm and ocm-backplane in PATH, refer https://github.com/openshift/backplane-cli#installation",
+		InteractiveMode: api.NeverExecInteractiveMode,
+	}
+}
+
+// NewExecCredential wraps the given OCM access token in an ExecCredential.
+// The expiration timestamp is taken from the exp claim of the token if present.
+func NewExecCredential(accessToken string) *clientauthv1.ExecCredential {
+	credential := &clientauthv1.ExecCredential{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: ExecCredentialAPIVersion,
+			Kind:       ExecCredentialKind,
+		},
+		Status: &clientauthv1.ExecCredentialStatus{
+			Token: accessToken,
+		},
+	}
+
+	if exp, err := utils.GetExpirationFromJWT(accessToken); err == nil {
+		expirationTimestamp := metav1.NewTime(exp)
+		credential.Status.ExpirationTimestamp = &expirationTimestamp
+	}
+
+	return credential
+}
@@ -0,0 +1,113 @@
+package login
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/openshift/backplane-cli/pkg/cli/config"
+	"github.com/openshift/backplane-cli/pkg/info"
+	"github.com/openshift/backplane-cli/pkg/utils"
+)
+
+const (
+	// maxHistoryEntries is the number of clusters kept in the login history
+	maxHistoryEntries = 50
+
+	HistoryModeManager = "manager"
+	HistoryModeService = "service"
+)
+
+// HistoryEntry is a cluster logged into with backplane
+type HistoryEntry struct {
+	ClusterID   string    `json:"cluster_id"`
+	ClusterName string    `json:"cluster_name"`
+	Mode        string    `json:"mode,omitempty"`
+	Timestamp   time.Time `json:"timestamp"`
+}
+
+// GetHistoryFilePath returns the login history file path, next to the backplane configuration file
+func GetHistoryFilePath() (string, error) {
+	return config.GetConfigDirFilePath(info.BackplaneHistoryFileName)
+}
+
+// ReadHistory returns the login history, the most recent login first
+func ReadHistory() ([]HistoryEntry, error) {
+	path, err := GetHistoryFilePath()
+	if err != nil {
+		return nil, err
+	}
+
+	content, err := os.ReadFile(filepath.Clean(path))
+	if errors.Is(err, os.ErrNotExist) {
+		return []HistoryEntry{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("unable to read login history: %v", err)
+	}
+
+	history := []HistoryEntry{}
+	if err := json.Unmarshal(content, &history); err != nil {
+		return nil, fmt.Errorf("unable to parse login history %s: %v", path, err)
+	}
+
+	return history, nil
+}
+
+// AddHistoryEntry records the given login as the most recent one.
+// Previous logins to the same cluster are removed from the history.
+func AddHistoryEntry(entry HistoryEntry) error {
+	path, err := GetHistoryFilePath()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0750); err != nil {
+		return err
+	}
+
+	if entry.Timestamp.IsZero() {
+		entry.Timestamp = time.Now()
+	}
+
+	// Concurrent logins, eg. with --multi, would lose each other's entries without the lock
+	return utils.WithDirLock(filepath.Dir(path), func() error {
+		history, err := ReadHistory()
+		if err != nil {
+			return err
+		}
+
+		newHistory := []HistoryEntry{entry}
+		for _, e := range history {
+			if e.ClusterID != entry.ClusterID {
+				newHistory = append(newHistory, e)
+			}
+		}
+		if len(newHistory) > maxHistoryEntries {
+			newHistory = newHistory[:maxHistoryEntries]
+		}
+
+		content, err := json.MarshalIndent(newHistory, "", "  ")
+		if err != nil {
+			return err
+		}
+
+		return utils.WriteFileAtomic(path, content, 0600)
+	})
+}
+
+// GetPreviousHistoryEntry returns the cluster logged into before the most recent one
+func GetPreviousHistoryEntry() (HistoryEntry, error) {
+	history, err := ReadHistory()
+	if err != nil {
+		return HistoryEntry{}, err
+	}
+
+	if len(history) < 2 {
+		return HistoryEntry{}, fmt.Errorf("no previous cluster in the login history")
+	}
+
+	return history[1], nil
+}
@@ -0,0 +1,104 @@
+package login
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/openshift/backplane-cli/pkg/info"
+)
+
+var _ = Describe("Login history test", func() {
+
+	var configDir string
+
+	BeforeEach(func() {
+		var err error
+		configDir, err = os.MkdirTemp("", "backplane")
+		Expect(err).To(BeNil())
+		os.Setenv(info.BackplaneConfigPathEnvName, filepath.Join(configDir, "config.json"))
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(configDir)
+		os.Unsetenv(info.BackplaneConfigPathEnvName)
+	})
+
+	Context("record history", func() {
+		It("should return an empty history if no cluster was logged into", func() {
+			history, err := ReadHistory()
+
+			Expect(err).To(BeNil())
+			Expect(history).To(BeEmpty())
+		})
+
+		It("should keep the most recent login first without duplicates", func() {
+			Expect(AddHistoryEntry(HistoryEntry{ClusterID: "id1", ClusterName: "name1"})).To(Succeed())
+			Expect(AddHistoryEntry(HistoryEntry{ClusterID: "id2", ClusterName: "name2", Mode: HistoryModeManager})).To(Succeed())
+			Expect(AddHistoryEntry(HistoryEntry{ClusterID: "id1", ClusterName: "name1"})).To(Succeed())
+
+			history, err := ReadHistory()
+
+			Expect(err).To(BeNil())
+			Expect(len(history)).To(Equal(2))
+			Expect(history[0].ClusterID).To(Equal("id1"))
+			Expect(history[0].Timestamp.IsZero()).To(BeFalse())
+			Expect(history[1].ClusterID).To(Equal("id2"))
+			Expect(history[1].Mode).To(Equal(HistoryModeManager))
+		})
+
+		It("should keep the entries of concurrent logins", func() {
+			var wg sync.WaitGroup
+			for i := 0; i < 20; i++ {
+				wg.Add(1)
+				go func(i int) {
+					defer wg.Done()
+					defer GinkgoRecover()
+					Expect(AddHistoryEntry(HistoryEntry{ClusterID: strconv.Itoa(i)})).To(Succeed())
+				}(i)
+			}
+			wg.Wait()
+
+			history, err := ReadHistory()
+
+			Expect(err).To(BeNil())
+			Expect(len(history)).To(Equal(20))
+		})
+
+		It("should limit the number of entries", func() {
+			for i := 0; i < maxHistoryEntries+5; i++ {
+				Expect(AddHistoryEntry(HistoryEntry{ClusterID: strconv.Itoa(i)})).To(Succeed())
+			}
+
+			history, err := ReadHistory()
+
+			Expect(err).To(BeNil())
+			Expect(len(history)).To(Equal(maxHistoryEntries))
+			Expect(history[0].ClusterID).To(Equal(strconv.Itoa(maxHistoryEntries + 4)))
+		})
+	})
+
+	Context("previous cluster", func() {
+		It("should return the cluster logged into before the current one", func() {
+			Expect(AddHistoryEntry(HistoryEntry{ClusterID: "id1"})).To(Succeed())
+			Expect(AddHistoryEntry(HistoryEntry{ClusterID: "id2"})).To(Succeed())
+
+			previous, err := GetPreviousHistoryEntry()
+
+			Expect(err).To(BeNil())
+			Expect(previous.ClusterID).To(Equal("id1"))
+		})
+
+		It("should fail if there is no previous cluster", func() {
+			Expect(AddHistoryEntry(HistoryEntry{ClusterID: "id1"})).To(Succeed())
+
+			_, err := GetPreviousHistoryEntry()
+
+			Expect(err).NotTo(BeNil())
+		})
+	})
+})
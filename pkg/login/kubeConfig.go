@@ -2,9 +2,10 @@ package login
 
 import (
 	"errors"
-	"fmt"
 	"os"
 	"path/filepath"
+	"regexp"
+	"time"
 
 	"k8s.io/client-go/tools/clientcmd"
 	"k8s.io/client-go/tools/clientcmd/api"
@@ -12,12 +13,20 @@ import (
 	logger "github.com/sirupsen/logrus"
 
 	"github.com/openshift/backplane-cli/pkg/info"
+	"github.com/openshift/backplane-cli/pkg/utils"
 )
 
 var (
 	kubeConfigBasePath string
 )
 
+// ClusterKubeConfig is a cluster specific kube config created by backplane
+type ClusterKubeConfig struct {
+	ClusterID string
+	Path      string
+	ModTime   time.Time
+}
+
 // CreateClusterKubeConfig creates cluster specific kube config based on a cluster ID
 func CreateClusterKubeConfig(clusterID string, kubeConfig api.Config) (string, error) {
 
@@ -35,23 +44,17 @@ func CreateClusterKubeConfig(clusterID string, kubeConfig api.Config) (string, e
 		}
 	}
 
-	// Write kube config if file not exist
+	// Write kube config, again on every login as the namespace, CA or user may have changed
 	filename := filepath.Join(path, "config")
-	_, err = os.Stat(filename)
-	if errors.Is(err, os.ErrNotExist) {
-		f, err := os.Create(filename)
-		if err != nil {
-			return "", err
-		}
-		err = clientcmd.WriteToFile(kubeConfig, f.Name())
+	err = utils.WriteKubeConfig(filename, kubeConfig)
+	if err != nil {
+		return "", err
+	}
 
-		if err != nil {
-			return "", err
-		}
-		err = f.Close()
-		if err != nil {
-			return "", err
-		}
+	// An unchanged kube config isn't rewritten, refresh its modification time so it isn't seen as stale
+	now := time.Now()
+	if err := os.Chtimes(filename, now, now); err != nil {
+		logger.Debugf("Unable to refresh the modification time of %s: %v", filename, err)
 	}
 
 	// set kube config env with temp kube config file
@@ -64,6 +67,16 @@ func CreateClusterKubeConfig(clusterID string, kubeConfig api.Config) (string, e
 
 }
 
+// GetClusterKubeConfigPath returns the path of the cluster specific kube config file
+func GetClusterKubeConfigPath(clusterID string) (string, error) {
+	basePath, err := getKubeConfigBasePath()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(basePath, clusterID, "config"), nil
+}
+
 // RemoveClusterKubeConfig delete cluster specific kube config file
 func RemoveClusterKubeConfig(clusterID string) error {
 
@@ -81,41 +94,111 @@ func RemoveClusterKubeConfig(clusterID string) error {
 	return nil
 }
 
-// SaveKubeConfig modify Kube config based on user setting
-func SaveKubeConfig(clusterID string, config api.Config, isMulti bool, kubePath string) error {
+// ListClusterKubeConfigs returns the cluster specific kube configs created by backplane.
+// Only the kube configs pointing to a backplane API server are returned.
+func ListClusterKubeConfigs() ([]ClusterKubeConfig, error) {
+	basePath, err := getKubeConfigBasePath()
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := os.ReadDir(basePath)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	backplaneServerRegex := regexp.MustCompile(utils.BackplaneAPIURLRegexp)
+
+	kubeConfigs := []ClusterKubeConfig{}
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+
+		filename := filepath.Join(basePath, entry.Name(), "config")
+		fileInfo, err := os.Stat(filename)
+		if err != nil {
+			continue
+		}
+
+		config, err := clientcmd.LoadFromFile(filename)
+		if err != nil {
+			logger.Debugf("Skipping invalid kube config %s: %v", filename, err)
+			continue
+		}
+
+		isBackplane := false
+		for _, cluster := range config.Clusters {
+			if backplaneServerRegex.MatchString(cluster.Server) {
+				isBackplane = true
+				break
+			}
+		}
+		if !isBackplane {
+			continue
+		}
+
+		kubeConfigs = append(kubeConfigs, ClusterKubeConfig{
+			ClusterID: entry.Name(),
+			Path:      filename,
+			ModTime:   fileInfo.ModTime(),
+		})
+	}
+
+	return kubeConfigs, nil
+}
+
+// SaveKubeConfig modify Kube config based on user setting, and returns the path of the kube config written
+func SaveKubeConfig(clusterID string, config api.Config, isMulti bool, kubePath string) (string, error) {
+	var path string
 
 	if isMulti {
 		//update path
 		if kubePath != "" {
 			err := SetKubeConfigBasePath(kubePath)
 			if err != nil {
-				return err
+				return "", err
 			}
 		}
 		//save config to current session
-		path, err := CreateClusterKubeConfig(clusterID, config)
-
-		if kubePath == "" {
-			// Inform how to setup kube config
-			fmt.Printf("Execute the following command to log into the cluster %s \n", clusterID)
-			fmt.Println("export " + info.BackplaneKubeconfigEnvName + "=" + path)
-		}
-
+		var err error
+		path, err = CreateClusterKubeConfig(clusterID, config)
 		if err != nil {
-			return err
+			return "", err
 		}
 
 	} else {
-		// Save the config to default path.
-		configAccess := clientcmd.NewDefaultPathOptions()
-		err := clientcmd.ModifyConfig(configAccess, config, true)
-
+		// Add the config to the default kube config, which may have changed since the login started
+		err := utils.UpdateDefaultKubeConfig(func(rc *api.Config) error {
+			mergeKubeConfig(rc, config)
+			return nil
+		})
 		if err != nil {
-			return err
+			return "", err
 		}
+		path = clientcmd.NewDefaultPathOptions().GetDefaultFilename()
 	}
 	logger.Debugln("Wrote Kube configuration")
-	return nil
+	return path, nil
+}
+
+// mergeKubeConfig adds the clusters, users and contexts of src to dst, and switches to the current context of src
+func mergeKubeConfig(dst *api.Config, src api.Config) {
+	for name, cluster := range src.Clusters {
+		dst.Clusters[name] = cluster
+	}
+	for name, authInfo := range src.AuthInfos {
+		dst.AuthInfos[name] = authInfo
+	}
+	for name, context := range src.Contexts {
+		dst.Contexts[name] = context
+	}
+	if src.CurrentContext != "" {
+		dst.CurrentContext = src.CurrentContext
+	}
 }
 
 func getKubeConfigBasePath() (string, error) {
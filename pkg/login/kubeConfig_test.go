@@ -3,10 +3,15 @@ package login
 import (
 	"errors"
 	"os"
+	"path/filepath"
+	"time"
 
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
+	"k8s.io/client-go/tools/clientcmd"
 	"k8s.io/client-go/tools/clientcmd/api"
+
+	"github.com/openshift/backplane-cli/pkg/utils"
 )
 
 var _ = Describe("Login Kube Config test", func() {
@@ -50,6 +55,73 @@ var _ = Describe("Login Kube Config test", func() {
 			_, err = os.Stat(path)
 			Expect(err).To(BeNil())
 		})
+
+		It("should rewrite the cluster kube config on every login", func() {
+			err := SetKubeConfigBasePath(kubePath)
+			Expect(err).To(BeNil())
+			path, err := CreateClusterKubeConfig(testClusterID, kubeConfig)
+			Expect(err).To(BeNil())
+
+			kubeConfig.Contexts = map[string]*api.Context{"backplane": {Cluster: "dummy_cluster", Namespace: "my-project"}}
+			kubeConfig.CurrentContext = "backplane"
+			_, err = CreateClusterKubeConfig(testClusterID, kubeConfig)
+			Expect(err).To(BeNil())
+
+			cfg, err := clientcmd.LoadFromFile(path)
+			Expect(err).To(BeNil())
+			Expect(cfg.Contexts).To(HaveKey("backplane"))
+			Expect(cfg.Contexts["backplane"].Namespace).To(Equal("my-project"))
+		})
+
+		It("should refresh the modification time of an unchanged cluster kube config", func() {
+			err := SetKubeConfigBasePath(kubePath)
+			Expect(err).To(BeNil())
+			path, err := CreateClusterKubeConfig(testClusterID, kubeConfig)
+			Expect(err).To(BeNil())
+			old := time.Now().Add(-48 * time.Hour)
+			Expect(os.Chtimes(path, old, old)).To(Succeed())
+
+			_, err = CreateClusterKubeConfig(testClusterID, kubeConfig)
+			Expect(err).To(BeNil())
+
+			stat, err := os.Stat(path)
+			Expect(err).To(BeNil())
+			Expect(stat.ModTime()).To(BeTemporally(">", old.Add(time.Hour)))
+		})
+	})
+
+	Context("save kubeconfig with user setting", func() {
+		It("should return the path of the cluster kube config in multi cluster mode", func() {
+			path, err := SaveKubeConfig(testClusterID, kubeConfig, true, kubePath)
+
+			Expect(err).To(BeNil())
+			Expect(path).To(Equal(filepath.Join(kubePath, testClusterID, "config")))
+		})
+	})
+
+	Context("save kubeconfig to the default kubeconfig", func() {
+		It("should add the cluster to the existing kubeconfig", func() {
+			existing := api.NewConfig()
+			existing.Clusters["other"] = &api.Cluster{Server: "https://api.other.example.com:6443"}
+			existing.Contexts["other"] = &api.Context{Cluster: "other"}
+			existing.CurrentContext = "other"
+			err := utils.CreateTempKubeConfig(existing)
+			Expect(err).To(BeNil())
+			defer utils.RemoveTempKubeConfig()
+
+			kubeConfig.Contexts = map[string]*api.Context{"backplane": {Cluster: "dummy_cluster"}}
+			kubeConfig.CurrentContext = "backplane"
+
+			path, err := SaveKubeConfig(testClusterID, kubeConfig, false, "")
+			Expect(err).To(BeNil())
+			Expect(path).To(Equal(os.Getenv("KUBECONFIG")))
+
+			cfg, err := utils.ReadKubeconfigRaw()
+			Expect(err).To(BeNil())
+			Expect(cfg.Contexts).To(HaveKey("other"))
+			Expect(cfg.Contexts).To(HaveKey("backplane"))
+			Expect(cfg.CurrentContext).To(Equal("backplane"))
+		})
 	})
 
 	Context("Delete kubeconfig ", func() {
@@ -75,4 +147,27 @@ var _ = Describe("Login Kube Config test", func() {
 			Expect(errors.Is(err, os.ErrNotExist)).To(BeTrue())
 		})
 	})
+
+	Context("List kubeconfig ", func() {
+		It("should only list the backplane cluster kube configs", func() {
+
+			err := SetKubeConfigBasePath(kubePath)
+			Expect(err).To(BeNil())
+
+			// The kube config of the test doesn't point to a backplane API server
+			_, err = CreateClusterKubeConfig("not-backplane", kubeConfig)
+			Expect(err).To(BeNil())
+
+			kubeConfig.Clusters["dummy_cluster"].Server = "https://api.backplane.apps.something.com/backplane/cluster/configcluster"
+			path, err := CreateClusterKubeConfig(testClusterID, kubeConfig)
+			Expect(err).To(BeNil())
+
+			kubeConfigs, err := ListClusterKubeConfigs()
+			Expect(err).To(BeNil())
+			Expect(kubeConfigs).To(HaveLen(1))
+			Expect(kubeConfigs[0].ClusterID).To(Equal(testClusterID))
+			Expect(kubeConfigs[0].Path).To(Equal(path))
+			Expect(kubeConfigs[0].ModTime.IsZero()).To(BeFalse())
+		})
+	})
 })
@@ -129,21 +129,13 @@ func (c Client) RunMonitoring(monitoringType string) error {
 		log.Fatalf("connecting to server %v", err)
 	}
 
-	// Add http proxy transport
-	proxyURL, err := getProxyURL()
-	if err != nil {
-		return err
-	}
-	if proxyURL != "" {
-		proxyURL, err := url.Parse(proxyURL)
+	// Add http proxy transport, with the configured TLS settings
+	if c.http.Transport == nil {
+		transport, err := newTransport()
 		if err != nil {
 			return err
 		}
-		http.DefaultTransport = &http.Transport{
-			Proxy: http.ProxyURL(proxyURL),
-		}
-
-		logger.Debugf("Using backplane Proxy URL: %s\n", proxyURL)
+		c.http.Transport = transport
 	}
 
 	req = setProxyRequest(req, mURL, name, accessToken, isGrafana, hasNs, hasAppSelector, hasPort)
@@ -165,6 +157,7 @@ func (c Client) RunMonitoring(monitoringType string) error {
 		Director: func(req *http.Request) {
 			setProxyRequest(req, mURL, name, accessToken, isGrafana, hasNs, hasAppSelector, hasPort)
 		},
+		Transport: c.http.Transport,
 	}
 
 	// serve the proxy
@@ -341,17 +334,19 @@ func serveURL(hasURL, hasNs bool, cfg *restclient.Config) (*url.URL, error) {
 	return serveURL, nil
 }
 
-// getProxyURL returns the proxy url
-func getProxyURL() (proxyURL string, err error) {
+// newTransport returns the http transport through the backplane proxy, with the configured TLS settings
+func newTransport() (*http.Transport, error) {
 	bpConfig, err := config.GetBackplaneConfiguration()
-
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 
-	proxyURL = bpConfig.ProxyURL
+	proxyURL := bpConfig.SelectProxyURL()
+	if proxyURL != "" {
+		logger.Debugf("Using backplane Proxy URL: %s\n", proxyURL)
+	}
 
-	return proxyURL, nil
+	return bpConfig.NewTransport(proxyURL)
 }
 
 // validateClusterVersion checks the clusterversion based on namespace
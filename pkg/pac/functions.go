@@ -0,0 +1,247 @@
+package pac
+
+import (
+	"net"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/dop251/goja"
+	logger "github.com/sirupsen/logrus"
+)
+
+// weekdays are the day names of weekdayRange, in the order of time.Weekday
+var weekdays = []string{"SUN", "MON", "TUE", "WED", "THU", "FRI", "SAT"}
+
+// months are the month names of dateRange, in the order of time.Month
+var months = []string{"JAN", "FEB", "MAR", "APR", "MAY", "JUN", "JUL", "AUG", "SEP", "OCT", "NOV", "DEC"}
+
+// registerPACFunctions defines the standard PAC functions in the runtime
+func registerPACFunctions(runtime *goja.Runtime) error {
+	functions := map[string]interface{}{
+		"isPlainHostName": func(host string) bool {
+			return !strings.Contains(host, ".")
+		},
+		"dnsDomainIs": func(host string, domain string) bool {
+			return strings.HasSuffix(strings.ToLower(host), strings.ToLower(domain))
+		},
+		"localHostOrDomainIs": func(host string, hostDomain string) bool {
+			host, hostDomain = strings.ToLower(host), strings.ToLower(hostDomain)
+			return host == hostDomain || (!strings.Contains(host, ".") && strings.HasPrefix(hostDomain, host+"."))
+		},
+		"isResolvable": func(host string) bool {
+			return resolve(host) != nil
+		},
+		"isInNet": isInNet,
+		"dnsResolve": func(host string) string {
+			if ip := resolve(host); ip != nil {
+				return ip.String()
+			}
+			return ""
+		},
+		"myIpAddress": func() string {
+			return localIP()
+		},
+		"dnsDomainLevels": func(host string) int {
+			return strings.Count(host, ".")
+		},
+		"shExpMatch": shExpMatch,
+		"weekdayRange": func(call goja.FunctionCall) goja.Value {
+			return runtime.ToValue(weekdayRange(call.Arguments))
+		},
+		"dateRange": func(call goja.FunctionCall) goja.Value {
+			return runtime.ToValue(dateRange(call.Arguments))
+		},
+		"timeRange": func(call goja.FunctionCall) goja.Value {
+			return runtime.ToValue(timeRange(call.Arguments))
+		},
+		"alert": func(message string) {
+			logger.Debugf("Proxy auto-config alert: %s", message)
+		},
+	}
+	for name, function := range functions {
+		if err := runtime.Set(name, function); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// shExpMatch matches the string against a shell expression where * matches any characters and ? one
+func shExpMatch(s string, pattern string) bool {
+	expr := regexp.QuoteMeta(pattern)
+	expr = strings.ReplaceAll(expr, `\*`, ".*")
+	expr = strings.ReplaceAll(expr, `\?`, ".")
+	matched, err := regexp.MatchString("^"+expr+"$", s)
+	return err == nil && matched
+}
+
+// isInNet checks the host, resolved if needed, is in the network given by the pattern and the mask
+func isInNet(host string, pattern string, mask string) bool {
+	ip := resolve(host)
+	network := net.ParseIP(pattern).To4()
+	maskIP := net.ParseIP(mask).To4()
+	if ip == nil || network == nil || maskIP == nil {
+		return false
+	}
+	ipMask := net.IPMask(maskIP)
+	return ip.Mask(ipMask).Equal(network.Mask(ipMask))
+}
+
+// resolve returns the IPv4 address of the host, nil when it can't be resolved
+func resolve(host string) net.IP {
+	if ip := net.ParseIP(host); ip != nil {
+		return ip.To4()
+	}
+	ips, err := lookupIP(host)
+	if err != nil {
+		return nil
+	}
+	for _, ip := range ips {
+		if ip4 := ip.To4(); ip4 != nil {
+			return ip4
+		}
+	}
+	return nil
+}
+
+// currentTime returns the arguments of a date or time function without its optional trailing "GMT",
+// and the current time in UTC when it was given, else in the local time zone
+func currentTime(args []goja.Value) ([]goja.Value, time.Time) {
+	t := now()
+	if len(args) > 0 && args[len(args)-1].String() == "GMT" {
+		return args[:len(args)-1], t.UTC()
+	}
+	return args, t.Local()
+}
+
+// inRange checks from <= value <= to, the range wrapping around when from is after to
+func inRange(value, from, to int) bool {
+	if from <= to {
+		return from <= value && value <= to
+	}
+	return value >= from || value <= to
+}
+
+// indexOf returns the index of the name in the list, -1 if it isn't there
+func indexOf(list []string, name string) int {
+	for i, item := range list {
+		if item == strings.ToUpper(name) {
+			return i
+		}
+	}
+	return -1
+}
+
+// weekdayRange checks today is the weekday or in the range of weekdays, eg. weekdayRange("MON", "FRI")
+func weekdayRange(args []goja.Value) bool {
+	args, t := currentTime(args)
+	if len(args) < 1 || len(args) > 2 {
+		return false
+	}
+	from := indexOf(weekdays, args[0].String())
+	to := from
+	if len(args) == 2 {
+		to = indexOf(weekdays, args[1].String())
+	}
+	if from < 0 || to < 0 {
+		return false
+	}
+	return inRange(int(t.Weekday()), from, to)
+}
+
+// dateFields are the fields of a date given to dateRange, zero when not given
+type dateFields struct {
+	day, month, year int
+}
+
+// parseDate reads the day, month name and year given to dateRange. Numbers up to 31 are days, the
+// others years.
+func parseDate(args []goja.Value) (dateFields, bool) {
+	date := dateFields{}
+	for _, arg := range args {
+		if name, ok := arg.Export().(string); ok {
+			month := indexOf(months, name)
+			if month < 0 || date.month != 0 {
+				return date, false
+			}
+			date.month = month + 1
+			continue
+		}
+		n := int(arg.ToInteger())
+		switch {
+		case n >= 1 && n <= 31 && date.day == 0:
+			date.day = n
+		case n > 31 && date.year == 0:
+			date.year = n
+		default:
+			return date, false
+		}
+	}
+	return date, true
+}
+
+// key returns a number ordering the dates by the fields set in the pattern
+func (date dateFields) key(pattern dateFields) int {
+	key := 0
+	if pattern.year != 0 {
+		key += date.year * 10000
+	}
+	if pattern.month != 0 {
+		key += date.month * 100
+	}
+	if pattern.day != 0 {
+		key += date.day
+	}
+	return key
+}
+
+// dateRange checks today is the day, month or year, or in the range between two of them,
+// eg. dateRange(1, "JAN", 15, "JAN") or dateRange("JAN", 2026, "MAR", 2026)
+func dateRange(args []goja.Value) bool {
+	args, t := currentTime(args)
+	today := dateFields{day: t.Day(), month: int(t.Month()), year: t.Year()}
+
+	if len(args) == 1 {
+		date, ok := parseDate(args)
+		return ok && date.key(date) == today.key(date)
+	}
+	if len(args) != 2 && len(args) != 4 && len(args) != 6 {
+		return false
+	}
+	from, okFrom := parseDate(args[:len(args)/2])
+	to, okTo := parseDate(args[len(args)/2:])
+	if !okFrom || !okTo || (from.day == 0) != (to.day == 0) || (from.month == 0) != (to.month == 0) || (from.year == 0) != (to.year == 0) {
+		return false
+	}
+	if from.year != 0 {
+		// Years don't wrap around
+		return from.key(from) <= today.key(from) && today.key(from) <= to.key(from)
+	}
+	return inRange(today.key(from), from.key(from), to.key(from))
+}
+
+// timeRange checks the current time is in the hour, or in the range between two hours, hours and
+// minutes or hours, minutes and seconds, eg. timeRange(8, 18) or timeRange(8, 30, 17, 0)
+func timeRange(args []goja.Value) bool {
+	args, t := currentTime(args)
+	values := make([]int, 0, len(args))
+	for _, arg := range args {
+		values = append(values, int(arg.ToInteger()))
+	}
+	seconds := t.Hour()*3600 + t.Minute()*60 + t.Second()
+
+	switch len(values) {
+	case 1:
+		return t.Hour() == values[0]
+	case 2:
+		// The range ends with the last second of the hour
+		return inRange(seconds, values[0]*3600, values[1]*3600+3599)
+	case 4:
+		return inRange(seconds, values[0]*3600+values[1]*60, values[2]*3600+values[3]*60+59)
+	case 6:
+		return inRange(seconds, values[0]*3600+values[1]*60+values[2], values[3]*3600+values[4]*60+values[5])
+	default:
+		return false
+	}
+}
@@ -0,0 +1,207 @@
+package pac
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dop251/goja"
+)
+
+// Proxy auto-config files are JavaScript, evaluated by an embedded JavaScript engine with the standard
+// PAC functions: isPlainHostName, dnsDomainIs, localHostOrDomainIs, isResolvable, isInNet, dnsResolve,
+// myIpAddress, dnsDomainLevels, shExpMatch, weekdayRange, dateRange, timeRange and alert.
+
+var (
+	// lookupIP resolves the host names of isInNet, isResolvable and dnsResolve
+	lookupIP = net.LookupIP
+
+	// dialProxy checks the proxy accepts connections, to fall back to the next entry of the result
+	dialProxy = func(host string) error {
+		conn, err := net.DialTimeout("tcp", host, 5*time.Second)
+		if err != nil {
+			return err
+		}
+		return conn.Close()
+	}
+
+	// localIP returns the IP address of the machine for myIpAddress
+	localIP = func() string {
+		// No packet is sent, dialing UDP only selects the outgoing interface
+		conn, err := net.Dial("udp", "192.0.2.1:80")
+		if err != nil {
+			return "127.0.0.1"
+		}
+		defer conn.Close()
+		return conn.LocalAddr().(*net.UDPAddr).IP.String()
+	}
+
+	// now returns the current time for weekdayRange, dateRange and timeRange
+	now = time.Now
+
+	// evalTimeout bounds the evaluation of the script, so a script looping forever fails instead of hanging
+	evalTimeout = 5 * time.Second
+)
+
+// Script is a parsed proxy auto-config file
+type Script struct {
+	// mu guards the runtime, which isn't safe for concurrent use, and the selected proxies
+	mu              sync.Mutex
+	runtime         *goja.Runtime
+	findProxyForURL goja.Callable
+
+	// selected is the proxy chosen for each result with several entries
+	selected map[string]*url.URL
+}
+
+// Parse evaluates a proxy auto-config file, which must define a FindProxyForURL function
+func Parse(source string) (*Script, error) {
+	program, err := goja.Compile("proxy auto-config", source, false)
+	if err != nil {
+		return nil, fmt.Errorf("invalid proxy auto-config: %v", err)
+	}
+
+	runtime := goja.New()
+	if err := registerPACFunctions(runtime); err != nil {
+		return nil, err
+	}
+	err = withTimeout(runtime, func() error {
+		_, err := runtime.RunProgram(program)
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("invalid proxy auto-config: %v", err)
+	}
+
+	findProxyForURL, ok := goja.AssertFunction(runtime.Get("FindProxyForURL"))
+	if !ok {
+		return nil, fmt.Errorf("invalid proxy auto-config: no FindProxyForURL function")
+	}
+	return &Script{runtime: runtime, findProxyForURL: findProxyForURL}, nil
+}
+
+// FindProxyForURL evaluates the script for the URL, returning the PAC result like "PROXY host:port; DIRECT"
+func (s *Script) FindProxyForURL(rawURL string, host string) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var value goja.Value
+	err := withTimeout(s.runtime, func() error {
+		var err error
+		value, err = s.findProxyForURL(goja.Undefined(), s.runtime.ToValue(rawURL), s.runtime.ToValue(host))
+		return err
+	})
+	if err != nil {
+		return "", fmt.Errorf("FindProxyForURL failed for %s: %v", rawURL, err)
+	}
+
+	result, ok := value.Export().(string)
+	if !ok {
+		return "", fmt.Errorf("FindProxyForURL must return a string, got %v for %s", value, rawURL)
+	}
+	return result, nil
+}
+
+// withTimeout runs fn, interrupting the script it runs after evalTimeout
+func withTimeout(runtime *goja.Runtime, fn func() error) error {
+	timer := time.AfterFunc(evalTimeout, func() {
+		runtime.Interrupt(fmt.Sprintf("timed out after %v", evalTimeout))
+	})
+	defer func() {
+		timer.Stop()
+		runtime.ClearInterrupt()
+	}()
+	return fn()
+}
+
+// Proxy returns the proxy of the request chosen by the script, nil to connect directly.
+// It can be used as the Proxy of a http.Transport.
+func (s *Script) Proxy(req *http.Request) (*url.URL, error) {
+	result, err := s.FindProxyForURL(req.URL.String(), req.URL.Hostname())
+	if err != nil {
+		return nil, err
+	}
+	return s.selectProxy(result)
+}
+
+// selectProxy returns the first entry of the result accepting connections, DIRECT always does.
+// When none does, the first entry is used so the error is reported by the connection itself.
+// The choice is kept for the next requests with the same result.
+func (s *Script) selectProxy(result string) (*url.URL, error) {
+	proxies, err := ParseResult(result)
+	if err != nil {
+		return nil, err
+	}
+	if len(proxies) == 1 {
+		return proxies[0], nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if proxy, ok := s.selected[result]; ok {
+		return proxy, nil
+	}
+
+	proxy := proxies[0]
+	for _, candidate := range proxies {
+		if candidate == nil || dialProxy(candidate.Host) == nil {
+			proxy = candidate
+			break
+		}
+	}
+	if s.selected == nil {
+		s.selected = map[string]*url.URL{}
+	}
+	s.selected[result] = proxy
+	return proxy, nil
+}
+
+// ParseResult returns the proxies of the entries of a PAC result in order, nil for DIRECT
+func ParseResult(result string) ([]*url.URL, error) {
+	proxies := []*url.URL{}
+	for _, entry := range strings.Split(result, ";") {
+		if strings.TrimSpace(entry) == "" {
+			continue
+		}
+		proxy, err := parseEntry(entry)
+		if err != nil {
+			return nil, err
+		}
+		proxies = append(proxies, proxy)
+	}
+	if len(proxies) == 0 {
+		return nil, fmt.Errorf("empty proxy auto-config result")
+	}
+	return proxies, nil
+}
+
+// parseEntry returns the proxy of an entry of a PAC result, nil for DIRECT
+func parseEntry(entry string) (*url.URL, error) {
+	entry = strings.TrimSpace(entry)
+	fields := strings.Fields(entry)
+
+	kind := strings.ToUpper(fields[0])
+	if kind == "DIRECT" {
+		return nil, nil
+	}
+	if len(fields) != 2 {
+		return nil, fmt.Errorf("invalid proxy auto-config result %q", entry)
+	}
+
+	var scheme string
+	switch kind {
+	case "PROXY", "HTTP":
+		scheme = "http"
+	case "HTTPS":
+		scheme = "https"
+	case "SOCKS", "SOCKS5":
+		scheme = "socks5"
+	default:
+		return nil, fmt.Errorf("unsupported proxy type %s in proxy auto-config result %q", fields[0], entry)
+	}
+	return &url.URL{Scheme: scheme, Host: fields[1]}, nil
+}
@@ -0,0 +1,369 @@
+package pac
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+const testScript = `
+// Corporate proxy auto-config
+function FindProxyForURL(url, host) {
+	/* Local hosts are reached directly */
+	if (isPlainHostName(host) || dnsDomainIs(host, ".corp.example.com"))
+		return "DIRECT";
+
+	if (shExpMatch(host, "*.stage.backplane.example.com") && !localHostOrDomainIs(host, "api.stage.backplane.example.com")) {
+		return 'SOCKS5 localhost:1080';
+	} else if (isInNet(host, "10.0.0.0", "255.0.0.0")) {
+		return "PROXY internal-proxy:3128; DIRECT";
+	}
+
+	if (url == "https://api.backplane.example.com/backplane/login") {
+		return "HTTPS login-proxy:443";
+	}
+	return "PROXY squid.example.com:3128";
+}
+`
+
+func TestFindProxyForURL(t *testing.T) {
+	lookupIP = func(host string) ([]net.IP, error) {
+		if host == "internal.example.com" {
+			return []net.IP{net.ParseIP("10.1.2.3")}, nil
+		}
+		return nil, fmt.Errorf("no such host %s", host)
+	}
+	defer func() { lookupIP = net.LookupIP }()
+	originalDialProxy := dialProxy
+	dialProxy = func(host string) error { return nil }
+	defer func() { dialProxy = originalDialProxy }()
+
+	script, err := Parse(testScript)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		url      string
+		expected string
+	}{
+		{"https://console/", ""},
+		{"https://git.corp.example.com/", ""},
+		{"https://shard.stage.backplane.example.com/", "socks5://localhost:1080"},
+		{"https://api.stage.backplane.example.com/", "http://squid.example.com:3128"},
+		{"https://internal.example.com/", "http://internal-proxy:3128"},
+		{"https://10.4.5.6:6443/", "http://internal-proxy:3128"},
+		{"https://api.backplane.example.com/backplane/login", "https://login-proxy:443"},
+		{"https://api.backplane.example.com/", "http://squid.example.com:3128"},
+	}
+	for _, tt := range tests {
+		req, err := http.NewRequest(http.MethodGet, tt.url, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		proxy, err := script.Proxy(req)
+		if err != nil {
+			t.Errorf("unexpected error for %s: %v", tt.url, err)
+			continue
+		}
+		got := ""
+		if proxy != nil {
+			got = proxy.String()
+		}
+		if got != tt.expected {
+			t.Errorf("expected proxy %q for %s, got %q", tt.expected, tt.url, got)
+		}
+	}
+}
+
+func TestFindProxyForURLWithVariables(t *testing.T) {
+	script, err := Parse(`function FindProxyForURL(url, host) {
+	var direct = "DIRECT", proxy = "PROXY squid.example.com:3128";
+	var internal;
+	if (dnsDomainIs(host, ".corp.example.com")) {
+		internal = true;
+	}
+	if (internal) return direct;
+	return proxy;
+}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for host, expected := range map[string]string{
+		"git.corp.example.com":      "DIRECT",
+		"api.backplane.example.com": "PROXY squid.example.com:3128",
+	} {
+		result, err := script.FindProxyForURL("https://"+host+"/", host)
+		if err != nil {
+			t.Errorf("unexpected error for %s: %v", host, err)
+		}
+		if result != expected {
+			t.Errorf("expected %q for %s, got %q", expected, host, result)
+		}
+	}
+}
+
+func TestProxyFallback(t *testing.T) {
+	dialed := []string{}
+	originalDialProxy := dialProxy
+	dialProxy = func(host string) error {
+		dialed = append(dialed, host)
+		if host == "proxy-2:3128" {
+			return nil
+		}
+		return fmt.Errorf("connection refused")
+	}
+	defer func() { dialProxy = originalDialProxy }()
+
+	tests := []struct {
+		result   string
+		expected string
+	}{
+		{"PROXY proxy-1:3128; PROXY proxy-2:3128; DIRECT", "http://proxy-2:3128"},
+		{"PROXY proxy-1:3128; DIRECT; PROXY proxy-2:3128", ""},
+		{"PROXY proxy-1:3128; PROXY proxy-3:3128", "http://proxy-1:3128"},
+	}
+	for _, tt := range tests {
+		script, err := Parse(`function FindProxyForURL(url, host) { return "` + tt.result + `"; }`)
+		if err != nil {
+			t.Fatal(err)
+		}
+		req, err := http.NewRequest(http.MethodGet, "https://api.backplane.example.com/", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		proxy, err := script.Proxy(req)
+		if err != nil {
+			t.Errorf("unexpected error for %q: %v", tt.result, err)
+			continue
+		}
+		got := ""
+		if proxy != nil {
+			got = proxy.String()
+		}
+		if got != tt.expected {
+			t.Errorf("expected proxy %q for %q, got %q", tt.expected, tt.result, got)
+		}
+
+		// The choice is kept for the next requests
+		dialed = nil
+		if _, err := script.Proxy(req); err != nil || len(dialed) != 0 {
+			t.Errorf("expected the proxy of %q to be kept, dialed %v", tt.result, dialed)
+		}
+	}
+}
+
+func TestFindProxyForURLJavaScript(t *testing.T) {
+	script, err := Parse(`
+var proxies = {
+	"stage": "PROXY stage-proxy.example.com:3128",
+	"production": "PROXY squid.example.com:3128"
+};
+
+function FindProxyForURL(url, host) {
+	host = host.toLowerCase();
+	if (dnsDomainLevels(host) < 2 || url.substring(0, 5) == "http:")
+		return "DIRECT";
+	var environment = host.indexOf(".stage.") >= 0 ? "stage" : "production";
+	for (var i = 0; i < 1; i++) {
+		if (shExpMatch(host, "*.socks.example.com"))
+			return "SOCKS5 " + host.split(".")[0] + ":1080";
+	}
+	switch (environment) {
+	case "stage":
+		return proxies.stage;
+	default:
+		return proxies[environment] + "; DIRECT";
+	}
+}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := map[string]string{
+		"https://console.example/":                  "DIRECT",
+		"http://api.backplane.example.com/":         "DIRECT",
+		"https://API.stage.backplane.example.com/":  "PROXY stage-proxy.example.com:3128",
+		"https://gateway.socks.example.com/":        "SOCKS5 gateway:1080",
+		"https://api.backplane.example.com/cluster": "PROXY squid.example.com:3128; DIRECT",
+	}
+	for rawURL, expected := range tests {
+		req, err := http.NewRequest(http.MethodGet, rawURL, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		result, err := script.FindProxyForURL(rawURL, req.URL.Hostname())
+		if err != nil {
+			t.Errorf("unexpected error for %s: %v", rawURL, err)
+		}
+		if result != expected {
+			t.Errorf("expected %q for %s, got %q", expected, rawURL, result)
+		}
+	}
+}
+
+func TestDateAndTimeFunctions(t *testing.T) {
+	// Wednesday 14 October 2026, 10:30:15 GMT
+	now = func() time.Time { return time.Date(2026, time.October, 14, 10, 30, 15, 0, time.UTC) }
+	defer func() { now = time.Now }()
+
+	tests := map[string]bool{
+		`weekdayRange("WED", "GMT")`:                              true,
+		`weekdayRange("MON", "FRI", "GMT")`:                       true,
+		`weekdayRange("SAT", "SUN", "GMT")`:                       false,
+		`weekdayRange("FRI", "THU", "GMT")`:                       true,
+		`weekdayRange("SUN", "TUE", "GMT")`:                       false,
+		`dateRange(14, "GMT")`:                                    true,
+		`dateRange("OCT", "GMT")`:                                 true,
+		`dateRange(2025, "GMT")`:                                  false,
+		`dateRange(1, 15, "GMT")`:                                 true,
+		`dateRange("NOV", "FEB", "GMT")`:                          false,
+		`dateRange("SEP", "JAN", "GMT")`:                          true,
+		`dateRange(1, "OCT", 13, "OCT", "GMT")`:                   false,
+		`dateRange("OCT", 2026, "MAR", 2027, "GMT")`:              true,
+		`dateRange(15, "SEP", 2026, 14, "OCT", 2026, "GMT")`:      true,
+		`dateRange(2020, 2025, "GMT")`:                            false,
+		`dateRange(1, "OCT", 2026, "GMT")`:                        false,
+		`timeRange(10, "GMT")`:                                    true,
+		`timeRange(8, 10, "GMT")`:                                 true,
+		`timeRange(11, 18, "GMT")`:                                false,
+		`timeRange(22, 11, "GMT")`:                                true,
+		`timeRange(10, 30, 10, 45, "GMT")`:                        true,
+		`timeRange(10, 31, 17, 0, "GMT")`:                         false,
+		`timeRange(10, 30, 10, 10, 30, 20, "GMT")`:                true,
+		`timeRange(10, 30, 0, 10, 30, 14, "GMT")`:                 false,
+		`timeRange(1, 2, 3, "GMT")`:                               false,
+		`dnsDomainLevels("api.backplane.example.com") == 3`:       true,
+		`localHostOrDomainIs("api", "api.backplane.example.com")`: true,
+	}
+	for condition, expected := range tests {
+		script, err := Parse(`function FindProxyForURL(url, host) { return (` + condition + `) ? "PROXY match:1" : "DIRECT"; }`)
+		if err != nil {
+			t.Fatalf("unexpected error for %s: %v", condition, err)
+		}
+		result, err := script.FindProxyForURL("https://example.com/", "example.com")
+		if err != nil {
+			t.Errorf("unexpected error for %s: %v", condition, err)
+			continue
+		}
+		if got := result == "PROXY match:1"; got != expected {
+			t.Errorf("expected %s to be %v", condition, expected)
+		}
+	}
+}
+
+func TestParseInvalid(t *testing.T) {
+	evalTimeout = 100 * time.Millisecond
+	defer func() { evalTimeout = 5 * time.Second }()
+
+	tests := map[string]struct {
+		source string
+		error  string
+	}{
+		"syntax error": {
+			`function FindProxyForURL(url, host) { return "DIRECT" `,
+			"invalid proxy auto-config: SyntaxError",
+		},
+		"no function": {
+			`var proxy = "DIRECT";`,
+			"invalid proxy auto-config: no FindProxyForURL function",
+		},
+		"not a function": {
+			`var FindProxyForURL = "DIRECT";`,
+			"invalid proxy auto-config: no FindProxyForURL function",
+		},
+		"failing script": {
+			`undefinedFunction(); function FindProxyForURL(url, host) { return "DIRECT"; }`,
+			"invalid proxy auto-config: ReferenceError: undefinedFunction is not defined",
+		},
+		"endless script": {
+			`while (true) {} function FindProxyForURL(url, host) { return "DIRECT"; }`,
+			"invalid proxy auto-config: timed out after 100ms",
+		},
+	}
+	for name, tt := range tests {
+		_, err := Parse(tt.source)
+		if err == nil || !strings.Contains(err.Error(), tt.error) {
+			t.Errorf("%s: expected an error with %q, got %v", name, tt.error, err)
+		}
+	}
+}
+
+func TestFindProxyForURLErrors(t *testing.T) {
+	evalTimeout = 100 * time.Millisecond
+	defer func() { evalTimeout = 5 * time.Second }()
+
+	tests := map[string]struct {
+		body  string
+		error string
+	}{
+		"exception": {
+			`throw new Error("no proxy");`,
+			"FindProxyForURL failed for https://example.com/: Error: no proxy",
+		},
+		"not a string": {
+			`return 3128;`,
+			"FindProxyForURL must return a string, got 3128 for https://example.com/",
+		},
+		"no result": {
+			`if (host == "other") return "DIRECT";`,
+			"FindProxyForURL must return a string, got undefined for https://example.com/",
+		},
+		"endless loop": {
+			`for (;;) {}`,
+			"FindProxyForURL failed for https://example.com/: timed out after 100ms",
+		},
+	}
+	for name, tt := range tests {
+		script, err := Parse(`function FindProxyForURL(url, host) { ` + tt.body + ` }`)
+		if err != nil {
+			t.Fatalf("%s: unexpected error %v", name, err)
+		}
+		_, err = script.FindProxyForURL("https://example.com/", "example.com")
+		if err == nil || !strings.Contains(err.Error(), tt.error) {
+			t.Errorf("%s: expected an error with %q, got %v", name, tt.error, err)
+		}
+
+		// The script can be evaluated again after an error
+		if _, err := script.FindProxyForURL("https://other/", "other"); name == "no result" && err != nil {
+			t.Errorf("%s: unexpected error after the failure: %v", name, err)
+		}
+	}
+}
+
+func TestParseResult(t *testing.T) {
+	tests := map[string][]string{
+		"DIRECT":                            {""},
+		"PROXY squid:3128; DIRECT":          {"http://squid:3128", ""},
+		" socks localhost:1080 ":            {"socks5://localhost:1080"},
+		"HTTPS secure-proxy:443;PROXY p:1;": {"https://secure-proxy:443", "http://p:1"},
+	}
+	for result, expected := range tests {
+		proxies, err := ParseResult(result)
+		if err != nil {
+			t.Errorf("unexpected error for %q: %v", result, err)
+			continue
+		}
+		got := []string{}
+		for _, proxy := range proxies {
+			if proxy == nil {
+				got = append(got, "")
+			} else {
+				got = append(got, proxy.String())
+			}
+		}
+		if strings.Join(got, ",") != strings.Join(expected, ",") || len(got) != len(expected) {
+			t.Errorf("expected proxies %q for %q, got %q", expected, result, got)
+		}
+	}
+
+	for _, result := range []string{"", " ; ", "PROXY", "SOCKS4 localhost:1080", "PROXY p:1; HTTP"} {
+		if _, err := ParseResult(result); err == nil {
+			t.Errorf("expected an error for %q", result)
+		}
+	}
+}
@@ -5,7 +5,6 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
-	"net/url"
 
 	BackplaneApi "github.com/openshift/backplane-api/pkg/client"
 	logger "github.com/sirupsen/logrus"
@@ -31,6 +30,41 @@ var (
 	DefaultClientUtils ClientUtils = &DefaultClientUtilsImpl{}
 )
 
+// newRetryingHTTPClient returns the http client retrying the backplane API requests as configured,
+// through the given proxy if any and with the configured TLS settings.
+// The proxy only applies to this client, not to the default transport.
+func newRetryingHTTPClient(proxyURL string) (*RetryingHTTPClient, error) {
+	bpConfig, err := config.GetBackplaneConfiguration()
+	if err != nil {
+		logger.Debugf("Unable to read the backplane configuration, using the default http client settings: %v", err)
+		bpConfig = config.BackplaneConfiguration{MaxRetries: config.DefaultMaxRetries}
+	}
+	client := NewRetryingHTTPClient(bpConfig.MaxRetries)
+
+	transport, err := bpConfig.NewTransport(proxyURL)
+	if err != nil {
+		return nil, err
+	}
+	client.Client.Transport = transport
+	if proxyURL != "" {
+		logger.Debugf("Using backplane Proxy URL: %s\n", proxyURL)
+	}
+
+	return client, nil
+}
+
+// getClientProxyURL returns the proxy set on the client utils, or the configured one
+func (s *DefaultClientUtilsImpl) getClientProxyURL() (string, error) {
+	if s.clientProxyURL == "" {
+		bpConfig, err := config.GetBackplaneConfiguration()
+		if err != nil {
+			return "", err
+		}
+		s.clientProxyURL = bpConfig.SelectProxyURL()
+	}
+	return s.clientProxyURL, nil
+}
+
 func (s *DefaultClientUtilsImpl) MakeRawBackplaneAPIClientWithAccessToken(base, accessToken string) (BackplaneApi.ClientInterface, error) {
 	co := func(client *BackplaneApi.Client) error {
 		client.RequestEditors = append(client.RequestEditors, func(ctx context.Context, req *http.Request) error {
@@ -42,26 +76,17 @@ func (s *DefaultClientUtilsImpl) MakeRawBackplaneAPIClientWithAccessToken(base,
 	}
 
 	// Inject client Proxy Url from config
-	if s.clientProxyURL == "" {
-		bpConfig, err := config.GetBackplaneConfiguration()
-		if err != nil {
-			return nil, err
-		}
-		s.clientProxyURL = bpConfig.ProxyURL
+	proxyURL, err := s.getClientProxyURL()
+	if err != nil {
+		return nil, err
 	}
 
-	// Update http proxy transport
-	if s.clientProxyURL != "" {
-		proxyURL, err := url.Parse(s.clientProxyURL)
-		if err != nil {
-			return nil, err
-		}
-		http.DefaultTransport = &http.Transport{Proxy: http.ProxyURL(proxyURL)}
-
-		logger.Debugf("Using backplane Proxy URL: %s\n", s.clientProxyURL)
+	httpClient, err := newRetryingHTTPClient(proxyURL)
+	if err != nil {
+		return nil, err
 	}
 
-	return BackplaneApi.NewClient(base, co)
+	return BackplaneApi.NewClient(base, co, BackplaneApi.WithHTTPClient(httpClient))
 }
 
 func (s *DefaultClientUtilsImpl) MakeRawBackplaneAPIClient(base string) (BackplaneApi.ClientInterface, error) {
@@ -72,7 +97,7 @@ func (s *DefaultClientUtilsImpl) MakeRawBackplaneAPIClient(base string) (Backpla
 	return s.MakeRawBackplaneAPIClientWithAccessToken(base, *token)
 }
 
-func (*DefaultClientUtilsImpl) MakeBackplaneAPIClientWithAccessToken(base, accessToken string) (BackplaneApi.ClientWithResponsesInterface, error) {
+func (s *DefaultClientUtilsImpl) MakeBackplaneAPIClientWithAccessToken(base, accessToken string) (BackplaneApi.ClientWithResponsesInterface, error) {
 	co := func(client *BackplaneApi.Client) error {
 		client.RequestEditors = append(client.RequestEditors, func(ctx context.Context, req *http.Request) error {
 			req.Header.Add("Authorization", "Bearer "+accessToken)
@@ -82,7 +107,17 @@ func (*DefaultClientUtilsImpl) MakeBackplaneAPIClientWithAccessToken(base, acces
 		return nil
 	}
 
-	return BackplaneApi.NewClientWithResponses(base, co)
+	proxyURL, err := s.getClientProxyURL()
+	if err != nil {
+		return nil, err
+	}
+
+	httpClient, err := newRetryingHTTPClient(proxyURL)
+	if err != nil {
+		return nil, err
+	}
+
+	return BackplaneApi.NewClientWithResponses(base, co, BackplaneApi.WithHTTPClient(httpClient))
 }
 
 func (s *DefaultClientUtilsImpl) MakeBackplaneAPIClient(base string) (BackplaneApi.ClientWithResponsesInterface, error) {
@@ -0,0 +1,46 @@
+package utils
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// ocmEnvironmentNames maps the OCM API hosts to the environment names used in the HCP namespaces
+var ocmEnvironmentNames = map[string]string{
+	"api.openshift.com":             "production",
+	"api.stage.openshift.com":       "staging",
+	"api.integration.openshift.com": "integration",
+}
+
+// HCPNamespaces are the namespaces of a hosted cluster on its management cluster
+type HCPNamespaces struct {
+	// HostedCluster is the namespace of the HostedCluster and NodePool objects
+	HostedCluster string `json:"hosted_cluster"`
+	// HostedControlPlane is the namespace running the hosted control plane
+	HostedControlPlane string `json:"hosted_control_plane"`
+}
+
+// GetOCMEnvironmentName returns the name of the OCM environment of the given OCM API URL
+func GetOCMEnvironmentName(ocmURL string) (string, error) {
+	u, err := url.Parse(ocmURL)
+	if err != nil {
+		return "", fmt.Errorf("invalid OCM URL %s: %v", ocmURL, err)
+	}
+
+	env, ok := ocmEnvironmentNames[strings.ToLower(u.Hostname())]
+	if !ok {
+		return "", fmt.Errorf("unknown OCM environment %s", ocmURL)
+	}
+	return env, nil
+}
+
+// GetHCPNamespaces returns the namespaces of the given hosted cluster on its management cluster,
+// which are ocm-<env>-<clusterID> and ocm-<env>-<clusterID>-<clusterName>
+func GetHCPNamespaces(env, clusterID, clusterName string) HCPNamespaces {
+	hostedClusterNamespace := fmt.Sprintf("ocm-%s-%s", env, clusterID)
+	return HCPNamespaces{
+		HostedCluster:      hostedClusterNamespace,
+		HostedControlPlane: fmt.Sprintf("%s-%s", hostedClusterNamespace, clusterName),
+	}
+}
@@ -0,0 +1,44 @@
+package utils
+
+import (
+	"testing"
+)
+
+func TestGetOCMEnvironmentName(t *testing.T) {
+	tests := []struct {
+		name    string
+		ocmURL  string
+		want    string
+		wantErr bool
+	}{
+		{name: "Production", ocmURL: "https://api.openshift.com", want: "production"},
+		{name: "Staging", ocmURL: "https://api.stage.openshift.com/", want: "staging"},
+		{name: "Integration", ocmURL: "https://api.integration.openshift.com", want: "integration"},
+		{name: "Unknown environment", ocmURL: "https://api.example.com", wantErr: true},
+		{name: "Invalid URL", ocmURL: "://api.openshift.com", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := GetOCMEnvironmentName(tt.ocmURL)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("GetOCMEnvironmentName() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("GetOCMEnvironmentName() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetHCPNamespaces(t *testing.T) {
+	got := GetHCPNamespaces("production", "abc123", "my-cluster")
+	want := HCPNamespaces{
+		HostedCluster:      "ocm-production-abc123",
+		HostedControlPlane: "ocm-production-abc123-my-cluster",
+	}
+	if got != want {
+		t.Errorf("GetHCPNamespaces() got = %+v, want %+v", got, want)
+	}
+}
@@ -1,30 +1,53 @@
 package utils
 
 import (
+	"encoding/json"
 	"fmt"
+	"time"
+
 	"github.com/golang-jwt/jwt/v4"
+	logger "github.com/sirupsen/logrus"
 )
 
-func GetStringFieldFromJWT(token string, field string) (string, error) {
-	var jwtToken *jwt.Token
-	var err error
+// OCMClaims holds the claims of an OCM access token backplane relies on
+type OCMClaims struct {
+	Username  string    `json:"username"`
+	Email     string    `json:"email"`
+	OrgID     string    `json:"org_id"`
+	Issuer    string    `json:"issuer"`
+	ExpiresAt time.Time `json:"expires_at"`
+	IssuedAt  time.Time `json:"issued_at"`
 
-	parser := new(jwt.Parser)
-	jwtToken, _, err = parser.ParseUnverified(token, jwt.MapClaims{})
-	if err != nil {
-		return "", fmt.Errorf("failed to parse jwt")
-	}
+	claims jwt.MapClaims
+}
 
+// ParseOCMClaims decodes the claims of the given OCM access token without verifying its signature
+func ParseOCMClaims(token string) (*OCMClaims, error) {
+	parser := new(jwt.Parser)
+	jwtToken, _, err := parser.ParseUnverified(token, jwt.MapClaims{})
 	if err != nil {
-		return "", err
+		return nil, fmt.Errorf("failed to parse jwt")
 	}
 
 	claims, ok := jwtToken.Claims.(jwt.MapClaims)
 	if !ok {
-		return "", err
+		return nil, fmt.Errorf("failed to parse jwt claims")
 	}
 
-	claim, ok := claims[field]
+	ocmClaims := &OCMClaims{claims: claims}
+	ocmClaims.Username, _ = ocmClaims.StringField("username")
+	ocmClaims.Email, _ = ocmClaims.StringField("email")
+	ocmClaims.OrgID, _ = ocmClaims.StringField("org_id")
+	ocmClaims.Issuer, _ = ocmClaims.StringField("iss")
+	ocmClaims.ExpiresAt, _ = ocmClaims.TimeField("exp")
+	ocmClaims.IssuedAt, _ = ocmClaims.TimeField("iat")
+
+	return ocmClaims, nil
+}
+
+// StringField returns the value of the given string claim
+func (c *OCMClaims) StringField(field string) (string, error) {
+	claim, ok := c.claims[field]
 	if !ok {
 		return "", fmt.Errorf("no field %v on given token", field)
 	}
@@ -36,3 +59,72 @@ func GetStringFieldFromJWT(token string, field string) (string, error) {
 
 	return claimString, nil
 }
+
+// TimeField returns the value of the given numeric date claim
+func (c *OCMClaims) TimeField(field string) (time.Time, error) {
+	claim, ok := c.claims[field]
+	if !ok {
+		return time.Time{}, fmt.Errorf("no field %v on given token", field)
+	}
+
+	switch value := claim.(type) {
+	case float64:
+		return time.Unix(int64(value), 0), nil
+	case json.Number:
+		v, err := value.Int64()
+		if err != nil {
+			return time.Time{}, fmt.Errorf("field %v does not contain a valid timestamp: %v", field, err)
+		}
+		return time.Unix(v, 0), nil
+	default:
+		return time.Time{}, fmt.Errorf("field %v does not contain a numeric value", field)
+	}
+}
+
+// ExpiresIn returns the time left before the token expires, zero if the token has no expiration
+func (c *OCMClaims) ExpiresIn() time.Duration {
+	if c.ExpiresAt.IsZero() {
+		return 0
+	}
+	return time.Until(c.ExpiresAt)
+}
+
+func GetStringFieldFromJWT(token string, field string) (string, error) {
+	claims, err := ParseOCMClaims(token)
+	if err != nil {
+		return "", err
+	}
+
+	return claims.StringField(field)
+}
+
+// GetExpirationFromJWT returns the expiration time stored in the exp claim of the given token
+func GetExpirationFromJWT(token string) (time.Time, error) {
+	claims, err := ParseOCMClaims(token)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	return claims.TimeField("exp")
+}
+
+// WarnIfTokenExpiresSoon logs a warning when the given token expires within the given window
+func WarnIfTokenExpiresSoon(token string, window time.Duration) {
+	if window <= 0 {
+		return
+	}
+
+	claims, err := ParseOCMClaims(token)
+	if err != nil || claims.ExpiresAt.IsZero() {
+		return
+	}
+
+	expiresIn := claims.ExpiresIn()
+	if expiresIn <= 0 {
+		logger.Warnf("Your OCM token expired at %s, please login to OCM again", claims.ExpiresAt.Format(time.RFC3339))
+		return
+	}
+	if expiresIn < window {
+		logger.Warnf("Your OCM token expires in %s, please login to OCM again soon", expiresIn.Round(time.Second))
+	}
+}
@@ -0,0 +1,132 @@
+package utils
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	logger "github.com/sirupsen/logrus"
+	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/client-go/tools/clientcmd/api"
+)
+
+// kubeConfigBackupCount is the number of backups of the previous kubeconfig kept next to it,
+// named <kubeconfig>.backup.1 for the most recent one to <kubeconfig>.backup.<count>
+const kubeConfigBackupCount = 3
+
+// WriteKubeConfig writes the kubeconfig to the given file. The write is atomic, and the
+// kubeconfig lock is held so it doesn't interleave with another backplane kubeconfig write.
+func WriteKubeConfig(path string, config api.Config) error {
+	return withKubeConfigLock(path, func() error {
+		return writeKubeConfigFile(path, config)
+	})
+}
+
+// UpdateKubeConfig reads the kubeconfig of the given file, applies the update and writes it back,
+// holding the kubeconfig lock so concurrent updates don't lose each other's changes.
+// The kubeconfig starts empty if the file doesn't exist, and isn't written if the update fails.
+func UpdateKubeConfig(path string, update func(*api.Config) error) error {
+	return withKubeConfigLock(path, func() error {
+		config, err := clientcmd.LoadFromFile(path)
+		if errors.Is(err, os.ErrNotExist) {
+			config = api.NewConfig()
+		} else if err != nil {
+			return fmt.Errorf("unable to read kubeconfig %s: %v", path, err)
+		}
+
+		if err := update(config); err != nil {
+			return err
+		}
+
+		return writeKubeConfigFile(path, *config)
+	})
+}
+
+// UpdateDefaultKubeConfig is UpdateKubeConfig on the default kubeconfig, honoring the KUBECONFIG variable.
+// When KUBECONFIG lists several files, the update applies to the merged kubeconfig and is written back
+// by client-go to the file each entry comes from, which isn't atomic.
+func UpdateDefaultKubeConfig(update func(*api.Config) error) error {
+	pathOptions := clientcmd.NewDefaultPathOptions()
+	if len(pathOptions.GetLoadingPrecedence()) <= 1 {
+		return UpdateKubeConfig(pathOptions.GetDefaultFilename(), update)
+	}
+
+	return withKubeConfigLock(pathOptions.GetDefaultFilename(), func() error {
+		config, err := pathOptions.GetStartingConfig()
+		if err != nil {
+			return err
+		}
+
+		if err := update(config); err != nil {
+			return err
+		}
+
+		return clientcmd.ModifyConfig(pathOptions, *config, true)
+	})
+}
+
+// withKubeConfigLock runs fn holding an advisory lock on the directory of the given kubeconfig.
+// The directory is locked rather than a lock file next to the kubeconfig, as client-go already
+// creates and removes <kubeconfig>.lock, and the kubeconfig itself is replaced on every write.
+func withKubeConfigLock(path string, fn func() error) error {
+	return WithDirLock(filepath.Dir(path), fn)
+}
+
+// writeKubeConfigFile writes the kubeconfig to a temporary file renamed over the given file,
+// after backing up the previous kubeconfig. Nothing is written if the kubeconfig is unchanged.
+func writeKubeConfigFile(path string, config api.Config) error {
+	// Replace the target of a symlinked kubeconfig, not the symlink
+	if target, err := filepath.EvalSymlinks(path); err == nil {
+		path = target
+	}
+
+	content, err := clientcmd.Write(config)
+	if err != nil {
+		return err
+	}
+
+	previous, err := os.ReadFile(filepath.Clean(path))
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	if len(previous) > 0 {
+		if bytes.Equal(previous, content) {
+			logger.Debugf("Kubeconfig %s unchanged", path)
+			return nil
+		}
+		if err := backupKubeConfig(path, previous); err != nil {
+			return err
+		}
+	}
+
+	if err := WriteFileAtomic(path, content, 0600); err != nil {
+		return err
+	}
+	logger.Debugf("Wrote kubeconfig %s", path)
+
+	return nil
+}
+
+// backupKubeConfig saves the previous kubeconfig content as the most recent backup,
+// and rotates the older backups
+func backupKubeConfig(path string, previous []byte) error {
+	for i := kubeConfigBackupCount - 1; i >= 1; i-- {
+		err := os.Rename(kubeConfigBackupPath(path, i), kubeConfigBackupPath(path, i+1))
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("unable to rotate kubeconfig backups: %v", err)
+		}
+	}
+
+	if err := os.WriteFile(kubeConfigBackupPath(path, 1), previous, 0600); err != nil {
+		return fmt.Errorf("unable to backup kubeconfig: %v", err)
+	}
+
+	return nil
+}
+
+// kubeConfigBackupPath returns the path of the given backup of a kubeconfig
+func kubeConfigBackupPath(path string, index int) string {
+	return fmt.Sprintf("%s.backup.%d", path, index)
+}
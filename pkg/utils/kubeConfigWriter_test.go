@@ -0,0 +1,158 @@
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/client-go/tools/clientcmd/api"
+)
+
+func newTestKubeConfig(contexts ...string) api.Config {
+	config := api.NewConfig()
+	for _, name := range contexts {
+		config.Clusters[name] = &api.Cluster{Server: "https://api.backplane.example.com/backplane/cluster/" + name}
+		config.AuthInfos[name] = &api.AuthInfo{}
+		config.Contexts[name] = &api.Context{Cluster: name, AuthInfo: name}
+		config.CurrentContext = name
+	}
+	return *config
+}
+
+func TestWriteKubeConfig(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config")
+
+	for i := 1; i <= kubeConfigBackupCount+2; i++ {
+		if err := WriteKubeConfig(path, newTestKubeConfig(fmt.Sprintf("ctx%d", i))); err != nil {
+			t.Fatalf("WriteKubeConfig() error = %v", err)
+		}
+	}
+
+	config, err := clientcmd.LoadFromFile(path)
+	if err != nil {
+		t.Fatalf("failed to load kubeconfig: %v", err)
+	}
+	if config.CurrentContext != fmt.Sprintf("ctx%d", kubeConfigBackupCount+2) {
+		t.Errorf("WriteKubeConfig() got current context %s", config.CurrentContext)
+	}
+
+	// The most recent backup holds the previous kubeconfig, and older backups are rotated
+	for i := 1; i <= kubeConfigBackupCount; i++ {
+		backup, err := clientcmd.LoadFromFile(kubeConfigBackupPath(path, i))
+		if err != nil {
+			t.Fatalf("failed to load backup %d: %v", i, err)
+		}
+		if want := fmt.Sprintf("ctx%d", kubeConfigBackupCount+2-i); backup.CurrentContext != want {
+			t.Errorf("backup %d got current context %s, want %s", i, backup.CurrentContext, want)
+		}
+	}
+	if _, err := os.Stat(kubeConfigBackupPath(path, kubeConfigBackupCount+1)); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("expected at most %d backups", kubeConfigBackupCount)
+	}
+
+	// Writing the same kubeconfig doesn't rotate the backups
+	if err := WriteKubeConfig(path, *config); err != nil {
+		t.Fatalf("WriteKubeConfig() error = %v", err)
+	}
+	backup, err := clientcmd.LoadFromFile(kubeConfigBackupPath(path, 1))
+	if err != nil {
+		t.Fatalf("failed to load backup: %v", err)
+	}
+	if want := fmt.Sprintf("ctx%d", kubeConfigBackupCount+1); backup.CurrentContext != want {
+		t.Errorf("unchanged write rotated the backups, got %s, want %s", backup.CurrentContext, want)
+	}
+
+	// No temporary file is left behind
+	entries, err := os.ReadDir(filepath.Dir(path))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != kubeConfigBackupCount+1 {
+		t.Errorf("unexpected files left in the kubeconfig directory: %v", entries)
+	}
+}
+
+func TestWriteKubeConfigSymlink(t *testing.T) {
+	dir := t.TempDir()
+	target := filepath.Join(dir, "real-config")
+	link := filepath.Join(dir, "config")
+	if err := os.WriteFile(target, []byte{}, 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink(target, link); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := WriteKubeConfig(link, newTestKubeConfig("ctx")); err != nil {
+		t.Fatalf("WriteKubeConfig() error = %v", err)
+	}
+
+	info, err := os.Lstat(link)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode()&os.ModeSymlink == 0 {
+		t.Errorf("WriteKubeConfig() replaced the symlink")
+	}
+	config, err := clientcmd.LoadFromFile(target)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if config.CurrentContext != "ctx" {
+		t.Errorf("WriteKubeConfig() didn't write the symlink target")
+	}
+}
+
+func TestUpdateKubeConfig(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config")
+
+	// Concurrent updates don't lose each other's changes
+	var wg sync.WaitGroup
+	errs := make(chan error, 10)
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			errs <- UpdateKubeConfig(path, func(config *api.Config) error {
+				name := fmt.Sprintf("ctx%d", i)
+				config.Contexts[name] = &api.Context{Cluster: name}
+				return nil
+			})
+		}(i)
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if err != nil {
+			t.Fatalf("UpdateKubeConfig() error = %v", err)
+		}
+	}
+
+	config, err := clientcmd.LoadFromFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(config.Contexts) != 10 {
+		t.Errorf("UpdateKubeConfig() got %d contexts, want 10", len(config.Contexts))
+	}
+
+	// A failed update doesn't write the kubeconfig
+	err = UpdateKubeConfig(path, func(config *api.Config) error {
+		config.Contexts = nil
+		return errors.New("update failed")
+	})
+	if err == nil || err.Error() != "update failed" {
+		t.Errorf("UpdateKubeConfig() error = %v, want update failed", err)
+	}
+	config, err = clientcmd.LoadFromFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(config.Contexts) != 10 {
+		t.Errorf("failed UpdateKubeConfig() wrote the kubeconfig")
+	}
+}
@@ -0,0 +1,64 @@
+package utils
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"syscall"
+
+	logger "github.com/sirupsen/logrus"
+)
+
+// WithDirLock runs fn holding an advisory lock on the directory, created if needed. The directory
+// is locked rather than a file in it, as the files written with WriteFileAtomic are replaced.
+func WithDirLock(dir string, fn func() error) error {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+
+	lock, err := os.Open(filepath.Clean(dir))
+	if err != nil {
+		return fmt.Errorf("unable to lock %s: %v", dir, err)
+	}
+	defer lock.Close()
+
+	if err := syscall.Flock(int(lock.Fd()), syscall.LOCK_EX); err != nil {
+		return fmt.Errorf("unable to lock %s: %v", dir, err)
+	}
+	defer func() {
+		if err := syscall.Flock(int(lock.Fd()), syscall.LOCK_UN); err != nil {
+			logger.Debugf("Unable to unlock %s: %v", dir, err)
+		}
+	}()
+
+	return fn()
+}
+
+// WriteFileAtomic writes the content to a temporary file renamed over the given file,
+// so readers never see a partially written file
+func WriteFileAtomic(path string, content []byte, perm os.FileMode) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return err
+	}
+	// Removing the temporary file fails once it's renamed, which is expected
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(content); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Chmod(perm); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), path)
+}
@@ -49,6 +49,21 @@ func (mr *MockOCMInterfaceMockRecorder) GetClusterInfoByID(arg0 interface{}) *go
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetClusterInfoByID", reflect.TypeOf((*MockOCMInterface)(nil).GetClusterInfoByID), arg0)
 }
 
+// GetClusterLimitedSupportReasons mocks base method.
+func (m *MockOCMInterface) GetClusterLimitedSupportReasons(arg0 string) ([]*v1.LimitedSupportReason, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetClusterLimitedSupportReasons", arg0)
+	ret0, _ := ret[0].([]*v1.LimitedSupportReason)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetClusterLimitedSupportReasons indicates an expected call of GetClusterLimitedSupportReasons.
+func (mr *MockOCMInterfaceMockRecorder) GetClusterLimitedSupportReasons(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetClusterLimitedSupportReasons", reflect.TypeOf((*MockOCMInterface)(nil).GetClusterLimitedSupportReasons), arg0)
+}
+
 // GetManagingCluster mocks base method.
 func (m *MockOCMInterface) GetManagingCluster(arg0 string) (string, string, error) {
 	m.ctrl.T.Helper()
@@ -80,6 +95,21 @@ func (mr *MockOCMInterfaceMockRecorder) GetOCMAccessToken() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOCMAccessToken", reflect.TypeOf((*MockOCMInterface)(nil).GetOCMAccessToken))
 }
 
+// GetOCMEnvironmentURL mocks base method.
+func (m *MockOCMInterface) GetOCMEnvironmentURL() (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOCMEnvironmentURL")
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetOCMEnvironmentURL indicates an expected call of GetOCMEnvironmentURL.
+func (mr *MockOCMInterfaceMockRecorder) GetOCMEnvironmentURL() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOCMEnvironmentURL", reflect.TypeOf((*MockOCMInterface)(nil).GetOCMEnvironmentURL))
+}
+
 // GetPullSecret mocks base method.
 func (m *MockOCMInterface) GetPullSecret() (string, error) {
 	m.ctrl.T.Helper()
@@ -157,6 +187,20 @@ func (mr *MockOCMInterfaceMockRecorder) IsClusterHibernating(arg0 interface{}) *
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsClusterHibernating", reflect.TypeOf((*MockOCMInterface)(nil).IsClusterHibernating), arg0)
 }
 
+// IsNonInteractive mocks base method.
+func (m *MockOCMInterface) IsNonInteractive() bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsNonInteractive")
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// IsNonInteractive indicates an expected call of IsNonInteractive.
+func (mr *MockOCMInterfaceMockRecorder) IsNonInteractive() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsNonInteractive", reflect.TypeOf((*MockOCMInterface)(nil).IsNonInteractive))
+}
+
 // IsProduction mocks base method.
 func (m *MockOCMInterface) IsProduction() (bool, error) {
 	m.ctrl.T.Helper()
@@ -171,3 +215,30 @@ func (mr *MockOCMInterfaceMockRecorder) IsProduction() *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsProduction", reflect.TypeOf((*MockOCMInterface)(nil).IsProduction))
 }
+
+// SearchClusters mocks base method.
+func (m *MockOCMInterface) SearchClusters(arg0 string) ([]*v1.Cluster, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SearchClusters", arg0)
+	ret0, _ := ret[0].([]*v1.Cluster)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SearchClusters indicates an expected call of SearchClusters.
+func (mr *MockOCMInterfaceMockRecorder) SearchClusters(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SearchClusters", reflect.TypeOf((*MockOCMInterface)(nil).SearchClusters), arg0)
+}
+
+// SetNonInteractive mocks base method.
+func (m *MockOCMInterface) SetNonInteractive(arg0 bool) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetNonInteractive", arg0)
+}
+
+// SetNonInteractive indicates an expected call of SetNonInteractive.
+func (mr *MockOCMInterfaceMockRecorder) SetNonInteractive(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetNonInteractive", reflect.TypeOf((*MockOCMInterface)(nil).SetNonInteractive), arg0)
+}
@@ -9,6 +9,8 @@ import (
 	logger "github.com/sirupsen/logrus"
 
 	"gopkg.in/AlecAivazis/survey.v1"
+
+	"github.com/openshift/backplane-cli/pkg/cli/config"
 )
 
 // OCM Wrapper to abstract ocm sdk interface
@@ -19,14 +21,37 @@ type OCMInterface interface {
 	GetTargetCluster(clusterKey string) (clusterID, clusterName string, err error)
 	GetManagingCluster(clusterKey string) (clusterID, clusterName string, err error)
 	GetOCMAccessToken() (*string, error)
+	GetOCMEnvironmentURL() (string, error)
 	GetServiceCluster(clusterKey string) (clusterID, clusterName string, err error)
 	GetClusterInfoByID(clusterID string) (*cmv1.Cluster, error)
+	GetClusterLimitedSupportReasons(clusterID string) ([]*cmv1.LimitedSupportReason, error)
 	IsProduction() (bool, error)
 	GetPullSecret() (string, error)
 	GetStsSupportJumpRoleARN(clusterID string) (string, error)
+	SearchClusters(clusterKey string) ([]*cmv1.Cluster, error)
+	SetNonInteractive(nonInteractive bool)
+	IsNonInteractive() bool
+}
+
+type DefaultOCMInterfaceImpl struct {
+	nonInteractive bool
 }
 
-type DefaultOCMInterfaceImpl struct{}
+// AmbiguousClusterError is returned instead of asking the user to choose
+// when the search key matches several clusters in non-interactive mode
+type AmbiguousClusterError struct {
+	ClusterKey string
+	Clusters   []*cmv1.Cluster
+}
+
+func (e *AmbiguousClusterError) Error() string {
+	candidates := []string{}
+	for _, v := range e.Clusters {
+		candidates = append(candidates, fmt.Sprintf("  Name: %s, ID: %s", v.Name(), v.ID()))
+	}
+	return fmt.Sprintf("cluster key '%s' matches %d clusters, please use one of the cluster IDs:\n%s",
+		e.ClusterKey, len(e.Clusters), strings.Join(candidates, "\n"))
+}
 
 var DefaultOCMInterface OCMInterface = &DefaultOCMInterfaceImpl{}
 
@@ -46,19 +71,39 @@ func (*DefaultOCMInterfaceImpl) IsClusterHibernating(clusterID string) (bool, er
 	return cluster.Status().State() == cmv1.ClusterStateHibernating, nil
 }
 
-// GetTargetCluster returns one single cluster based on the search key and survery.
-func (*DefaultOCMInterfaceImpl) GetTargetCluster(clusterKey string) (clusterID, clusterName string, err error) {
+// SetNonInteractive disables the survey when the search key matches several clusters
+func (o *DefaultOCMInterfaceImpl) SetNonInteractive(nonInteractive bool) {
+	o.nonInteractive = nonInteractive
+}
+
+// IsNonInteractive tells whether the prompts choosing a cluster are disabled
+func (o *DefaultOCMInterfaceImpl) IsNonInteractive() bool {
+	return o.nonInteractive
+}
+
+// SearchClusters returns the clusters matching the search key
+func (*DefaultOCMInterfaceImpl) SearchClusters(clusterKey string) ([]*cmv1.Cluster, error) {
 	// Create the client for the OCM API:
 	connection, err := ocm.NewConnection().Build()
 	if err != nil {
-		return "", "", fmt.Errorf("failed to create OCM connection: %v", err)
+		return nil, fmt.Errorf("failed to create OCM connection: %v", err)
 	}
 	defer connection.Close()
 	// Get the client for the resource that manages the collection of clusters:
 	clusterCollection := connection.ClustersMgmt().V1().Clusters()
 	clusters, err := getClusters(clusterCollection, clusterKey)
 	if err != nil {
-		return "", "", fmt.Errorf("failed to get cluster '%s': %v", clusterKey, err)
+		return nil, fmt.Errorf("failed to get cluster '%s': %v", clusterKey, err)
+	}
+	return clusters, nil
+}
+
+// GetTargetCluster returns one single cluster based on the search key and survery.
+// In non-interactive mode, an AmbiguousClusterError is returned instead of the survey.
+func (o *DefaultOCMInterfaceImpl) GetTargetCluster(clusterKey string) (clusterID, clusterName string, err error) {
+	clusters, err := o.SearchClusters(clusterKey)
+	if err != nil {
+		return "", "", err
 	}
 
 	if len(clusters) == 1 {
@@ -66,6 +111,8 @@ func (*DefaultOCMInterfaceImpl) GetTargetCluster(clusterKey string) (clusterID,
 			clusterID = v.ID()
 			clusterName = v.Name()
 		}
+	} else if o.nonInteractive {
+		return "", "", &AmbiguousClusterError{ClusterKey: clusterKey, Clusters: clusters}
 	} else {
 		cluster, err := doSurvey(clusters)
 		if err != nil {
@@ -196,17 +243,36 @@ func (*DefaultOCMInterfaceImpl) GetOCMAccessToken() (*string, error) {
 		return nil, fmt.Errorf("failed to create OCM connection: %v", err)
 	}
 	defer connection.Close()
+
+	// The SDK refreshes the token with its default margin, the warning window only applies to the warning
 	accessToken, _, err := connection.Tokens()
 	if err != nil {
 		return nil, err
 	}
 
+	expiryWarning, err := config.GetTokenExpiryWarning()
+	if err != nil {
+		logger.Debugf("Unable to read the token expiry warning, using the default %v: %v", expiryWarning, err)
+	}
+
 	logger.Debugln("Found OCM access token")
 	accessToken = strings.TrimSuffix(accessToken, "\n")
+	WarnIfTokenExpiresSoon(accessToken, expiryWarning)
 
 	return &accessToken, nil
 }
 
+// GetOCMEnvironmentURL returns the API URL of the OCM environment the user is logged into
+func (*DefaultOCMInterfaceImpl) GetOCMEnvironmentURL() (string, error) {
+	connection, err := ocm.NewConnection().Build()
+	if err != nil {
+		return "", fmt.Errorf("failed to create OCM connection: %v", err)
+	}
+	defer connection.Close()
+
+	return connection.URL(), nil
+}
+
 // GetPullSecret returns pull secret from OCM
 func (*DefaultOCMInterfaceImpl) GetPullSecret() (string, error) {
 
@@ -250,6 +316,23 @@ func (*DefaultOCMInterfaceImpl) GetClusterInfoByID(clusterID string) (*cmv1.Clus
 	return cluster, nil
 }
 
+// GetClusterLimitedSupportReasons calls the OCM to retrieve the limited support reasons
+// for a given internal cluster id.
+func (*DefaultOCMInterfaceImpl) GetClusterLimitedSupportReasons(clusterID string) ([]*cmv1.LimitedSupportReason, error) {
+	// Create the client for the OCM API:
+	connection, err := ocm.NewConnection().Build()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create OCM connection: %v", err)
+	}
+	defer connection.Close()
+
+	response, err := connection.ClustersMgmt().V1().Clusters().Cluster(clusterID).LimitedSupportReasons().List().Send()
+	if err != nil {
+		return nil, fmt.Errorf("can't retrieve limited support reasons for cluster '%s': %v", clusterID, err)
+	}
+	return response.Items().Slice(), nil
+}
+
 // IsProduction checks if OCM is currently in production env
 func (*DefaultOCMInterfaceImpl) IsProduction() (bool, error) {
 	// Create the client for the OCM API:
@@ -0,0 +1,47 @@
+package utils
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+)
+
+func TestAmbiguousClusterError(t *testing.T) {
+	cluster1, _ := cmv1.NewCluster().ID("id1").Name("test-1").Build()
+	cluster2, _ := cmv1.NewCluster().ID("id2").Name("test-2").Build()
+
+	var err error = &AmbiguousClusterError{
+		ClusterKey: "test%",
+		Clusters:   []*cmv1.Cluster{cluster1, cluster2},
+	}
+
+	var ambiguousErr *AmbiguousClusterError
+	if !errors.As(err, &ambiguousErr) {
+		t.Fatalf("expected an AmbiguousClusterError")
+	}
+	if len(ambiguousErr.Clusters) != 2 {
+		t.Errorf("expected 2 candidates, got %d", len(ambiguousErr.Clusters))
+	}
+
+	for _, want := range []string{"test%", "Name: test-1, ID: id1", "Name: test-2, ID: id2"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("expected error %q to contain %q", err.Error(), want)
+		}
+	}
+}
+
+func TestSetNonInteractive(t *testing.T) {
+	o := &DefaultOCMInterfaceImpl{}
+
+	o.SetNonInteractive(true)
+	if !o.nonInteractive || !o.IsNonInteractive() {
+		t.Errorf("expected non-interactive mode to be enabled")
+	}
+
+	o.SetNonInteractive(false)
+	if o.nonInteractive || o.IsNonInteractive() {
+		t.Errorf("expected non-interactive mode to be disabled")
+	}
+}
@@ -0,0 +1,204 @@
+package utils
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	logger "github.com/sirupsen/logrus"
+)
+
+const (
+	// defaultRetryInitialBackoff is the backoff before the first retry, doubled for each retry
+	defaultRetryInitialBackoff = 500 * time.Millisecond
+
+	// defaultRetryMaxBackoff caps the backoff between two retries, including the one asked by Retry-After
+	defaultRetryMaxBackoff = 30 * time.Second
+)
+
+var (
+	// retryableStatusCodes are the transient failures of the backplane API and its load balancer
+	retryableStatusCodes = map[int]bool{
+		http.StatusTooManyRequests:    true,
+		http.StatusBadGateway:         true,
+		http.StatusServiceUnavailable: true,
+		http.StatusGatewayTimeout:     true,
+	}
+
+	// retryablePostPaths are the POST endpoints which don't create anything, so are safe to retry.
+	// They are matched anywhere in the path as the backplane URL can have a path prefix.
+	// Job creation must never be retried as it would run the job twice.
+	retryablePostPaths = []string{
+		"/backplane/login/",
+	}
+)
+
+// RetryingHTTPClient is a backplane API http client which retries idempotent requests
+// failing with a transient error, with an exponential backoff and jitter
+type RetryingHTTPClient struct {
+	Client         *http.Client
+	MaxRetries     int
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+
+	sleep func(ctx context.Context, d time.Duration) error
+}
+
+// NewRetryingHTTPClient returns a http client retrying at most maxRetries times
+func NewRetryingHTTPClient(maxRetries int) *RetryingHTTPClient {
+	return &RetryingHTTPClient{
+		Client:         &http.Client{},
+		MaxRetries:     maxRetries,
+		InitialBackoff: defaultRetryInitialBackoff,
+		MaxBackoff:     defaultRetryMaxBackoff,
+		sleep:          sleepWithContext,
+	}
+}
+
+// Do sends the request, and retries it on a transient failure if it is safe to
+func (c *RetryingHTTPClient) Do(req *http.Request) (*http.Response, error) {
+	retryable := isRetryableRequest(req)
+
+	for attempt := 0; ; attempt++ {
+		if attempt > 0 && req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			req.Body = body
+		}
+
+		resp, err := c.Client.Do(req)
+
+		if !retryable || attempt >= c.MaxRetries || !isTransientFailure(resp, err) {
+			return resp, err
+		}
+
+		backoff := c.backoff(attempt, resp)
+		if err != nil {
+			logger.Debugf("Retrying %s %s in %s (%d/%d): %v", req.Method, req.URL.Path, backoff, attempt+1, c.MaxRetries, err)
+		} else {
+			logger.Debugf("Retrying %s %s in %s (%d/%d): %s", req.Method, req.URL.Path, backoff, attempt+1, c.MaxRetries, resp.Status)
+			// Drain the body so the connection can be reused
+			_, _ = io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+		}
+
+		if err := c.sleep(req.Context(), backoff); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// backoff returns the time to wait before the next retry. The Retry-After header of the
+// response is honored, otherwise the backoff grows exponentially with a random jitter.
+func (c *RetryingHTTPClient) backoff(attempt int, resp *http.Response) time.Duration {
+	if resp != nil {
+		if retryAfter, ok := parseRetryAfter(resp.Header.Get("Retry-After")); ok {
+			if retryAfter > c.MaxBackoff {
+				return c.MaxBackoff
+			}
+			return retryAfter
+		}
+	}
+
+	backoff := c.InitialBackoff << attempt
+	if backoff <= 0 || backoff > c.MaxBackoff {
+		backoff = c.MaxBackoff
+	}
+
+	// Wait between half and the full backoff, so concurrent clients don't retry all at once
+	half := backoff / 2
+	if half <= 0 {
+		return backoff
+	}
+	return half + time.Duration(rand.Int63n(int64(half)+1)) // #nosec G404
+}
+
+// isRetryableRequest checks whether the request can be sent several times safely
+func isRetryableRequest(req *http.Request) bool {
+	// The body can't be sent again
+	if req.Body != nil && req.Body != http.NoBody && req.GetBody == nil {
+		return false
+	}
+
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	case http.MethodPost:
+		for _, path := range retryablePostPaths {
+			if strings.Contains(req.URL.Path, path) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// isTransientFailure checks whether the request failed with an error worth retrying
+func isTransientFailure(resp *http.Response, err error) bool {
+	if err != nil {
+		// The request was cancelled by the caller
+		if errors.Is(err, context.Canceled) {
+			return false
+		}
+
+		var netErr net.Error
+		if errors.As(err, &netErr) && netErr.Timeout() {
+			return true
+		}
+
+		return errors.Is(err, syscall.ECONNRESET) ||
+			errors.Is(err, syscall.ECONNREFUSED) ||
+			errors.Is(err, io.EOF) ||
+			errors.Is(err, io.ErrUnexpectedEOF)
+	}
+
+	return retryableStatusCodes[resp.StatusCode]
+}
+
+// parseRetryAfter parses a Retry-After header, given either in seconds or as a http date
+func parseRetryAfter(value string) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0, false
+		}
+		return time.Duration(seconds) * time.Second, true
+	}
+
+	if date, err := http.ParseTime(value); err == nil {
+		retryAfter := time.Until(date)
+		if retryAfter < 0 {
+			retryAfter = 0
+		}
+		return retryAfter, true
+	}
+
+	return 0, false
+}
+
+// sleepWithContext waits for the given duration, or until the context is done
+func sleepWithContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return fmt.Errorf("request cancelled while waiting to retry: %w", ctx.Err())
+	case <-timer.C:
+		return nil
+	}
+}
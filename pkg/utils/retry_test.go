@@ -0,0 +1,225 @@
+package utils
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// newTestRetryingHTTPClient returns a retrying client recording its backoffs instead of sleeping
+func newTestRetryingHTTPClient(maxRetries int, backoffs *[]time.Duration) *RetryingHTTPClient {
+	c := NewRetryingHTTPClient(maxRetries)
+	c.InitialBackoff = 100 * time.Millisecond
+	c.MaxBackoff = time.Second
+	c.sleep = func(ctx context.Context, d time.Duration) error {
+		*backoffs = append(*backoffs, d)
+		return nil
+	}
+	return c
+}
+
+func TestRetryingHTTPClient(t *testing.T) {
+	tests := []struct {
+		name         string
+		method       string
+		path         string
+		statusCodes  []int
+		retryAfter   string
+		maxRetries   int
+		wantStatus   int
+		wantRequests int32
+	}{
+		{
+			name:         "Retries a GET until it succeeds",
+			method:       http.MethodGet,
+			path:         "/backplane/script/cluster/job",
+			statusCodes:  []int{http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusOK},
+			maxRetries:   3,
+			wantStatus:   http.StatusOK,
+			wantRequests: 3,
+		},
+		{
+			name:         "Gives up after the max retries",
+			method:       http.MethodGet,
+			path:         "/backplane/cloud/credentials/cluster",
+			statusCodes:  []int{http.StatusGatewayTimeout, http.StatusGatewayTimeout, http.StatusGatewayTimeout},
+			maxRetries:   2,
+			wantStatus:   http.StatusGatewayTimeout,
+			wantRequests: 3,
+		},
+		{
+			name:         "Retries the login",
+			method:       http.MethodPost,
+			path:         "/backplane/login/cluster/",
+			statusCodes:  []int{http.StatusServiceUnavailable, http.StatusOK},
+			maxRetries:   3,
+			wantStatus:   http.StatusOK,
+			wantRequests: 2,
+		},
+		{
+			name:         "Retries the login behind a path prefix",
+			method:       http.MethodPost,
+			path:         "/api/v1/backplane/login/cluster/",
+			statusCodes:  []int{http.StatusServiceUnavailable, http.StatusOK},
+			maxRetries:   3,
+			wantStatus:   http.StatusOK,
+			wantRequests: 2,
+		},
+		{
+			name:         "Never retries a job creation behind a path prefix",
+			method:       http.MethodPost,
+			path:         "/api/v1/backplane/script/cluster/job",
+			statusCodes:  []int{http.StatusServiceUnavailable, http.StatusOK},
+			maxRetries:   3,
+			wantStatus:   http.StatusServiceUnavailable,
+			wantRequests: 1,
+		},
+		{
+			name:         "Never retries a job creation",
+			method:       http.MethodPost,
+			path:         "/backplane/script/cluster/job",
+			statusCodes:  []int{http.StatusServiceUnavailable, http.StatusOK},
+			maxRetries:   3,
+			wantStatus:   http.StatusServiceUnavailable,
+			wantRequests: 1,
+		},
+		{
+			name:         "Doesn't retry a client error",
+			method:       http.MethodGet,
+			path:         "/backplane/script/cluster",
+			statusCodes:  []int{http.StatusNotFound, http.StatusOK},
+			maxRetries:   3,
+			wantStatus:   http.StatusNotFound,
+			wantRequests: 1,
+		},
+		{
+			name:         "Doesn't retry when disabled",
+			method:       http.MethodGet,
+			path:         "/backplane/script/cluster",
+			statusCodes:  []int{http.StatusBadGateway, http.StatusOK},
+			maxRetries:   0,
+			wantStatus:   http.StatusBadGateway,
+			wantRequests: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var requests int32
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				i := atomic.AddInt32(&requests, 1) - 1
+				w.WriteHeader(tt.statusCodes[i])
+			}))
+			defer server.Close()
+
+			backoffs := []time.Duration{}
+			client := newTestRetryingHTTPClient(tt.maxRetries, &backoffs)
+
+			req, err := http.NewRequest(tt.method, server.URL+tt.path, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			resp, err := client.Do(req)
+			if err != nil {
+				t.Fatalf("Do() error = %v", err)
+			}
+			resp.Body.Close()
+
+			if resp.StatusCode != tt.wantStatus {
+				t.Errorf("Do() status = %d, want %d", resp.StatusCode, tt.wantStatus)
+			}
+			if requests != tt.wantRequests {
+				t.Errorf("Do() sent %d requests, want %d", requests, tt.wantRequests)
+			}
+			if len(backoffs) != int(tt.wantRequests)-1 {
+				t.Errorf("Do() waited %d times, want %d", len(backoffs), tt.wantRequests-1)
+			}
+		})
+	}
+}
+
+func TestRetryingHTTPClientBackoff(t *testing.T) {
+	backoffs := []time.Duration{}
+	client := newTestRetryingHTTPClient(10, &backoffs)
+
+	for attempt := 0; attempt < 6; attempt++ {
+		backoff := client.backoff(attempt, nil)
+		max := client.InitialBackoff << attempt
+		if max > client.MaxBackoff {
+			max = client.MaxBackoff
+		}
+		if backoff < max/2 || backoff > max {
+			t.Errorf("backoff(%d) = %s, want between %s and %s", attempt, backoff, max/2, max)
+		}
+	}
+}
+
+func TestRetryingHTTPClientRetryAfter(t *testing.T) {
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&requests, 1) == 1 {
+			w.Header().Set("Retry-After", "1")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	backoffs := []time.Duration{}
+	client := newTestRetryingHTTPClient(3, &backoffs)
+
+	req, err := http.NewRequest(http.MethodPut, server.URL, strings.NewReader("body"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatalf("Do() error = %v", err)
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("Do() status = %d, want %d", resp.StatusCode, http.StatusOK)
+	}
+	if len(backoffs) != 1 || backoffs[0] != time.Second {
+		t.Errorf("Do() backoffs = %v, want [1s]", backoffs)
+	}
+}
+
+func TestRetryingHTTPClientConnectionError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	url := server.URL
+	server.Close()
+
+	backoffs := []time.Duration{}
+	client := newTestRetryingHTTPClient(2, &backoffs)
+
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = client.Do(req)
+	if err == nil {
+		t.Fatalf("Do() expected an error")
+	}
+	if len(backoffs) != 2 {
+		t.Errorf("Do() waited %d times, want 2", len(backoffs))
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	if d, ok := parseRetryAfter("5"); !ok || d != 5*time.Second {
+		t.Errorf("parseRetryAfter(5) = %s, %v", d, ok)
+	}
+	if d, ok := parseRetryAfter(time.Now().Add(time.Minute).UTC().Format(http.TimeFormat)); !ok || d <= 0 || d > time.Minute {
+		t.Errorf("parseRetryAfter(date) = %s, %v", d, ok)
+	}
+	if _, ok := parseRetryAfter("soon"); ok {
+		t.Errorf("parseRetryAfter(soon) expected to fail")
+	}
+}
@@ -1,6 +1,7 @@
 package utils
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -18,7 +19,6 @@ import (
 
 	"github.com/spf13/cobra"
 	"k8s.io/cli-runtime/pkg/genericclioptions"
-	"k8s.io/client-go/tools/clientcmd"
 	"k8s.io/client-go/tools/clientcmd/api"
 
 	"github.com/openshift/backplane-cli/internal/github"
@@ -176,13 +176,12 @@ func CreateTempKubeConfig(kubeConfig *api.Config) error {
 	if kubeConfig == nil {
 		kubeConfig = &defaultKubeConfig
 	}
-	err = clientcmd.WriteToFile(*kubeConfig, f.Name())
-
+	err = f.Close()
 	if err != nil {
 		return err
 	}
 
-	err = f.Close()
+	err = WriteKubeConfig(f.Name(), *kubeConfig)
 	if err != nil {
 		return err
 	}
@@ -209,9 +208,28 @@ func RemoveTempKubeConfig() {
 	path, found := os.LookupEnv("KUBECONFIG")
 	if found {
 		os.Remove(path)
+		for i := 1; i <= kubeConfigBackupCount; i++ {
+			os.Remove(kubeConfigBackupPath(path, i))
+		}
 	}
 }
 
+// GetLatestBackplaneVersion returns the version of the latest backplane-cli release on GitHub, without the v prefix
+func GetLatestBackplaneVersion(ctx context.Context) (string, error) {
+	git := github.NewClient()
+	if err := git.CheckConnection(); err != nil {
+		return "", fmt.Errorf("could not connect to GitHub: %v", err)
+	}
+
+	// Get the latest version from the GitHub API
+	latestVersionTag, err := git.GetLatestVersion(ctx)
+	if err != nil {
+		return "", err
+	}
+	// GitHub API keeps the v prefix in front which causes mismatch with info.Version
+	return strings.TrimLeft(latestVersionTag.TagName, "v"), nil
+}
+
 // CheckBackplaneVersion checks the backplane version and aims to only
 // report any errors encountered in the process in order to
 // avoid calling functions act as usual
@@ -227,20 +245,11 @@ func CheckBackplaneVersion(cmd *cobra.Command) {
 		return
 	}
 
-	git := github.NewClient()
-	if err := git.CheckConnection(); err != nil {
-		logger.WithField("Connection error", err).Warn("Could not connect to GitHub")
-		return
-	}
-
-	// Get the latest version from the GitHub API
-	latestVersionTag, err := git.GetLatestVersion(ctx)
+	latestVersion, err := GetLatestBackplaneVersion(ctx)
 	if err != nil {
-		logger.WithField("Fetch error", err).Warn("Could not fetch latest version from GitHub")
+		logger.WithField("Error", err).Warn("Could not fetch latest version from GitHub")
 		return
 	}
-	// GitHub API keeps the v prefix in front which causes mismatch with info.Version
-	latestVersion := strings.TrimLeft(latestVersionTag.TagName, "v")
 
 	// Check if the local version is already up-to-date
 	if latestVersion == info.Version {